@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/runtime"
+)
+
+// credentialParamsKey is the context key of the query parameters set by the authentication of a request.
+type credentialParamsKey struct{}
+
+// credentialRecorder records the query parameters set by a [runtime.ClientAuthInfoWriter], e.g. API keys.
+type credentialRecorder struct {
+	runtime.ClientRequest
+
+	params *[]string
+}
+
+func (c credentialRecorder) SetQueryParam(name string, values ...string) error {
+	*c.params = append(*c.params, name)
+
+	return c.ClientRequest.SetQueryParam(name, values...)
+}
+
+// recordCredentialParams wraps the authentication of a request, to record the query parameters it sets.
+func recordCredentialParams(auth runtime.ClientAuthInfoWriter, params *[]string) runtime.ClientAuthInfoWriter {
+	return runtime.ClientAuthInfoWriterFunc(func(req runtime.ClientRequest, reg strfmt.Registry) error {
+		return auth.AuthenticateRequest(credentialRecorder{ClientRequest: req, params: params}, reg)
+	})
+}
+
+// withCredentialParams records the query parameters carrying credentials in the context of a request.
+func withCredentialParams(req *http.Request, params []string) *http.Request {
+	if len(params) == 0 {
+		return req
+	}
+
+	return req.WithContext(context.WithValue(req.Context(), credentialParamsKey{}, params))
+}
+
+// redactedURL returns the URL of a request without its credentials: the password of its user info
+// and the query parameters set by its authentication.
+func redactedURL(req *http.Request) string {
+	target := *req.URL
+	if params, _ := req.Context().Value(credentialParamsKey{}).([]string); len(params) > 0 {
+		query := target.Query()
+		for _, name := range params {
+			query.Del(name)
+		}
+		target.RawQuery = query.Encode()
+	}
+
+	return target.Redacted()
+}
+
+// capturingBody records the first bytes read from a response body,
+// so they remain available after the response reader is done with it.
+type capturingBody struct {
+	io.ReadCloser
+
+	limit     int64
+	buf       bytes.Buffer
+	truncated bool
+}
+
+func newCapturingBody(body io.ReadCloser, limit int64) *capturingBody {
+	return &capturingBody{
+		ReadCloser: body,
+		limit:      limit,
+	}
+}
+
+func (c *capturingBody) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	if n > 0 {
+		c.capture(p[:n])
+	}
+
+	return n, err
+}
+
+func (c *capturingBody) capture(p []byte) {
+	room := c.limit - int64(c.buf.Len())
+	if room <= 0 {
+		if len(p) > 0 {
+			c.truncated = true
+		}
+
+		return
+	}
+
+	if int64(len(p)) > room {
+		p = p[:room]
+		c.truncated = true
+	}
+	_, _ = c.buf.Write(p)
+}
+
+// fill reads what remains of the body, up to the limit.
+//
+// An extra byte is read to determine whether the body is truncated.
+func (c *capturingBody) fill() {
+	if c.truncated {
+		return
+	}
+
+	room := c.limit - int64(c.buf.Len())
+	_, _ = io.Copy(io.Discard, io.LimitReader(c, room+1))
+}
+
+// bufferedResponse is a [runtime.ClientResponse] which body has been captured.
+type bufferedResponse struct {
+	runtime.ClientResponse
+
+	body []byte
+}
+
+func (r bufferedResponse) Body() io.ReadCloser {
+	return io.NopCloser(bytes.NewReader(r.body))
+}
+
+// enrichAPIError completes an [runtime.APIError] with the details of the unexpected response.
+func enrichAPIError(apiErr *runtime.APIError, req *http.Request, res *http.Response, body *capturingBody, cons runtime.Consumer) {
+	body.fill()
+
+	apiErr.Method = req.Method
+	apiErr.URL = redactedURL(req)
+	apiErr.Header = res.Header.Clone()
+	apiErr.Body = bytes.Clone(body.buf.Bytes())
+	apiErr.BodyTruncated = body.truncated
+
+	resp, isResponse := apiErr.Response.(runtime.ClientResponse)
+	if !isResponse {
+		return
+	}
+
+	// the original response body is no longer available: callers may read the captured bytes instead
+	apiErr.Response = bufferedResponse{ClientResponse: resp, body: apiErr.Body}
+
+	if apiErr.BodyTruncated || len(apiErr.Body) == 0 || cons == nil {
+		return
+	}
+
+	var payload any
+	if err := cons.Consume(bytes.NewReader(apiErr.Body), &payload); err == nil {
+		apiErr.Payload = payload
+	}
+}
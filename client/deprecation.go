@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/runtime"
+)
+
+// DeprecationNotice describes the deprecation or the sunset of an operation, as announced by
+// the Deprecation (RFC 9745), Sunset (RFC 8594) and Link headers of a response.
+//
+// It is passed to [Runtime.DeprecationWarning].
+type DeprecationNotice struct {
+	OperationID string
+	Method      string
+	URL         string // redacted like the URL of a [runtime.APIError]
+
+	// Deprecated is true when the response carries a Deprecation header.
+	Deprecated bool
+
+	// Since is when the operation was deprecated, or the zero time when unknown.
+	Since time.Time
+
+	// Sunset is when the operation will be removed, or the zero time when not announced.
+	Sunset time.Time
+
+	// Link is the URL of the documentation about the deprecation, if any.
+	Link string
+}
+
+// deprecationNotice reads the deprecation headers of a response, if any.
+func deprecationNotice(req *http.Request, res *http.Response, operation *runtime.ClientOperation) (DeprecationNotice, bool) {
+	deprecation := res.Header.Get("Deprecation")
+	sunset := res.Header.Get("Sunset")
+	if deprecation == "" && sunset == "" {
+		return DeprecationNotice{}, false
+	}
+
+	notice := DeprecationNotice{
+		OperationID: operation.ID,
+		Method:      req.Method,
+		URL:         redactedURL(req),
+		Deprecated:  deprecation != "",
+	}
+
+	switch {
+	case strings.HasPrefix(deprecation, "@"):
+		if seconds, err := strconv.ParseInt(deprecation[1:], 10, 64); err == nil {
+			notice.Since = time.Unix(seconds, 0).UTC()
+		}
+	case deprecation != "":
+		// HTTP-date of the drafts preceding RFC 9745, or "true"
+		if since, err := http.ParseTime(deprecation); err == nil {
+			notice.Since = since
+		}
+	}
+
+	if sunset != "" {
+		if date, err := http.ParseTime(sunset); err == nil {
+			notice.Sunset = date
+		}
+	}
+
+	notice.Link = deprecationLink(res.Header.Values("Link"))
+
+	return notice, true
+}
+
+// deprecationLink returns the target of the first link with the relation "deprecation" or "sunset".
+func deprecationLink(values []string) string {
+	for _, value := range values {
+		for link := range strings.SplitSeq(value, ",") {
+			target, params, found := strings.Cut(strings.TrimSpace(link), ";")
+			if !found || !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+
+			for param := range strings.SplitSeq(params, ";") {
+				name, rel, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if !strings.EqualFold(name, "rel") {
+					continue
+				}
+				for relation := range strings.FieldsSeq(strings.Trim(rel, `"`)) {
+					if strings.EqualFold(relation, "deprecation") || strings.EqualFold(relation, "sunset") {
+						return target[1 : len(target)-1]
+					}
+				}
+			}
+		}
+	}
+
+	return ""
+}
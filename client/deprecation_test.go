@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestRuntime_DeprecationWarning(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/v1/users":
+			rw.Header().Set("Deprecation", "@1769817600")
+			rw.Header().Set("Sunset", "Thu, 31 Dec 2026 11:00:00 GMT")
+			rw.Header().Add("Link", `<https://example.com/users>; rel="next", <https://example.com/migrate>; rel="deprecation"; type="text/html"`)
+		case "/v1/groups":
+			rw.Header().Set("Deprecation", "true")
+		case "/v1/reports":
+			rw.Header().Set("Sunset", "Wed, 30 Jun 2027 00:00:00 GMT")
+			rw.Header().Set("Link", `<https://example.com/reports>; rel=sunset`)
+		}
+		rw.Header().Set(runtime.HeaderContentType, runtime.JSONMime)
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+
+	hu, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	var notices []DeprecationNotice
+	rt := New(hu.Host, "/", []string{schemeHTTP})
+	rt.DefaultAuthentication = APIKeyAuth("api_key", "query", "secret") // not disclosed by the notices
+	rt.DeprecationWarning = func(notice DeprecationNotice) {
+		notices = append(notices, notice)
+	}
+
+	for _, path := range []string{"/v1/users", "/v1/groups", "/v1/reports", "/v2/users"} {
+		_, err := rt.SubmitContext(context.Background(), &runtime.ClientOperation{
+			ID:          "call " + path,
+			Method:      http.MethodGet,
+			PathPattern: path,
+			Params: runtime.ClientRequestWriterFunc(func(runtime.ClientRequest, strfmt.Registry) error {
+				return nil
+			}),
+			Reader: runtime.ClientResponseReaderFunc(func(runtime.ClientResponse, runtime.Consumer) (any, error) {
+				return nil, nil
+			}),
+		})
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []DeprecationNotice{
+		{
+			OperationID: "call /v1/users",
+			Method:      http.MethodGet,
+			URL:         server.URL + "/v1/users",
+			Deprecated:  true,
+			Since:       time.Date(2026, time.January, 31, 0, 0, 0, 0, time.UTC),
+			Sunset:      time.Date(2026, time.December, 31, 11, 0, 0, 0, time.UTC),
+			Link:        "https://example.com/migrate",
+		},
+		{
+			OperationID: "call /v1/groups",
+			Method:      http.MethodGet,
+			URL:         server.URL + "/v1/groups",
+			Deprecated:  true,
+		},
+		{
+			OperationID: "call /v1/reports",
+			Method:      http.MethodGet,
+			URL:         server.URL + "/v1/reports",
+			Sunset:      time.Date(2027, time.June, 30, 0, 0, 0, 0, time.UTC),
+			Link:        "https://example.com/reports",
+		},
+	}, notices)
+}
@@ -0,0 +1,281 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/runtime"
+)
+
+const (
+	defaultDownloadAttempts = 5
+	defaultDownloadBackoff  = 500 * time.Millisecond
+)
+
+// ErrDownloadChanged is returned by [Runtime.Download] when the downloaded resource
+// has changed on the server since the download started, so it may not be resumed.
+var ErrDownloadChanged = errors.New("resource changed during download")
+
+// DownloadOption configures a resumable download with [Runtime.Download].
+type DownloadOption func(*downloadOpts)
+
+type downloadOpts struct {
+	attempts int
+	backoff  time.Duration
+}
+
+// WithDownloadAttempts sets the maximum number of requests issued to complete
+// a download (default: 5).
+func WithDownloadAttempts(attempts int) DownloadOption {
+	return func(o *downloadOpts) {
+		if attempts > 0 {
+			o.attempts = attempts
+		}
+	}
+}
+
+// WithDownloadBackoff sets the delay before resuming an interrupted download (default: 500ms).
+func WithDownloadBackoff(backoff time.Duration) DownloadOption {
+	return func(o *downloadOpts) {
+		o.backoff = backoff
+	}
+}
+
+// Download submits an operation which successful response is a byte stream, and copies
+// the response body to dst.
+//
+// Whenever the transfer is interrupted (e.g. the connection is lost), the download is
+// resumed by reissuing the operation with a Range header starting after the last byte
+// received. An If-Range header with the ETag (or Last-Modified date) of the first
+// response ensures the resource hasn't changed in between: otherwise, [ErrDownloadChanged]
+// is returned. Servers which ignore Range requests are supported, at the cost of
+// transferring again the bytes already received.
+//
+// Responses other than 200 (OK) or 206 (Partial Content) are handed over to the
+// operation's response reader, so the usual errors are returned.
+//
+// Download returns the number of bytes written to dst.
+func (r *Runtime) Download(ctx context.Context, operation *runtime.ClientOperation, dst io.Writer, opts ...DownloadOption) (int64, error) {
+	o := downloadOpts{
+		attempts: defaultDownloadAttempts,
+		backoff:  defaultDownloadBackoff,
+	}
+	for _, apply := range opts {
+		apply(&o)
+	}
+
+	state := &downloadState{dst: dst}
+	for attempt := 1; ; attempt++ {
+		op := *operation
+		op.Params = &rangeRequestWriter{params: operation.Params, state: state}
+		reader := &downloadReader{reader: operation.Reader, state: state}
+		op.Reader = reader
+
+		_, err := r.SubmitContext(ctx, &op)
+		if err == nil {
+			return state.written, nil
+		}
+
+		if attempt >= o.attempts || ctx.Err() != nil || !reader.resumable(err) {
+			return state.written, err
+		}
+
+		timer := time.NewTimer(o.backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return state.written, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// downloadState tracks the progress of a download across attempts.
+type downloadState struct {
+	dst       io.Writer
+	written   int64
+	validator string // sent as If-Range when resuming
+	resuming  bool
+}
+
+// rangeRequestWriter adds Range and If-Range headers to the request of a resumed download.
+type rangeRequestWriter struct {
+	params runtime.ClientRequestWriter
+	state  *downloadState
+}
+
+func (w *rangeRequestWriter) WriteToRequest(req runtime.ClientRequest, reg strfmt.Registry) error {
+	if w.params != nil {
+		if err := w.params.WriteToRequest(req, reg); err != nil {
+			return err
+		}
+	}
+
+	w.state.resuming = w.state.written > 0
+	if !w.state.resuming {
+		return nil
+	}
+
+	if err := req.SetHeaderParam("Range", fmt.Sprintf("bytes=%d-", w.state.written)); err != nil {
+		return err
+	}
+
+	if w.state.validator == "" {
+		return nil
+	}
+
+	return req.SetHeaderParam("If-Range", w.state.validator)
+}
+
+// downloadReader copies successful responses to the destination of the download.
+type downloadReader struct {
+	reader      runtime.ClientResponseReader
+	state       *downloadState
+	called      bool
+	interrupted bool
+}
+
+func (d *downloadReader) ReadResponse(resp runtime.ClientResponse, cons runtime.Consumer) (any, error) {
+	d.called = true
+	skip := int64(0)
+
+	switch resp.Code() {
+	case http.StatusOK:
+		if d.state.resuming {
+			if d.state.validator != "" {
+				return nil, ErrDownloadChanged
+			}
+
+			// the server doesn't support ranges: skip what has already been received
+			skip = d.state.written
+		}
+	case http.StatusPartialContent:
+		start, err := contentRangeStart(resp.GetHeader("Content-Range"))
+		if err != nil {
+			return nil, err
+		}
+
+		if start != d.state.written {
+			return nil, fmt.Errorf("unexpected partial content starting at byte %d, expected %d", start, d.state.written)
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		if d.state.resuming && contentRangeSize(resp.GetHeader("Content-Range")) == d.state.written {
+			// the interruption occurred right after the last byte
+			return nil, nil
+		}
+
+		return d.reader.ReadResponse(resp, cons)
+	default:
+		return d.reader.ReadResponse(resp, cons)
+	}
+
+	if d.state.validator == "" {
+		d.state.validator = rangeValidator(resp)
+	}
+
+	body := &interruptibleBody{reader: resp.Body()}
+	if skip > 0 {
+		if _, err := io.CopyN(io.Discard, body, skip); err != nil {
+			d.interrupted = body.err != nil
+
+			return nil, err
+		}
+	}
+
+	n, err := io.Copy(d.state.dst, body)
+	d.state.written += n
+	if err != nil {
+		d.interrupted = body.err != nil
+
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// resumable tells if a failed attempt may be resumed.
+func (d *downloadReader) resumable(err error) bool {
+	if d.called {
+		return d.interrupted
+	}
+
+	// the request could not be sent or no response was received
+	var urlErr *url.Error
+
+	return errors.As(err, &urlErr)
+}
+
+// interruptibleBody records errors when reading a response body,
+// to tell them apart from errors when writing to the destination.
+type interruptibleBody struct {
+	reader io.Reader
+	err    error
+}
+
+func (b *interruptibleBody) Read(p []byte) (int, error) {
+	n, err := b.reader.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		b.err = err
+	}
+
+	return n, err
+}
+
+// rangeValidator returns the validator to send as If-Range: a strong ETag or else the Last-Modified date.
+func rangeValidator(resp runtime.ClientResponse) string {
+	if etag := resp.GetHeader("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+
+	return resp.GetHeader("Last-Modified")
+}
+
+// contentRangeStart parses the first byte position of a Content-Range header such as "bytes 200-999/1000".
+func contentRangeStart(contentRange string) (int64, error) {
+	rest, ok := strings.CutPrefix(contentRange, "bytes ")
+	if !ok {
+		return 0, fmt.Errorf("invalid Content-Range: %q", contentRange)
+	}
+
+	first, _, ok := strings.Cut(rest, "-")
+	if !ok {
+		return 0, fmt.Errorf("invalid Content-Range: %q", contentRange)
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid Content-Range: %q: %w", contentRange, err)
+	}
+
+	return start, nil
+}
+
+// contentRangeSize parses the complete length of a Content-Range header such as "bytes */1000".
+//
+// It returns -1 when the length is unknown or invalid.
+func contentRangeSize(contentRange string) int64 {
+	_, size, ok := strings.Cut(contentRange, "/")
+	if !ok {
+		return -1
+	}
+
+	n, err := strconv.ParseInt(size, 10, 64)
+	if err != nil {
+		return -1
+	}
+
+	return n
+}
@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestRuntime_Download(t *testing.T) {
+	content := strings.Repeat("0123456789", 1000)
+
+	// interrupt writes half of the content then drops the connection
+	interrupt := func(rw http.ResponseWriter) {
+		rw.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write([]byte(content[:len(content)/2]))
+		rw.(http.Flusher).Flush()
+
+		panic(http.ErrAbortHandler)
+	}
+
+	var (
+		calls    atomic.Int32
+		ranges   []string
+		ifRanges []string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		call := calls.Add(1)
+		ranges = append(ranges, req.Header.Get("Range"))
+		ifRanges = append(ifRanges, req.Header.Get("If-Range"))
+		rw.Header().Set(runtime.HeaderContentType, "application/octet-stream")
+
+		switch req.URL.Path {
+		case "/ranges":
+			rw.Header().Set("ETag", `"v1"`)
+			if call == 1 {
+				interrupt(rw)
+			}
+			http.ServeContent(rw, req, "", time.Time{}, strings.NewReader(content))
+		case "/noranges":
+			if call == 1 {
+				interrupt(rw)
+			}
+			_, _ = rw.Write([]byte(content))
+		case "/changed":
+			if call == 1 {
+				rw.Header().Set("ETag", `"v1"`)
+				interrupt(rw)
+			}
+			rw.Header().Set("ETag", `"v2"`)
+			http.ServeContent(rw, req, "", time.Time{}, strings.NewReader(content))
+		case "/broken":
+			interrupt(rw)
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	hu, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	download := func(path string, opts ...DownloadOption) (string, int64, error) {
+		calls.Store(0)
+		ranges, ifRanges = nil, nil
+
+		rt := New(hu.Host, "/", []string{schemeHTTP})
+		rt.Consumers["application/octet-stream"] = runtime.ByteStreamConsumer()
+
+		var buf bytes.Buffer
+		n, err := rt.Download(context.Background(), &runtime.ClientOperation{
+			ID:          "getFile",
+			Method:      http.MethodGet,
+			PathPattern: path,
+			Params: runtime.ClientRequestWriterFunc(func(runtime.ClientRequest, strfmt.Registry) error {
+				return nil
+			}),
+			Reader: runtime.ClientResponseReaderFunc(func(resp runtime.ClientResponse, _ runtime.Consumer) (any, error) {
+				return nil, runtime.NewAPIError("getFile", nil, resp.Code())
+			}),
+		}, &buf, append([]DownloadOption{WithDownloadBackoff(0)}, opts...)...)
+
+		return buf.String(), n, err
+	}
+
+	t.Run("should resume an interrupted download with a range request", func(t *testing.T) {
+		got, n, err := download("/ranges")
+		require.NoError(t, err)
+
+		assert.EqualT(t, int64(len(content)), n)
+		assert.EqualT(t, content, got)
+		assert.Equal(t, []string{"", "bytes=5000-"}, ranges)
+		assert.Equal(t, []string{"", `"v1"`}, ifRanges)
+	})
+
+	t.Run("should resume when the server ignores ranges", func(t *testing.T) {
+		got, n, err := download("/noranges")
+		require.NoError(t, err)
+
+		assert.EqualT(t, int64(len(content)), n)
+		assert.EqualT(t, content, got)
+		assert.Equal(t, []string{"", "bytes=5000-"}, ranges)
+		assert.Equal(t, []string{"", ""}, ifRanges)
+	})
+
+	t.Run("should not resume when the resource has changed", func(t *testing.T) {
+		_, n, err := download("/changed")
+		require.ErrorIs(t, err, ErrDownloadChanged)
+		assert.EqualT(t, int64(len(content)/2), n)
+	})
+
+	t.Run("should give up after the maximum number of attempts", func(t *testing.T) {
+		_, _, err := download("/broken", WithDownloadAttempts(3))
+		require.Error(t, err)
+		assert.EqualT(t, int32(3), calls.Load())
+	})
+
+	t.Run("should not retry error responses", func(t *testing.T) {
+		_, n, err := download("/missing")
+
+		apiErr, ok := runtime.AsAPIError(err)
+		require.TrueT(t, ok)
+		assert.EqualT(t, http.StatusNotFound, apiErr.Code)
+		assert.EqualT(t, int64(0), n)
+		assert.EqualT(t, int32(1), calls.Load())
+	})
+}
+
+func TestContentRange(t *testing.T) {
+	start, err := contentRangeStart("bytes 200-999/1000")
+	require.NoError(t, err)
+	assert.EqualT(t, int64(200), start)
+
+	_, err = contentRangeStart("items 0-1/2")
+	require.Error(t, err)
+
+	_, err = contentRangeStart("bytes x-1/2")
+	require.Error(t, err)
+
+	assert.EqualT(t, int64(1000), contentRangeSize("bytes */1000"))
+	assert.EqualT(t, int64(-1), contentRangeSize("bytes 0-1/*"))
+}
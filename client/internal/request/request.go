@@ -25,7 +25,10 @@ import (
 	"github.com/go-openapi/strfmt"
 )
 
-var _ runtime.ClientRequest = new(Request) // ensure compliance to the interface
+var (
+	_ runtime.ClientRequest       = new(Request) // ensure compliance to the interface
+	_ runtime.FileProgressRequest = new(Request)
+)
 
 // Request represents a swagger client request.
 // It binds parameters to a HTTP request.
@@ -116,6 +119,7 @@ type Request struct {
 	consumes []string
 	timeout  time.Duration
 	buf      *bytes.Buffer
+	progress runtime.FileProgressFunc // see runtime.WithFileProgress
 
 	getBody func(r *Request) []byte
 }
@@ -254,6 +258,11 @@ func (r *Request) SetFileParam(name string, files ...runtime.NamedReadCloser) er
 	return nil
 }
 
+// SetFileProgress sets a function which receives the progress of the files sent as multipart/form-data.
+func (r *Request) SetFileProgress(fn runtime.FileProgressFunc) {
+	r.progress = fn
+}
+
 // GetFileParam yields all file parameters.
 func (r *Request) GetFileParam() map[string][]runtime.NamedReadCloser {
 	return r.fileFields
@@ -725,12 +734,14 @@ func (r *Request) streamMultipartParts(ctx context.Context, mp *multipart.Writer
 		}
 	}()
 
+	progress := &progressWriter{report: r.progress}
 	for fn, f := range r.fileFields {
 		for _, fi := range f {
 			if err := ctx.Err(); err != nil {
 				_ = pw.CloseWithError(err)
 				return
 			}
+			fileLen := fileSize(fi)
 
 			var fileContentType string
 			if p, ok := fi.(runtime.ContentTyper); ok {
@@ -760,14 +771,69 @@ func (r *Request) streamMultipartParts(ctx context.Context, mp *multipart.Writer
 				logClose(err, pw)
 				return
 			}
+			if r.progress != nil {
+				wrtr = progress.start(wrtr, fn, fi, fileLen)
+			}
 			if _, err := io.Copy(wrtr, &ctxReader{ctx: ctx, r: fi}); err != nil {
 				logClose(err, pw)
 				return
 			}
+			if r.progress != nil {
+				progress.done()
+			}
 		}
 	}
 }
 
+// progressWriter reports the bytes written to the parts of a multipart body.
+type progressWriter struct {
+	w      io.Writer
+	report runtime.FileProgressFunc
+	state  runtime.FileProgress
+}
+
+func (p *progressWriter) start(w io.Writer, fieldName string, file runtime.NamedReadCloser, size int64) io.Writer {
+	p.w = w
+	p.state.FieldName = fieldName
+	p.state.Filename = filepath.Base(file.Name())
+	p.state.Size = size
+	p.state.Sent = 0
+	p.state.Done = false
+
+	return p
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	if n > 0 {
+		p.state.Sent += int64(n)
+		p.state.TotalSent += int64(n)
+		p.report(p.state)
+	}
+
+	return n, err
+}
+
+func (p *progressWriter) done() {
+	p.state.Done = true
+	p.report(p.state)
+}
+
+// fileSize returns the size of a file which has a Stat method, or -1.
+func fileSize(file runtime.NamedReadCloser) int64 {
+	statter, ok := file.(interface{ Stat() (os.FileInfo, error) })
+	if !ok {
+		return -1
+	}
+
+	info, err := statter.Stat()
+	if err != nil || !info.Mode().IsRegular() {
+		return -1
+	}
+
+	return info.Size()
+}
+
 // ctxReader wraps an [io.Reader] with a context check on each Read. Once
 // ctx is done, subsequent Reads return ctx.Err() instead of delegating
 // to the underlying reader. It does not preempt a Read already in flight
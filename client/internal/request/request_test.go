@@ -556,6 +556,54 @@ func TestBuildRequest_BuildHTTP_Files(t *testing.T) {
 	fileverifier("empty", 0, filepath.Base(emptyFile.Name()), []byte{})
 }
 
+func TestBuildRequest_BuildHTTP_FileProgress(t *testing.T) {
+	cont, err := os.ReadFile(testFile1)
+	require.NoError(t, err)
+
+	var events []runtime.FileProgress
+	reqWrtr := runtime.WithFileProgress(runtime.ClientRequestWriterFunc(func(req runtime.ClientRequest, _ strfmt.Registry) error {
+		_ = req.SetFileParam("file", mustGetFile(testFile1))
+		_ = req.SetFileParam("other", runtime.NamedReader("other.txt", strings.NewReader(valOregonTrail)))
+		return nil
+	}), func(progress runtime.FileProgress) {
+		events = append(events, progress)
+	})
+
+	r := New(http.MethodPost, "/upload", reqWrtr)
+	req, cancel, err := r.BuildHTTPContext(t.Context(), runtime.MultipartFormMime, "", testProducers, nil, nil)
+	require.NoError(t, err)
+	t.Cleanup(cancel)
+
+	// the progress is reported as the body is consumed
+	_, err = io.Copy(io.Discard, req.Body)
+	require.NoError(t, err)
+	require.NoError(t, req.Body.Close())
+
+	sizes := map[string]int64{"file": int64(len(cont)), "other": int64(len(valOregonTrail))}
+	var done []string
+	var total int64
+	for _, event := range events {
+		assert.LessOrEqual(t, event.Sent, sizes[event.FieldName])
+		assert.GreaterOrEqual(t, event.TotalSent, total)
+		total = event.TotalSent
+		if event.Done {
+			done = append(done, event.FieldName)
+			assert.EqualT(t, sizes[event.FieldName], event.Sent)
+		}
+
+		switch event.FieldName {
+		case "file":
+			assert.EqualT(t, testFile1, event.Filename)
+			assert.EqualT(t, int64(len(cont)), event.Size)
+		case "other":
+			assert.EqualT(t, "other.txt", event.Filename)
+			assert.EqualT(t, int64(-1), event.Size)
+		}
+	}
+	assert.ElementsMatch(t, []string{"file", "other"}, done)
+	assert.EqualT(t, int64(len(cont)+len(valOregonTrail)), total)
+}
+
 // TestBuildRequest_BuildHTTP_Files_URLEncoded covers issue #286: when the
 // caller explicitly picks application/x-www-form-urlencoded, file fields must
 // be encoded as regular URL-encoded form values rather than producing a
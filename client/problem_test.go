@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestRuntime_ProblemDetails(t *testing.T) {
+	const problemDoc = `{"type":"about:blank","title":"Not Found","detail":"no such pet","instance":"/pets/1","traceId":"abc"}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/ok":
+			rw.Header().Set(runtime.HeaderContentType, runtime.JSONMime)
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{}`))
+		case "/invalid":
+			rw.Header().Set(runtime.HeaderContentType, runtime.ProblemJSONMime)
+			rw.WriteHeader(http.StatusBadRequest)
+			_, _ = rw.Write([]byte(`{"title":`))
+		default:
+			rw.Header().Set(runtime.HeaderContentType, runtime.ProblemJSONMime+"; charset=utf-8")
+			rw.WriteHeader(http.StatusNotFound)
+			_, _ = rw.Write([]byte(problemDoc))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	hu, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	readerCalled := false
+	submit := func(rt *Runtime, path string) error {
+		readerCalled = false
+		_, err := rt.SubmitContext(context.Background(), &runtime.ClientOperation{
+			ID:          "getPet",
+			Method:      http.MethodGet,
+			PathPattern: path,
+			Params: runtime.ClientRequestWriterFunc(func(runtime.ClientRequest, strfmt.Registry) error {
+				return nil
+			}),
+			Reader: runtime.ClientResponseReaderFunc(func(resp runtime.ClientResponse, _ runtime.Consumer) (any, error) {
+				readerCalled = true
+				if resp.Code() == http.StatusOK {
+					return struct{}{}, nil
+				}
+
+				return nil, runtime.NewAPIError("getPet", nil, resp.Code())
+			}),
+		})
+
+		return err
+	}
+
+	t.Run("should decode problem documents with suffix matching", func(t *testing.T) {
+		rt := New(hu.Host, "/", []string{schemeHTTP})
+		rt.ProblemDetails = true
+		rt.MatchSuffix = true
+
+		err := submit(rt, "/pets/1")
+		require.Error(t, err)
+		assert.FalseT(t, readerCalled)
+
+		var problem *runtime.ProblemError
+		require.TrueT(t, errors.As(err, &problem))
+		assert.EqualT(t, http.StatusNotFound, problem.Status)
+		assert.EqualT(t, "no such pet", problem.Detail)
+		assert.EqualT(t, "/pets/1", problem.Instance)
+		assert.Equal(t, map[string]any{"traceId": "abc"}, problem.Extensions)
+	})
+
+	t.Run("should decode problem documents with an explicit consumer", func(t *testing.T) {
+		rt := New(hu.Host, "/", []string{schemeHTTP})
+		rt.ProblemDetails = true
+		rt.Consumers[runtime.ProblemJSONMime] = runtime.JSONConsumer()
+
+		var problem *runtime.ProblemError
+		require.TrueT(t, errors.As(submit(rt, "/pets/1"), &problem))
+	})
+
+	t.Run("should report invalid problem documents", func(t *testing.T) {
+		rt := New(hu.Host, "/", []string{schemeHTTP})
+		rt.ProblemDetails = true
+		rt.MatchSuffix = true
+
+		err := submit(rt, "/invalid")
+		require.Error(t, err)
+		require.ErrorContains(t, err, "decode problem details")
+	})
+
+	t.Run("should leave successful responses to the reader", func(t *testing.T) {
+		rt := New(hu.Host, "/", []string{schemeHTTP})
+		rt.ProblemDetails = true
+		rt.MatchSuffix = true
+
+		require.NoError(t, submit(rt, "/ok"))
+		assert.TrueT(t, readerCalled)
+	})
+
+	t.Run("should leave problems to the reader when disabled", func(t *testing.T) {
+		rt := New(hu.Host, "/", []string{schemeHTTP})
+		rt.MatchSuffix = true
+
+		err := submit(rt, "/pets/1")
+		assert.TrueT(t, readerCalled)
+
+		var apiErr *runtime.APIError
+		require.TrueT(t, errors.As(err, &apiErr))
+	})
+}
@@ -80,6 +80,37 @@ type Runtime struct {
 	// See [mediatype.AllowSuffix] for the semantics.
 	MatchSuffix bool
 
+	// ProblemDetails enables the decoding of RFC 9457 problem details documents.
+	//
+	// When true, an error response (4xx or 5xx) with Content-Type "application/problem+json"
+	// is decoded into a [*runtime.ProblemError], which is returned as the error of the call.
+	// The operation's response reader is not called in that case.
+	//
+	// The document is decoded with the consumer registered for "application/problem+json",
+	// or, when [Runtime.MatchSuffix] is enabled, with the JSON consumer.
+	ProblemDetails bool
+
+	// APIErrorBodyLimit enables the capture of unexpected response bodies, when positive.
+	//
+	// Whenever the operation's response reader returns a [*runtime.APIError] (typically for
+	// a status code not declared in the spec), the runtime completes it with the request
+	// method and URL, the response headers, and up to APIErrorBodyLimit bytes of the
+	// response body. If the body has been captured entirely and the consumer for the
+	// response content type can decode it, the decoded value is exposed as
+	// [runtime.APIError.Payload].
+	//
+	// Use [runtime.AsAPIError] to retrieve these details from the returned error.
+	//
+	// Default: 0 (disabled).
+	APIErrorBodyLimit int64
+
+	// DeprecationWarning is called when a response announces the deprecation or the sunset
+	// of the called operation, with Deprecation (RFC 9745) or Sunset (RFC 8594) headers,
+	// e.g. to log the calls to operations about to be removed.
+	//
+	// Default: nil (disabled).
+	DeprecationWarning func(DeprecationNotice)
+
 	clientOnce *sync.Once
 	client     *http.Client
 	schemes    []string
@@ -255,6 +286,11 @@ func (r *Runtime) SubmitContext(parentCtx context.Context, operation *runtime.Cl
 		res.Body = trace.wrapResponseBody(res.Body)
 	}
 
+	return r.readResponse(req, res, operation)
+}
+
+// readResponse hands over a response to the operation's response reader, with the consumer for its content type.
+func (r *Runtime) readResponse(req *http.Request, res *http.Response, operation *runtime.ClientOperation) (any, error) {
 	ct := res.Header.Get(runtime.HeaderContentType)
 	if ct == "" { // this should really never occur
 		ct = r.DefaultMediaType
@@ -264,12 +300,33 @@ func (r *Runtime) SubmitContext(parentCtx context.Context, operation *runtime.Cl
 		return nil, err
 	}
 
+	if r.DeprecationWarning != nil {
+		if notice, ok := deprecationNotice(req, res, operation); ok {
+			r.DeprecationWarning(notice)
+		}
+	}
+
 	cons, err := r.resolveConsumer(ct)
 	if err != nil {
 		return nil, err
 	}
 
-	return operation.Reader.ReadResponse(r.response(res), cons)
+	if r.ProblemDetails && res.StatusCode >= http.StatusBadRequest && isProblem(ct) {
+		return nil, readProblem(res, cons)
+	}
+
+	if r.APIErrorBodyLimit <= 0 {
+		return operation.Reader.ReadResponse(r.response(res), cons)
+	}
+
+	body := newCapturingBody(res.Body, r.APIErrorBodyLimit)
+	res.Body = body
+	result, err := operation.Reader.ReadResponse(r.response(res), cons)
+	if apiErr, ok := runtime.AsAPIError(err); ok {
+		enrichAPIError(apiErr, req, res, body, cons)
+	}
+
+	return result, err
 }
 
 // SetDebug changes the debug flag.
@@ -413,6 +470,31 @@ func (r *Runtime) resolveConsumer(ct string) (runtime.Consumer, error) {
 	return nil, fmt.Errorf("no consumer: %q", ct)
 }
 
+// isProblem reports whether ct is the RFC 9457 problem details media type.
+func isProblem(ct string) bool {
+	mt, err := mediatype.Parse(ct)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(mt.Type+"/"+mt.Subtype, runtime.ProblemJSONMime)
+}
+
+// readProblem decodes a problem details document. The status code of the response
+// prevails whenever the document doesn't specify one.
+func readProblem(res *http.Response, cons runtime.Consumer) error {
+	problem := new(runtime.ProblemError)
+	if err := cons.Consume(res.Body, problem); err != nil {
+		return fmt.Errorf("decode problem details: %w", err)
+	}
+
+	if problem.Status == 0 {
+		problem.Status = res.StatusCode
+	}
+
+	return problem
+}
+
 // matchOpts builds the mediatype.MatchOption slice for codec
 // lookups on the Runtime, currently just the AllowSuffix opt-in.
 func (r *Runtime) matchOpts() []mediatype.MatchOption {
@@ -434,11 +516,17 @@ func (r *Runtime) createHTTPRequestContext(parentCtx context.Context, operation
 		return nil, nil, err
 	}
 
+	var credentialParams []string
+	if auth != nil {
+		auth = recordCredentialParams(auth, &credentialParams)
+	}
+
 	httpReq, cancel, err := req.BuildHTTPContext(parentCtx, cmt, r.BasePath, r.Producers, r.Formats, auth)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	httpReq = withCredentialParams(httpReq, credentialParams)
 	r.applyHostScheme(httpReq, operation)
 
 	return httpReq, cancel, nil
@@ -0,0 +1,238 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+
+	"github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/runtime"
+)
+
+// ErrUnexpectedResult is returned by [Do] when the response reader of an operation
+// returns a result which is not of the expected type.
+var ErrUnexpectedResult = errors.New("unexpected result type")
+
+// ClientResponseReader reads a response into a result of type T.
+//
+// It is the typed counterpart of [runtime.ClientResponseReader]:
+// use [UntypedReader] to set it as the reader of a [runtime.ClientOperation].
+type ClientResponseReader[T any] interface { //nolint:revive // mirrors runtime.ClientResponseReader
+	ReadResponse(runtime.ClientResponse, runtime.Consumer) (T, error)
+}
+
+// ClientResponseReaderFunc turns a function into a [ClientResponseReader] implementation.
+type ClientResponseReaderFunc[T any] func(runtime.ClientResponse, runtime.Consumer) (T, error) //nolint:revive // mirrors runtime.ClientResponseReaderFunc
+
+// ReadResponse reads the response.
+func (read ClientResponseReaderFunc[T]) ReadResponse(resp runtime.ClientResponse, consumer runtime.Consumer) (T, error) {
+	return read(resp, consumer)
+}
+
+// UntypedReader adapts a typed response reader to a [runtime.ClientResponseReader].
+func UntypedReader[T any](reader ClientResponseReader[T]) runtime.ClientResponseReader {
+	return runtime.ClientResponseReaderFunc(func(resp runtime.ClientResponse, consumer runtime.Consumer) (any, error) {
+		return reader.ReadResponse(resp, consumer)
+	})
+}
+
+// TypedReader adapts a [runtime.ClientResponseReader], e.g. the reader of a generated client,
+// to a response reader with results of type T.
+//
+// A result of another type is reported with [ErrUnexpectedResult].
+func TypedReader[T any](reader runtime.ClientResponseReader) ClientResponseReader[T] {
+	return ClientResponseReaderFunc[T](func(resp runtime.ClientResponse, consumer runtime.Consumer) (T, error) {
+		result, err := reader.ReadResponse(resp, consumer)
+		typed, convErr := asResult[T](result)
+		if err != nil {
+			return typed, err
+		}
+
+		return typed, convErr
+	})
+}
+
+// ResponseError is an error response with a payload of type E, i.e. one of the error models of an operation.
+//
+// It wraps a [runtime.APIError], so [runtime.AsAPIError] finds it too.
+type ResponseError[E any] struct {
+	APIError *runtime.APIError
+
+	// Payload is the response body, decoded as E
+	Payload E
+}
+
+// AsResponseError finds the first [ResponseError] with a payload of type E in the chain of err.
+//
+// This is a shorthand for [errors.As].
+func AsResponseError[E any](err error) (*ResponseError[E], bool) {
+	var respErr *ResponseError[E]
+	if errors.As(err, &respErr) {
+		return respErr, true
+	}
+
+	return nil, false
+}
+
+func (e *ResponseError[E]) Error() string {
+	return e.APIError.Error()
+}
+
+// Unwrap returns the underlying [runtime.APIError].
+func (e *ResponseError[E]) Unwrap() error {
+	return e.APIError
+}
+
+// Code returns the status code of the response.
+func (e *ResponseError[E]) Code() int {
+	return e.APIError.Code
+}
+
+// PayloadReader builds a response reader which decodes the body of successful responses as T,
+// and the body of other responses as E, returned in a [*ResponseError].
+//
+// By default, 2xx responses are successful: the status codes of successful responses may be set instead.
+// The result of a response without a body (e.g. 204) is the zero value of T.
+func PayloadReader[T, E any](successCodes ...int) ClientResponseReader[T] {
+	isSuccess := func(code int) bool {
+		return code >= http.StatusOK && code < http.StatusMultipleChoices
+	}
+	if len(successCodes) > 0 {
+		isSuccess = func(code int) bool {
+			for _, c := range successCodes {
+				if c == code {
+					return true
+				}
+			}
+
+			return false
+		}
+	}
+
+	return ClientResponseReaderFunc[T](func(resp runtime.ClientResponse, consumer runtime.Consumer) (T, error) {
+		var result T
+		if isSuccess(resp.Code()) {
+			if err := consumeBody(resp, consumer, &result); err != nil {
+				return result, err
+			}
+
+			return result, nil
+		}
+
+		respErr := &ResponseError[E]{
+			APIError: runtime.NewAPIError("", resp, resp.Code()),
+		}
+		if err := consumeBody(resp, consumer, &respErr.Payload); err == nil {
+			respErr.APIError.Response = respErr.Payload
+			respErr.APIError.Payload = respErr.Payload
+		}
+
+		return result, respErr
+	})
+}
+
+// consumeBody decodes the body of a response, if any.
+func consumeBody(resp runtime.ClientResponse, consumer runtime.Consumer, data any) error {
+	if resp.Code() == http.StatusNoContent || resp.Code() == http.StatusResetContent || resp.Body() == nil {
+		return nil
+	}
+
+	if err := consumer.Consume(resp.Body(), data); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	return nil
+}
+
+// Do submits an operation with parameters of type Req, and returns its result as Resp.
+//
+// When Req implements [runtime.ClientRequestWriter], params are the parameters of the operation.
+// Otherwise, non-nil params are the body of the request, on top of the parameters possibly set on the operation
+// (e.g. path parameters).
+//
+// When the operation has no response reader, responses are read with [PayloadReader],
+// and error responses are returned as a [*ResponseError] with an untyped payload.
+// Set [UntypedReader] of a [PayloadReader] as the reader of the operation to decode typed error models.
+//
+// The operation is not modified. When the transport is a [runtime.ContextualTransport], the operation is
+// submitted with ctx; otherwise ctx is set as the context of the operation.
+func Do[Req, Resp any](ctx context.Context, transport runtime.ClientTransport, operation *runtime.ClientOperation, params Req) (Resp, error) {
+	op := *operation
+
+	switch writer := any(params).(type) {
+	case runtime.ClientRequestWriter:
+		op.Params = writer
+	case nil:
+		if op.Params == nil {
+			op.Params = noParams
+		}
+	default:
+		op.Params = bodyParams(params, operation.Params)
+	}
+
+	if op.Reader == nil {
+		op.Reader = UntypedReader(PayloadReader[Resp, any]())
+	}
+
+	var (
+		result any
+		err    error
+	)
+	if submitter, ok := transport.(runtime.ContextualTransport); ok {
+		result, err = submitter.SubmitContext(ctx, &op)
+	} else {
+		op.Context = ctx
+		result, err = transport.Submit(&op)
+	}
+
+	if apiErr, ok := runtime.AsAPIError(err); ok && apiErr.OperationName == "" {
+		apiErr.OperationName = op.ID
+	}
+
+	typed, convErr := asResult[Resp](result)
+	if err != nil {
+		return typed, err
+	}
+
+	if convErr != nil {
+		return typed, fmt.Errorf("%s: %w", op.ID, convErr)
+	}
+
+	return typed, nil
+}
+
+var noParams = runtime.ClientRequestWriterFunc(func(runtime.ClientRequest, strfmt.Registry) error { return nil })
+
+// bodyParams sets body as the body of the request, after the other parameters.
+func bodyParams(body any, params runtime.ClientRequestWriter) runtime.ClientRequestWriter {
+	return runtime.ClientRequestWriterFunc(func(req runtime.ClientRequest, reg strfmt.Registry) error {
+		if params != nil {
+			if err := params.WriteToRequest(req, reg); err != nil {
+				return err
+			}
+		}
+
+		return req.SetBodyParam(body)
+	})
+}
+
+func asResult[T any](result any) (T, error) {
+	var zero T
+	if result == nil {
+		return zero, nil
+	}
+
+	typed, ok := result.(T)
+	if !ok {
+		return zero, fmt.Errorf("%w: %T, expected %v", ErrUnexpectedResult, result, reflect.TypeFor[T]())
+	}
+
+	return typed, nil
+}
@@ -0,0 +1,166 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/go-openapi/runtime"
+)
+
+type typedPet struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type typedProblem struct {
+	Message string `json:"message"`
+}
+
+func newTypedServer(t *testing.T) *Runtime {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set(runtime.HeaderContentType, runtime.JSONMime)
+		switch {
+		case req.Method == http.MethodPost:
+			var pet typedPet
+			if err := json.NewDecoder(req.Body).Decode(&pet); err != nil {
+				rw.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(rw).Encode(typedProblem{Message: err.Error()})
+
+				return
+			}
+			pet.ID = 42
+			rw.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(rw).Encode(pet)
+		case req.Method == http.MethodDelete:
+			rw.WriteHeader(http.StatusNoContent)
+		case req.URL.Path == "/pets/42":
+			_ = json.NewEncoder(rw).Encode(typedPet{ID: 42, Name: "Rex"})
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(rw).Encode(typedProblem{Message: "no such pet"})
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	hu, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	return New(hu.Host, "/", []string{schemeHTTP})
+}
+
+func petIDParam(id string) runtime.ClientRequestWriter {
+	return runtime.ClientRequestWriterFunc(func(req runtime.ClientRequest, _ strfmt.Registry) error {
+		return req.SetPathParam("id", id)
+	})
+}
+
+func TestDo(t *testing.T) {
+	rt := newTypedServer(t)
+
+	t.Run("should return a typed result", func(t *testing.T) {
+		pet, err := Do[runtime.ClientRequestWriter, *typedPet](t.Context(), rt, &runtime.ClientOperation{
+			ID:          "getPet",
+			Method:      http.MethodGet,
+			PathPattern: "/pets/{id}",
+		}, petIDParam("42"))
+		require.NoError(t, err)
+		assert.Equal(t, &typedPet{ID: 42, Name: "Rex"}, pet)
+	})
+
+	t.Run("should send params as the body", func(t *testing.T) {
+		pet, err := Do[typedPet, typedPet](t.Context(), rt, &runtime.ClientOperation{
+			ID:                 "addPet",
+			Method:             http.MethodPost,
+			PathPattern:        "/pets",
+			ConsumesMediaTypes: []string{runtime.JSONMime},
+		}, typedPet{Name: "Rex"})
+		require.NoError(t, err)
+		assert.Equal(t, typedPet{ID: 42, Name: "Rex"}, pet)
+	})
+
+	t.Run("should return the zero value without a body", func(t *testing.T) {
+		pet, err := Do[runtime.ClientRequestWriter, *typedPet](t.Context(), rt, &runtime.ClientOperation{
+			ID:          "deletePet",
+			Method:      http.MethodDelete,
+			PathPattern: "/pets/{id}",
+		}, petIDParam("42"))
+		require.NoError(t, err)
+		assert.Nil(t, pet)
+	})
+
+	t.Run("should return typed error models", func(t *testing.T) {
+		op := &runtime.ClientOperation{
+			ID:          "getPet",
+			Method:      http.MethodGet,
+			PathPattern: "/pets/{id}",
+			Reader:      UntypedReader(PayloadReader[*typedPet, typedProblem](http.StatusOK)),
+		}
+		_, err := Do[runtime.ClientRequestWriter, *typedPet](t.Context(), rt, op, petIDParam("7"))
+		require.Error(t, err)
+
+		respErr, ok := AsResponseError[typedProblem](err)
+		require.TrueT(t, ok)
+		assert.EqualT(t, http.StatusNotFound, respErr.Code())
+		assert.EqualT(t, "no such pet", respErr.Payload.Message)
+
+		apiErr, ok := runtime.AsAPIError(err)
+		require.TrueT(t, ok)
+		assert.EqualT(t, "getPet", apiErr.OperationName)
+		assert.StringContainsT(t, err.Error(), "no such pet")
+
+		// the operation is left untouched
+		assert.Nil(t, op.Params)
+	})
+
+	t.Run("should return untyped error models by default", func(t *testing.T) {
+		_, err := Do[any, *typedPet](t.Context(), rt, &runtime.ClientOperation{
+			ID:          "listPets",
+			Method:      http.MethodGet,
+			PathPattern: "/pets",
+		}, nil)
+
+		respErr, ok := AsResponseError[any](err)
+		require.TrueT(t, ok)
+		assert.Equal(t, map[string]any{"message": "no such pet"}, respErr.Payload)
+	})
+
+	t.Run("should adapt untyped readers", func(t *testing.T) {
+		reader := runtime.ClientResponseReaderFunc(func(resp runtime.ClientResponse, cons runtime.Consumer) (any, error) {
+			var pet typedPet
+			err := cons.Consume(resp.Body(), &pet)
+
+			return &pet, err
+		})
+		op := &runtime.ClientOperation{
+			ID:          "getPet",
+			Method:      http.MethodGet,
+			PathPattern: "/pets/{id}",
+			Reader:      reader,
+		}
+
+		pet, err := Do[runtime.ClientRequestWriter, *typedPet](t.Context(), rt, op, petIDParam("42"))
+		require.NoError(t, err)
+		assert.EqualT(t, "Rex", pet.Name)
+
+		_, err = Do[runtime.ClientRequestWriter, typedPet](t.Context(), rt, op, petIDParam("42"))
+		require.ErrorIs(t, err, ErrUnexpectedResult)
+
+		typed := TypedReader[*typedPet](runtime.ClientResponseReaderFunc(func(runtime.ClientResponse, runtime.Consumer) (any, error) {
+			return "not a pet", nil
+		}))
+		_, err = typed.ReadResponse(newResponse(&http.Response{StatusCode: http.StatusOK, Body: http.NoBody}), runtime.JSONConsumer())
+		require.ErrorIs(t, err, ErrUnexpectedResult)
+	})
+}
@@ -0,0 +1,219 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+// Package untyped provides a client for the operations of an API described by a spec, without generated code.
+//
+// It is the client side counterpart of the untyped API served by the middleware.
+package untyped
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-openapi/analysis"
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/client"
+)
+
+// ErrUnknownOperation is returned by [Client.Call] for an operation which is not described by the spec.
+var ErrUnknownOperation = errors.New("unknown operation")
+
+// ErrUnknownParameter is returned by [Client.Call] for a parameter which is not declared by the operation.
+var ErrUnknownParameter = errors.New("unknown parameter")
+
+// Option configures a [Client].
+type Option func(*Client)
+
+// WithFormats sets the registry of string formats used to validate parameters (default: [strfmt.Default]).
+func WithFormats(formats strfmt.Registry) Option {
+	return func(c *Client) {
+		if formats != nil {
+			c.formats = formats
+		}
+	}
+}
+
+// WithAuthInfo sets the authentication of requests.
+//
+// By default, requests are authenticated by the transport, e.g. with the default authentication of a [client.Runtime].
+func WithAuthInfo(authInfo runtime.ClientAuthInfoWriter) Option {
+	return func(c *Client) {
+		c.authInfo = authInfo
+	}
+}
+
+// Client calls the operations of an API described by a spec.
+//
+// Parameters are placed in the request as declared by the spec, and validated against
+// the constraints of the spec. Responses are decoded according to the schema declared for their status code.
+type Client struct {
+	transport runtime.ClientTransport
+	spec      *loads.Document
+	analyzer  *analysis.Spec
+	formats   strfmt.Registry
+	authInfo  runtime.ClientAuthInfoWriter
+}
+
+// Response is the response to a call.
+type Response struct {
+	// Code is the status code of the response
+	Code int `json:"code"`
+
+	// Header holds the headers of the response declared by the spec
+	Header http.Header `json:"header,omitempty"`
+
+	// Payload is the response body, decoded by the consumer of the response: with JSON, objects are decoded
+	// as map[string]any and numbers as json.Number. Files are read as []byte.
+	// Payload is nil when no schema is declared for the response.
+	Payload any `json:"payload,omitempty"`
+}
+
+// New creates a client for the operations of a spec, submitted with a transport.
+//
+// The transport is usually a [client.Runtime] created from the host, base path and schemes of the spec:
+//
+//	rt := client.New(doc.Host(), doc.BasePath(), doc.Spec().Schemes)
+func New(doc *loads.Document, transport runtime.ClientTransport, opts ...Option) (*Client, error) {
+	expanded, err := doc.Expanded()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		transport: transport,
+		spec:      expanded,
+		analyzer:  analysis.New(expanded.Spec()),
+		formats:   strfmt.Default,
+	}
+	for _, apply := range opts {
+		apply(c)
+	}
+
+	return c, nil
+}
+
+// Operations returns the IDs of the operations of the spec, sorted.
+func (c *Client) Operations() []string {
+	ids := c.analyzer.OperationIDs()
+	slices.Sort(ids)
+
+	return ids
+}
+
+// Call calls an operation, with parameters by name.
+//
+// Values of simple parameters may be given as strings, e.g. from the command line: they are
+// converted to the type declared by the spec. Arrays may be given as slices, or as strings
+// with the collection format of the parameter. Files are given as [io.Reader], preferably
+// as [runtime.NamedReadCloser].
+// The body parameter is sent as is, e.g. a map[string]any.
+//
+// All parameters are validated before the request is sent: validation errors are returned
+// as a [github.com/go-openapi/errors.CompositeError].
+//
+// Responses with a 2xx status code are returned as a [*Response]. Other responses are returned
+// as a [*runtime.APIError], with a [*Response] as Response when the status code is declared by the spec.
+func (c *Client) Call(ctx context.Context, operationID string, params map[string]any) (*Response, error) {
+	method, path, operation, ok := c.analyzer.OperationForName(operationID)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownOperation, operationID)
+	}
+
+	writer, err := c.bindParams(operationID, c.analyzer.ParamsFor(method, path), params)
+	if err != nil {
+		return nil, err
+	}
+
+	schemes := operation.Schemes
+	if len(schemes) == 0 {
+		schemes = c.spec.Spec().Schemes
+	}
+
+	return client.Do[runtime.ClientRequestWriter, *Response](ctx, c.transport, &runtime.ClientOperation{
+		ID:                 operationID,
+		Method:             strings.ToUpper(method),
+		PathPattern:        path,
+		ProducesMediaTypes: c.analyzer.ProducesFor(operation),
+		ConsumesMediaTypes: c.analyzer.ConsumesFor(operation),
+		Schemes:            schemes,
+		AuthInfo:           c.authInfo,
+		Reader:             c.responseReader(operationID, operation),
+	}, writer)
+}
+
+// responseReader decodes responses according to the responses declared by an operation.
+func (c *Client) responseReader(operationID string, operation *spec.Operation) runtime.ClientResponseReader {
+	return runtime.ClientResponseReaderFunc(func(resp runtime.ClientResponse, consumer runtime.Consumer) (any, error) {
+		declared, ok := responseFor(operation, resp.Code())
+		if !ok {
+			return nil, runtime.NewAPIError(operationID, resp, resp.Code())
+		}
+
+		result := &Response{
+			Code: resp.Code(),
+		}
+		for name := range declared.Headers {
+			if values := resp.GetHeaders(name); len(values) > 0 {
+				if result.Header == nil {
+					result.Header = make(http.Header, len(declared.Headers))
+				}
+				result.Header[http.CanonicalHeaderKey(name)] = values
+			}
+		}
+
+		payload, err := readPayload(resp, consumer, declared.Schema)
+		if err != nil {
+			return nil, err
+		}
+		result.Payload = payload
+
+		const statusSuccess = 2
+		if resp.Code()/100 != statusSuccess {
+			apiErr := runtime.NewAPIError(operationID, result, resp.Code())
+			apiErr.Payload = payload
+
+			return nil, apiErr
+		}
+
+		return result, nil
+	})
+}
+
+// responseFor picks the response declared for a status code, or the default response.
+func responseFor(operation *spec.Operation, code int) (*spec.Response, bool) {
+	if operation.Responses == nil {
+		return nil, false
+	}
+
+	if response, ok := operation.Responses.StatusCodeResponses[code]; ok {
+		return &response, true
+	}
+
+	return operation.Responses.Default, operation.Responses.Default != nil
+}
+
+func readPayload(resp runtime.ClientResponse, consumer runtime.Consumer, schema *spec.Schema) (any, error) {
+	if schema == nil || resp.Code() == http.StatusNoContent {
+		return nil, nil //nolint:nilnil // no payload is expected
+	}
+
+	if schema.Type.Contains(typeFile) {
+		return io.ReadAll(resp.Body())
+	}
+
+	var payload any
+	if err := consumer.Consume(resp.Body(), &payload); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,269 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package untyped
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/client"
+)
+
+const testSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "basePath": "/api",
+  "consumes": ["application/json"],
+  "produces": ["application/json"],
+  "paths": {
+    "/pets/{id}": {
+      "parameters": [
+        {"name": "id", "in": "path", "required": true, "type": "integer", "minimum": 1}
+      ],
+      "get": {
+        "operationId": "getPet",
+        "parameters": [
+          {"name": "fields", "in": "query", "type": "array", "items": {"type": "string"}, "collectionFormat": "pipes"},
+          {"name": "tag", "in": "query", "type": "array", "items": {"type": "string"}, "collectionFormat": "multi"},
+          {"name": "X-Request-Id", "in": "header", "type": "string", "maxLength": 8}
+        ],
+        "responses": {
+          "200": {
+            "description": "a pet",
+            "schema": {"$ref": "#/definitions/Pet"},
+            "headers": {"ETag": {"type": "string"}}
+          },
+          "404": {"$ref": "#/responses/NotFound"}
+        }
+      }
+    },
+    "/pets": {
+      "post": {
+        "operationId": "addPet",
+        "parameters": [
+          {"name": "pet", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Pet"}}
+        ],
+        "responses": {
+          "201": {"description": "created", "schema": {"$ref": "#/definitions/Pet"}},
+          "default": {"description": "error", "schema": {"$ref": "#/definitions/Problem"}}
+        }
+      }
+    },
+    "/pets/{id}/photo": {
+      "post": {
+        "operationId": "uploadPhoto",
+        "consumes": ["multipart/form-data"],
+        "parameters": [
+          {"name": "id", "in": "path", "required": true, "type": "integer"},
+          {"name": "caption", "in": "formData", "type": "string"},
+          {"name": "photo", "in": "formData", "type": "file", "required": true}
+        ],
+        "responses": {
+          "204": {"description": "uploaded"}
+        }
+      }
+    }
+  },
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "required": ["name"],
+      "properties": {
+        "id": {"type": "integer"},
+        "name": {"type": "string", "minLength": 1}
+      }
+    },
+    "Problem": {
+      "type": "object",
+      "properties": {"message": {"type": "string"}}
+    }
+  },
+  "responses": {
+    "NotFound": {"description": "not found", "schema": {"$ref": "#/definitions/Problem"}}
+  }
+}`
+
+type recordedRequest struct {
+	method string
+	uri    string
+	header http.Header
+	form   map[string][]string
+	file   string
+}
+
+func newTestClient(t *testing.T) (*Client, *recordedRequest) {
+	t.Helper()
+
+	var recorded recordedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		recorded = recordedRequest{method: req.Method, uri: req.RequestURI, header: req.Header}
+		rw.Header().Set(runtime.HeaderContentType, runtime.JSONMime)
+
+		switch {
+		case strings.HasSuffix(req.URL.Path, "/photo"):
+			file, _, err := req.FormFile("photo")
+			require.NoError(t, err)
+			content, _ := io.ReadAll(file)
+			recorded.form = req.MultipartForm.Value
+			recorded.file = string(content)
+			rw.WriteHeader(http.StatusNoContent)
+		case req.Method == http.MethodPost:
+			var pet map[string]any
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&pet))
+			if pet["name"] == "conflict" {
+				rw.WriteHeader(http.StatusConflict)
+				_, _ = rw.Write([]byte(`{"message":"already exists"}`))
+
+				return
+			}
+			pet["id"] = 3
+			rw.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(rw).Encode(pet)
+		case req.URL.Path == "/api/pets/1":
+			rw.Header().Set("ETag", `"v1"`)
+			_, _ = rw.Write([]byte(`{"id":1,"name":"Rex"}`))
+		case req.URL.Path == "/api/pets/2":
+			rw.WriteHeader(http.StatusNotFound)
+			_, _ = rw.Write([]byte(`{"message":"no such pet"}`))
+		default:
+			rw.WriteHeader(http.StatusTeapot)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	doc, err := loads.Analyzed(json.RawMessage(testSpec), "")
+	require.NoError(t, err)
+
+	hu, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	c, err := New(doc, client.New(hu.Host, doc.BasePath(), []string{"http"}))
+	require.NoError(t, err)
+
+	return c, &recorded
+}
+
+func TestClient_Call(t *testing.T) {
+	c, recorded := newTestClient(t)
+
+	t.Run("should list operations", func(t *testing.T) {
+		assert.Equal(t, []string{"addPet", "getPet", "uploadPhoto"}, c.Operations())
+	})
+
+	t.Run("should place parameters as declared by the spec", func(t *testing.T) {
+		resp, err := c.Call(t.Context(), "getPet", map[string]any{
+			"id":           "1",
+			"fields":       "id|name",
+			"tag":          []string{"a", "b"},
+			"X-Request-Id": "abc",
+		})
+		require.NoError(t, err)
+
+		assert.EqualT(t, http.MethodGet, recorded.method)
+		assert.EqualT(t, "/api/pets/1?fields=id%7Cname&tag=a&tag=b", recorded.uri)
+		assert.EqualT(t, "abc", recorded.header.Get("X-Request-Id"))
+
+		assert.EqualT(t, http.StatusOK, resp.Code)
+		assert.EqualT(t, `"v1"`, resp.Header.Get("ETag"))
+		assert.Equal(t, map[string]any{"id": json.Number("1"), "name": "Rex"}, resp.Payload)
+	})
+
+	t.Run("should send the body", func(t *testing.T) {
+		resp, err := c.Call(t.Context(), "addPet", map[string]any{
+			"pet": map[string]any{"name": "Rex"},
+		})
+		require.NoError(t, err)
+		assert.EqualT(t, http.StatusCreated, resp.Code)
+		assert.Equal(t, map[string]any{"id": json.Number("3"), "name": "Rex"}, resp.Payload)
+	})
+
+	t.Run("should send forms and files", func(t *testing.T) {
+		resp, err := c.Call(t.Context(), "uploadPhoto", map[string]any{
+			"id":      7,
+			"caption": "portrait",
+			"photo":   runtime.NamedReader("rex.jpg", strings.NewReader("jpeg")),
+		})
+		require.NoError(t, err)
+		assert.EqualT(t, http.StatusNoContent, resp.Code)
+		assert.Nil(t, resp.Payload)
+		assert.EqualT(t, "/api/pets/7/photo", recorded.uri)
+		assert.Equal(t, []string{"portrait"}, recorded.form["caption"])
+		assert.EqualT(t, "jpeg", recorded.file)
+	})
+
+	t.Run("should return declared error responses", func(t *testing.T) {
+		_, err := c.Call(t.Context(), "getPet", map[string]any{"id": 2})
+
+		apiErr, ok := runtime.AsAPIError(err)
+		require.TrueT(t, ok)
+		assert.EqualT(t, "getPet", apiErr.OperationName)
+		assert.EqualT(t, http.StatusNotFound, apiErr.Code)
+		assert.Equal(t, map[string]any{"message": "no such pet"}, apiErr.Payload)
+
+		resp, ok := apiErr.Response.(*Response)
+		require.TrueT(t, ok)
+		assert.EqualT(t, http.StatusNotFound, resp.Code)
+
+		_, err = c.Call(t.Context(), "addPet", map[string]any{"pet": map[string]any{"name": "conflict"}})
+		apiErr, ok = runtime.AsAPIError(err)
+		require.TrueT(t, ok)
+		assert.EqualT(t, http.StatusConflict, apiErr.Code)
+		assert.Equal(t, map[string]any{"message": "already exists"}, apiErr.Payload)
+	})
+
+	t.Run("should return undeclared responses", func(t *testing.T) {
+		_, err := c.Call(t.Context(), "getPet", map[string]any{"id": 5})
+
+		apiErr, ok := runtime.AsAPIError(err)
+		require.TrueT(t, ok)
+		assert.EqualT(t, http.StatusTeapot, apiErr.Code)
+	})
+
+	t.Run("should validate parameters", func(t *testing.T) {
+		recorded.method = ""
+		_, err := c.Call(t.Context(), "getPet", map[string]any{
+			"id":           0,
+			"X-Request-Id": "too long for this header",
+		})
+
+		var composite *errors.CompositeError
+		require.ErrorAs(t, err, &composite)
+		assert.Len(t, composite.Errors, 2)
+
+		_, err = c.Call(t.Context(), "getPet", map[string]any{"id": "one"})
+		require.ErrorAs(t, err, &composite)
+		assert.StringContainsT(t, err.Error(), "id")
+
+		_, err = c.Call(t.Context(), "getPet", nil)
+		require.ErrorAs(t, err, &composite)
+		assert.StringContainsT(t, err.Error(), "is required")
+
+		_, err = c.Call(t.Context(), "addPet", map[string]any{"pet": map[string]any{"name": ""}})
+		require.ErrorAs(t, err, &composite)
+
+		// no request was sent
+		assert.Empty(t, recorded.method)
+	})
+
+	t.Run("should reject unknown operations and parameters", func(t *testing.T) {
+		_, err := c.Call(t.Context(), "deletePet", nil)
+		require.ErrorIs(t, err, ErrUnknownOperation)
+
+		_, err = c.Call(t.Context(), "getPet", map[string]any{"id": 1, "limit": 10})
+		require.ErrorIs(t, err, ErrUnknownParameter)
+		assert.FalseT(t, stderrors.Is(err, ErrUnknownOperation))
+	})
+}
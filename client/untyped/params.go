@@ -0,0 +1,263 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package untyped
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag/conv"
+	"github.com/go-openapi/swag/stringutils"
+	"github.com/go-openapi/validate"
+
+	"github.com/go-openapi/runtime"
+)
+
+const (
+	typeInteger = "integer"
+	typeNumber  = "number"
+	typeBoolean = "boolean"
+	typeString  = "string"
+	typeArray   = "array"
+	typeFile    = "file"
+)
+
+// boundParam is a parameter with its validated value.
+type boundParam struct {
+	param spec.Parameter
+	value any
+}
+
+// bindParams validates the values of the parameters of an operation, and returns the writer of the request.
+func (c *Client) bindParams(operationID string, declared map[string]spec.Parameter, values map[string]any) (runtime.ClientRequestWriter, error) {
+	byName := make(map[string]spec.Parameter, len(declared))
+	for _, param := range declared {
+		byName[param.Name] = param
+	}
+
+	for name := range values {
+		if _, ok := byName[name]; !ok {
+			return nil, fmt.Errorf("%w: %q for operation %q", ErrUnknownParameter, name, operationID)
+		}
+	}
+
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	bound := make([]boundParam, 0, len(values))
+	var errs []error
+	for _, name := range names {
+		param := byName[name]
+		value, ok := values[name]
+		if !ok || value == nil {
+			if param.Required {
+				errs = append(errs, errors.Required(param.Name, param.In, nil))
+			}
+
+			continue
+		}
+
+		value, err := c.validateParam(param, value)
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+		bound = append(bound, boundParam{param: param, value: value})
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.CompositeValidationError(errs...)
+	}
+
+	return runtime.ClientRequestWriterFunc(func(req runtime.ClientRequest, _ strfmt.Registry) error {
+		for _, b := range bound {
+			if err := writeParam(req, b.param, b.value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}), nil
+}
+
+// validateParam converts the value of a parameter to the type declared by the spec, and validates it.
+func (c *Client) validateParam(param spec.Parameter, value any) (any, error) {
+	if param.In == "body" {
+		if err := validate.NewSchemaValidator(param.Schema, c.spec.Spec(), param.Name, c.formats).Validate(value).AsError(); err != nil {
+			return nil, err
+		}
+
+		return value, nil
+	}
+
+	if param.Type == typeFile {
+		if _, ok := value.(io.Reader); !ok {
+			return nil, errors.InvalidType(param.Name, param.In, typeFile, value)
+		}
+
+		return value, nil
+	}
+
+	converted, ok := convertValue(param.Type, param.CollectionFormat, param.Items, value)
+	if !ok {
+		return nil, errors.InvalidType(param.Name, param.In, param.Type, value)
+	}
+
+	if err := validate.NewParamValidator(&param, c.formats).Validate(converted).AsError(); err != nil {
+		return nil, err
+	}
+
+	return converted, nil
+}
+
+// convertValue converts a value to the type of a simple schema: int64, float64, bool, string or []any.
+func convertValue(tpe, collectionFormat string, items *spec.Items, value any) (any, bool) {
+	str, isString := value.(string)
+	if !isString {
+		if stringer, ok := value.(fmt.Stringer); ok && tpe == typeString {
+			str, isString = stringer.String(), true
+		}
+	}
+
+	rv := reflect.ValueOf(value)
+	switch tpe {
+	case typeInteger:
+		switch {
+		case isString:
+			n, err := conv.ConvertInt64(str)
+			return n, err == nil
+		case rv.CanInt():
+			return rv.Int(), true
+		case rv.CanUint():
+			return int64(rv.Uint()), true //nolint:gosec // integer parameters are int64
+		case rv.CanFloat() && rv.Float() == float64(int64(rv.Float())):
+			return int64(rv.Float()), true
+		}
+	case typeNumber:
+		switch {
+		case isString:
+			n, err := conv.ConvertFloat64(str)
+			return n, err == nil
+		case rv.CanInt():
+			return float64(rv.Int()), true
+		case rv.CanUint():
+			return float64(rv.Uint()), true
+		case rv.CanFloat():
+			return rv.Float(), true
+		}
+	case typeBoolean:
+		switch {
+		case isString:
+			b, err := conv.ConvertBool(str)
+			return b, err == nil
+		case rv.Kind() == reflect.Bool:
+			return rv.Bool(), true
+		}
+	case typeString:
+		return str, isString
+	case typeArray:
+		return convertArray(collectionFormat, items, value)
+	}
+
+	return nil, false
+}
+
+func convertArray(collectionFormat string, items *spec.Items, value any) (any, bool) {
+	if items == nil {
+		return nil, false
+	}
+
+	var elems []any
+	if str, ok := value.(string); ok {
+		for _, elem := range stringutils.SplitByFormat(str, collectionFormat) {
+			elems = append(elems, elem)
+		}
+	} else {
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return nil, false
+		}
+		elems = make([]any, 0, rv.Len())
+		for i := range rv.Len() {
+			elems = append(elems, rv.Index(i).Interface())
+		}
+	}
+
+	result := make([]any, 0, len(elems))
+	for _, elem := range elems {
+		converted, ok := convertValue(items.Type, items.CollectionFormat, items.Items, elem)
+		if !ok {
+			return nil, false
+		}
+		result = append(result, converted)
+	}
+
+	return result, true
+}
+
+// formatValue formats a converted value, with the collection format of arrays.
+func formatValue(collectionFormat string, items *spec.Items, value any) []string {
+	switch v := value.(type) {
+	case int64:
+		return []string{strconv.FormatInt(v, 10)}
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+	case bool:
+		return []string{strconv.FormatBool(v)}
+	case string:
+		return []string{v}
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, elem := range v {
+			if items != nil {
+				values = append(values, formatValue(items.CollectionFormat, items.Items, elem)...)
+			}
+		}
+
+		return stringutils.JoinByFormat(values, collectionFormat)
+	default:
+		return nil
+	}
+}
+
+func writeParam(req runtime.ClientRequest, param spec.Parameter, value any) error {
+	switch param.In {
+	case "body":
+		return req.SetBodyParam(value)
+	case "formData":
+		if param.Type == typeFile {
+			file, ok := value.(runtime.NamedReadCloser)
+			if !ok {
+				file = runtime.NamedReader(param.Name, value.(io.Reader))
+			}
+
+			return req.SetFileParam(param.Name, file)
+		}
+
+		return req.SetFormParam(param.Name, formatValue(param.CollectionFormat, param.Items, value)...)
+	case "query":
+		return req.SetQueryParam(param.Name, formatValue(param.CollectionFormat, param.Items, value)...)
+	}
+
+	// path and header parameters take a single value
+	var formatted string
+	if values := formatValue(param.CollectionFormat, param.Items, value); len(values) > 0 {
+		formatted = values[0]
+	}
+	if param.In == "path" {
+		return req.SetPathParam(param.Name, formatted)
+	}
+
+	return req.SetHeaderParam(param.Name, formatted)
+}
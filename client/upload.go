@@ -0,0 +1,379 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/middleware"
+)
+
+const (
+	// DefaultUploadChunkSize is the size of the chunks sent by [Runtime.Upload].
+	DefaultUploadChunkSize = 8 << 20
+
+	defaultUploadAttempts = 5
+	defaultUploadBackoff  = 500 * time.Millisecond
+	uploadOperationName   = "resumable upload"
+)
+
+// ErrUploadNotResumable is returned by [Runtime.Upload] when the server doesn't create a resumable upload.
+var ErrUploadNotResumable = errors.New("the server does not support resumable uploads")
+
+// errUploadInterrupted marks the failures after which an upload may be resumed.
+var errUploadInterrupted = errors.New("upload interrupted")
+
+// UploadOption configures a resumable upload with [Runtime.Upload].
+type UploadOption func(*uploadOpts)
+
+type uploadOpts struct {
+	attempts  int
+	backoff   time.Duration
+	chunkSize int64
+	progress  func(sent, total int64)
+}
+
+// WithUploadAttempts sets the maximum number of consecutive failed requests before an upload is abandoned (default: 5).
+func WithUploadAttempts(attempts int) UploadOption {
+	return func(o *uploadOpts) {
+		if attempts > 0 {
+			o.attempts = attempts
+		}
+	}
+}
+
+// WithUploadBackoff sets the delay before resuming an interrupted upload (default: 500ms).
+func WithUploadBackoff(backoff time.Duration) UploadOption {
+	return func(o *uploadOpts) {
+		o.backoff = backoff
+	}
+}
+
+// WithUploadChunkSize sets the size of the chunks of the upload (default: [DefaultUploadChunkSize]).
+//
+// Once interrupted, the chunk being sent is resumed from the last byte received by the server.
+func WithUploadChunkSize(size int64) UploadOption {
+	return func(o *uploadOpts) {
+		if size > 0 {
+			o.chunkSize = size
+		}
+	}
+}
+
+// WithUploadProgress sets a function which receives the number of bytes of the request body
+// acknowledged by the server, after each chunk.
+func WithUploadProgress(fn func(sent, total int64)) UploadOption {
+	return func(o *uploadOpts) {
+		o.progress = fn
+	}
+}
+
+// Upload submits an operation with a resumable upload of its request body, e.g. a large multipart/form-data body.
+//
+// The request is built as with [Runtime.SubmitContext], and its body is spooled to a temporary file.
+// The upload is then created with the tus protocol, and the body is sent in chunks. Whenever a chunk is interrupted
+// (e.g. the connection is lost, or the server fails), the upload is resumed from the offset reported by the server.
+// The server side is implemented by [middleware.ResumableUploads].
+//
+// The response to the last chunk is the response of the operation, which is handed over to the operation's
+// response reader, so the usual result and errors are returned.
+//
+// The headers and query of the operation's request, including credentials, are sent with each request of the upload.
+// The per-request timeout set by parameters applies to each request of the upload.
+// An abandoned upload is terminated on the server.
+func (r *Runtime) Upload(ctx context.Context, operation *runtime.ClientOperation, opts ...UploadOption) (any, error) {
+	o := uploadOpts{
+		attempts:  defaultUploadAttempts,
+		backoff:   defaultUploadBackoff,
+		chunkSize: DefaultUploadChunkSize,
+	}
+	for _, apply := range opts {
+		apply(&o)
+	}
+
+	req, cancel, err := r.createHTTPRequestContext(ctx, operation)
+	if err != nil {
+		return nil, err
+	}
+
+	var timeout time.Duration
+	if deadline, ok := req.Context().Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+
+	body, size, err := spoolBody(req)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = body.Close()
+		_ = os.Remove(body.Name())
+	}()
+
+	r.ensureClient()
+	u := &resumableUpload{
+		runtime:   r,
+		operation: operation,
+		client:    r.pickClient(operation),
+		target:    req,
+		body:      body,
+		size:      size,
+		timeout:   timeout,
+		opts:      o,
+	}
+
+	return u.run(ctx)
+}
+
+// spoolBody copies the body of a request to a temporary file.
+func spoolBody(req *http.Request) (*os.File, int64, error) {
+	spool, err := os.CreateTemp("", "upload-*")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var size int64
+	if req.Body != nil {
+		size, err = io.Copy(spool, req.Body)
+		err = errors.Join(err, req.Body.Close())
+	}
+	if err != nil {
+		return nil, 0, errors.Join(err, spool.Close(), os.Remove(spool.Name()))
+	}
+
+	return spool, size, nil
+}
+
+// resumableUpload holds the state of an upload with [Runtime.Upload].
+type resumableUpload struct {
+	runtime   *Runtime
+	operation *runtime.ClientOperation
+	client    *http.Client
+	target    *http.Request // the request of the operation
+	body      *os.File
+	size      int64
+	timeout   time.Duration
+	opts      uploadOpts
+	location  *url.URL
+	offset    int64
+}
+
+func (u *resumableUpload) run(ctx context.Context) (any, error) {
+	resync := false
+	for failures := 0; ; {
+		var err error
+		switch {
+		case u.location == nil:
+			err = u.create(ctx)
+		case resync:
+			err = u.resync(ctx)
+		}
+
+		if err == nil {
+			resync = false
+
+			var result any
+			var done bool
+			if result, done, err = u.sendChunk(ctx); done {
+				return result, err
+			}
+		}
+
+		if err == nil {
+			failures = 0
+
+			continue
+		}
+
+		failures++
+		if !errors.Is(err, errUploadInterrupted) || failures >= u.opts.attempts || ctx.Err() != nil {
+			u.terminate(ctx)
+
+			return nil, err
+		}
+
+		resync = true
+		timer := time.NewTimer(u.opts.backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			u.terminate(ctx)
+
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// create sends the request of the operation without its body, to create the upload.
+func (u *resumableUpload) create(ctx context.Context) error {
+	req, cancel, err := u.newRequest(ctx, u.target.Method, u.target.URL, nil, 0)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	req.Header.Set(middleware.HeaderUploadLength, strconv.FormatInt(u.size, 10))
+
+	res, err := u.do(req)
+	if err != nil {
+		return err
+	}
+
+	location, err := url.Parse(res.Header.Get("Location"))
+	if res.StatusCode != http.StatusCreated || err != nil || location.String() == "" {
+		return fmt.Errorf("%w: %w", ErrUploadNotResumable, runtime.NewAPIError(uploadOperationName, nil, res.StatusCode))
+	}
+
+	u.location = u.target.URL.ResolveReference(location)
+	if u.location.RawQuery == "" {
+		// the query of the operation goes with every request, like its headers, e.g. API keys
+		u.location.RawQuery = u.target.URL.RawQuery
+	}
+	u.offset = 0
+
+	return nil
+}
+
+// resync fetches the offset of the upload on the server.
+func (u *resumableUpload) resync(ctx context.Context) error {
+	req, cancel, err := u.newRequest(ctx, http.MethodHead, u.location, nil, 0)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	res, err := u.do(req)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return runtime.NewAPIError(uploadOperationName, nil, res.StatusCode)
+	}
+
+	return u.acknowledge(res)
+}
+
+// sendChunk sends the next chunk of the upload. With the last chunk, the response of the operation is read.
+func (u *resumableUpload) sendChunk(ctx context.Context) (any, bool, error) {
+	n := min(u.opts.chunkSize, u.size-u.offset)
+	chunk := io.NewSectionReader(u.body, u.offset, n)
+
+	req, cancel, err := u.newRequest(ctx, http.MethodPatch, u.location, chunk, n)
+	if err != nil {
+		return nil, false, err
+	}
+	defer cancel()
+
+	req.Header.Set(runtime.HeaderContentType, middleware.OffsetOctetStreamMime)
+	req.Header.Set(middleware.HeaderUploadOffset, strconv.FormatInt(u.offset, 10))
+
+	res, err := u.client.Do(req)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %w", errUploadInterrupted, err)
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode >= http.StatusInternalServerError || res.StatusCode == http.StatusConflict:
+		return nil, false, fmt.Errorf("%w: %w", errUploadInterrupted, runtime.NewAPIError(uploadOperationName, nil, res.StatusCode))
+	case u.offset+n == u.size:
+		u.offset = u.size
+		u.report()
+		result, err := u.runtime.readResponse(u.target, res, u.operation)
+
+		return result, true, err
+	case res.StatusCode == http.StatusNoContent:
+		return nil, false, u.acknowledge(res)
+	default:
+		return nil, false, runtime.NewAPIError(uploadOperationName, nil, res.StatusCode)
+	}
+}
+
+// acknowledge records the offset of the upload on the server.
+func (u *resumableUpload) acknowledge(res *http.Response) error {
+	offset, err := strconv.ParseInt(res.Header.Get(middleware.HeaderUploadOffset), 10, 64)
+	if err != nil || offset < 0 || offset > u.size {
+		return fmt.Errorf("%w: invalid %s: %q", errUploadInterrupted, middleware.HeaderUploadOffset, res.Header.Get(middleware.HeaderUploadOffset))
+	}
+
+	u.offset = offset
+	u.report()
+
+	return nil
+}
+
+// terminate deletes an abandoned upload on the server, on a best-effort basis.
+func (u *resumableUpload) terminate(ctx context.Context) {
+	if u.location == nil {
+		return
+	}
+
+	req, cancel, err := u.newRequest(context.WithoutCancel(ctx), http.MethodDelete, u.location, nil, 0)
+	if err != nil {
+		return
+	}
+	defer cancel()
+
+	if res, err := u.client.Do(req); err == nil {
+		_ = res.Body.Close()
+	}
+}
+
+func (u *resumableUpload) report() {
+	if u.opts.progress != nil {
+		u.opts.progress(u.offset, u.size)
+	}
+}
+
+// newRequest creates a request of the upload, with the headers of the operation's request.
+func (u *resumableUpload) newRequest(ctx context.Context, method string, target *url.URL, body io.Reader, length int64) (*http.Request, context.CancelFunc, error) {
+	cancel := context.CancelFunc(func() {})
+	if u.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, u.timeout)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), body)
+	if err != nil {
+		cancel()
+
+		return nil, nil, err
+	}
+
+	req.Header = u.target.Header.Clone()
+	req.Header.Set(middleware.HeaderTusResumable, middleware.TusVersion)
+	req.Host = u.target.Host
+	req.ContentLength = length
+	if length == 0 {
+		req.Body = http.NoBody
+	}
+
+	return req, cancel, nil
+}
+
+// do sends a request without a meaningful response body.
+func (u *resumableUpload) do(req *http.Request) (*http.Response, error) {
+	res, err := u.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errUploadInterrupted, err)
+	}
+	_ = res.Body.Close()
+
+	if res.StatusCode >= http.StatusInternalServerError {
+		return nil, fmt.Errorf("%w: %w", errUploadInterrupted, runtime.NewAPIError(uploadOperationName, nil, res.StatusCode))
+	}
+
+	return res, nil
+}
@@ -0,0 +1,248 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/runtime/middleware/untyped"
+)
+
+var errConnectionLost = errors.New("connection lost")
+
+// uploadSpec declares the operation accepting resumable uploads.
+const uploadSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "uploads", "version": "1.0"},
+  "basePath": "/api",
+  "paths": {
+    "/documents": {
+      "post": {
+        "consumes": ["multipart/form-data"],
+        "parameters": [
+          {"name": "title", "in": "formData", "type": "string"},
+          {"name": "document", "in": "formData", "type": "file"}
+        ],
+        "responses": {"201": {"description": "created"}}
+      }
+    }
+  }
+}`
+
+// flakyTransport loses the connection while sending some chunks of an upload:
+// only half of the chunk reaches the server, and the response is lost.
+type flakyTransport struct {
+	mu      sync.Mutex
+	flaky   map[int]bool // the PATCH requests to interrupt, by rank
+	patches int
+	methods []string
+}
+
+func (f *flakyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	f.methods = append(f.methods, req.Method)
+	interrupt := false
+	if req.Method == http.MethodPatch {
+		f.patches++
+		interrupt = f.flaky[f.patches]
+	}
+	f.mu.Unlock()
+
+	if !interrupt {
+		return http.DefaultTransport.RoundTrip(req)
+	}
+
+	half := req.ContentLength / 2
+	partial := req.Clone(req.Context())
+	partial.Body = io.NopCloser(io.LimitReader(req.Body, half))
+	partial.ContentLength = half
+	res, err := http.DefaultTransport.RoundTrip(partial)
+	if err == nil {
+		_ = res.Body.Close()
+	}
+
+	return nil, errConnectionLost
+}
+
+func TestRuntime_Upload(t *testing.T) {
+	document := strings.Repeat("0123456789", 1000)
+
+	dir := t.TempDir()
+	api := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get(runtime.HeaderAuthorization) != "Bearer token" {
+			rw.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		file, _, err := req.FormFile("document")
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+		content, _ := io.ReadAll(file)
+
+		rw.Header().Set(runtime.HeaderContentType, runtime.JSONMime)
+		rw.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(rw).Encode(map[string]any{
+			"title":   req.FormValue("title"),
+			"matches": string(content) == document,
+			"path":    req.URL.Path,
+		})
+	})
+	doc, err := loads.Analyzed(json.RawMessage(uploadSpec), "")
+	require.NoError(t, err)
+	routes := untyped.NewAPI(doc)
+	routes.RegisterOperation(http.MethodPost, "/documents", runtime.OperationHandlerFunc(func(any) (any, error) { return nil, nil }))
+	server := httptest.NewServer(middleware.ResumableUploads(dir, api,
+		middleware.WithResumableRoutes(middleware.NewContext(doc, routes, nil)),
+		middleware.WithResumableQuota(1<<20),
+	))
+	t.Cleanup(server.Close)
+
+	hu, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	upload := func(transport http.RoundTripper, pathPattern string, opts ...UploadOption) (map[string]any, error) {
+		rt := New(hu.Host, "/api", []string{schemeHTTP})
+		rt.Transport = transport
+		rt.DefaultAuthentication = runtime.ClientAuthInfoWriterFunc(func(req runtime.ClientRequest, _ strfmt.Registry) error {
+			return req.SetHeaderParam(runtime.HeaderAuthorization, "Bearer token")
+		})
+
+		result, err := rt.Upload(t.Context(), &runtime.ClientOperation{
+			ID:                 "uploadDocument",
+			Method:             http.MethodPost,
+			PathPattern:        pathPattern,
+			ConsumesMediaTypes: []string{runtime.MultipartFormMime},
+			ProducesMediaTypes: []string{runtime.JSONMime},
+			Params: runtime.ClientRequestWriterFunc(func(req runtime.ClientRequest, _ strfmt.Registry) error {
+				_ = req.SetFormParam("title", "report")
+
+				return req.SetFileParam("document", runtime.NamedReader("report.txt", strings.NewReader(document)))
+			}),
+			Reader: runtime.ClientResponseReaderFunc(func(resp runtime.ClientResponse, cons runtime.Consumer) (any, error) {
+				if resp.Code() != http.StatusCreated {
+					return nil, runtime.NewAPIError("uploadDocument", nil, resp.Code())
+				}
+
+				var result map[string]any
+				err := cons.Consume(resp.Body(), &result)
+
+				return result, err
+			}),
+		}, append([]UploadOption{WithUploadBackoff(0), WithUploadChunkSize(4096)}, opts...)...)
+		if err != nil {
+			return nil, err
+		}
+
+		return result.(map[string]any), nil
+	}
+
+	t.Run("should upload in chunks", func(t *testing.T) {
+		var sent []int64
+		result, err := upload(http.DefaultTransport, "/documents", WithUploadProgress(func(n, total int64) {
+			sent = append(sent, n)
+			assert.GreaterOrEqual(t, total, int64(len(document)))
+		}))
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{"title": "report", "matches": true, "path": "/api/documents"}, result)
+		require.Len(t, sent, 3)
+		assert.Equal(t, []int64{4096, 8192}, sent[:2])
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("should resume interrupted chunks", func(t *testing.T) {
+		transport := &flakyTransport{flaky: map[int]bool{1: true, 3: true, 4: true}}
+		var sent []int64
+		result, err := upload(transport, "/documents", WithUploadProgress(func(n, _ int64) {
+			sent = append(sent, n)
+		}))
+		require.NoError(t, err)
+
+		assert.Equal(t, true, result["matches"])
+		assert.Equal(t, []string{
+			http.MethodPost,
+			http.MethodPatch, http.MethodHead, // interrupted after 2048 bytes
+			http.MethodPatch,
+			http.MethodPatch, http.MethodHead, // interrupted twice in a row
+			http.MethodPatch, http.MethodHead,
+			http.MethodPatch,
+		}, transport.methods)
+		assert.EqualT(t, int64(2048), sent[0])
+	})
+
+	t.Run("should give up and terminate the upload after the maximum number of attempts", func(t *testing.T) {
+		transport := &flakyTransport{flaky: map[int]bool{2: true, 3: true}}
+		_, err := upload(transport, "/documents", WithUploadAttempts(2))
+		require.ErrorIs(t, err, errConnectionLost)
+		assert.EqualT(t, http.MethodDelete, transport.methods[len(transport.methods)-1])
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("should report servers without resumable uploads", func(t *testing.T) {
+		plain := httptest.NewServer(api)
+		t.Cleanup(plain.Close)
+		pu, err := url.Parse(plain.URL)
+		require.NoError(t, err)
+
+		rt := New(pu.Host, "/", []string{schemeHTTP})
+		_, err = rt.Upload(t.Context(), &runtime.ClientOperation{
+			Method:      http.MethodPost,
+			PathPattern: "/documents",
+			Params: runtime.ClientRequestWriterFunc(func(req runtime.ClientRequest, _ strfmt.Registry) error {
+				return req.SetBodyParam(strings.NewReader(document))
+			}),
+		})
+		require.ErrorIs(t, err, ErrUploadNotResumable)
+
+		apiErr, ok := runtime.AsAPIError(err)
+		require.TrueT(t, ok)
+		assert.EqualT(t, http.StatusUnauthorized, apiErr.Code)
+	})
+
+	t.Run("should hand over error responses to the response reader", func(t *testing.T) {
+		rt := New(hu.Host, "/api", []string{schemeHTTP})
+		_, err := rt.Upload(t.Context(), &runtime.ClientOperation{
+			Method:      http.MethodPost,
+			PathPattern: "/documents",
+			Params: runtime.ClientRequestWriterFunc(func(req runtime.ClientRequest, _ strfmt.Registry) error {
+				return req.SetBodyParam(strings.NewReader(strconv.Itoa(42)))
+			}),
+			Reader: runtime.ClientResponseReaderFunc(func(resp runtime.ClientResponse, _ runtime.Consumer) (any, error) {
+				return nil, runtime.NewAPIError("uploadDocument", nil, resp.Code())
+			}),
+		})
+
+		apiErr, ok := runtime.AsAPIError(err)
+		require.TrueT(t, ok)
+		assert.EqualT(t, http.StatusUnauthorized, apiErr.Code)
+		assert.EqualT(t, "uploadDocument", apiErr.OperationName)
+	})
+}
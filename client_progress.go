@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import "github.com/go-openapi/strfmt"
+
+// FileProgress reports the progress of a file sent by a multipart client request.
+type FileProgress struct {
+	// FieldName is the name of the file parameter.
+	FieldName string
+
+	// Filename is the name of the file.
+	Filename string
+
+	// Size of the file, or -1 when unknown. The size is known for files which have a Stat method, like [os.File].
+	Size int64
+
+	// Sent is the number of bytes of the file sent so far.
+	Sent int64
+
+	// TotalSent is the number of bytes sent so far for all the files of the request.
+	TotalSent int64
+
+	// Done is true once the file has been sent entirely.
+	Done bool
+}
+
+// FileProgressFunc receives the progress of the files sent by a client request.
+//
+// It is called from the goroutine which streams the request body, every time some bytes have been sent.
+type FileProgressFunc func(FileProgress)
+
+// FileProgressRequest is implemented by client requests which report the progress of their file parameters.
+type FileProgressRequest interface {
+	SetFileProgress(FileProgressFunc)
+}
+
+// WithFileProgress wraps a [ClientRequestWriter] so that the progress of the files of the request is reported.
+//
+// Progress is reported when files are sent as multipart/form-data, and the request implements [FileProgressRequest],
+// like those of the client runtime. With a generated client, set it with a client option:
+//
+//	func(op *runtime.ClientOperation) { op.Params = runtime.WithFileProgress(op.Params, fn) }
+func WithFileProgress(params ClientRequestWriter, fn FileProgressFunc) ClientRequestWriter {
+	return ClientRequestWriterFunc(func(req ClientRequest, reg strfmt.Registry) error {
+		if progress, ok := req.(FileProgressRequest); ok {
+			progress.SetFileProgress(fn)
+		}
+
+		if params == nil {
+			return nil
+		}
+
+		return params.WriteToRequest(req, reg)
+	})
+}
@@ -5,8 +5,10 @@ package runtime
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
 )
 
@@ -36,10 +38,48 @@ type ClientResponseReader interface {
 }
 
 // APIError wraps an error model and captures the status code.
+//
+// When a response reader meets an undeclared status code, Response is usually
+// the [ClientResponse] itself. Transports may then capture more details about the
+// unexpected response (see the client Runtime option to buffer error bodies):
+// the request method and URL, the response headers, and the (possibly truncated)
+// response body.
 type APIError struct {
 	OperationName string
 	Response      any
 	Code          int
+
+	// Method of the request that failed
+	Method string
+
+	// URL of the request that failed, without the password of its user info,
+	// nor the query parameters set by its authentication (e.g. API keys)
+	URL string
+
+	// Header of the response
+	Header http.Header
+
+	// Body holds the raw response body, up to the limit configured on the transport
+	Body []byte
+
+	// BodyTruncated is true whenever Body holds only the first bytes of the response body
+	BodyTruncated bool
+
+	// Payload is the response body decoded by the consumer that matches the
+	// response content type, if any
+	Payload any
+}
+
+// AsAPIError finds the first [APIError] in the chain of err.
+//
+// This is a shorthand for [errors.As].
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+
+	return nil, false
 }
 
 // NewAPIError creates a new API error.
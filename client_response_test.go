@@ -6,6 +6,7 @@ package runtime
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"io/fs"
 	"testing"
@@ -117,3 +118,22 @@ func TestResponseReaderFuncError(t *testing.T) {
 		assert.StringContainsT(t, err.Error(), `{"a":555,"b":"closed"}`)
 	})
 }
+
+func TestAsAPIError(t *testing.T) {
+	t.Run("should find a wrapped API error", func(t *testing.T) {
+		apiErr := NewAPIError("fake", nil, 404)
+
+		found, ok := AsAPIError(fmt.Errorf("wrapped: %w", apiErr))
+		require.TrueT(t, ok)
+		assert.True(t, found == apiErr)
+	})
+
+	t.Run("should not find an API error", func(t *testing.T) {
+		found, ok := AsAPIError(errors.New("plain"))
+		require.FalseT(t, ok)
+		assert.Nil(t, found)
+
+		_, ok = AsAPIError(nil)
+		require.FalseT(t, ok)
+	})
+}
@@ -39,4 +39,6 @@ const (
 	MultipartFormMime = "multipart/form-data"
 	// URLencodedFormMime is the [url] encoded form mime type.
 	URLencodedFormMime = "application/x-www-form-urlencoded"
+	// ProblemJSONMime the RFC 9457 problem details mime type.
+	ProblemJSONMime = "application/problem+json"
 )
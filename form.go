@@ -82,6 +82,7 @@ type bindConfig struct {
 
 	maxParseMemory int64
 	files          []formFileSpec
+	inspector      *UploadInspector
 }
 
 type formFileSpec struct {
@@ -153,6 +154,16 @@ func BindFormFile(name string, required bool, bind FileBinder) BindOption {
 	}
 }
 
+// BindFormInspect inspects the declared file fields before their binders run
+// (see [UploadInspector]).
+//
+// Each file is read once to be inspected, then rewound. Its header then carries the
+// detected Content-Type and its Content-Digest. An inspection failure is a per-field
+// error, either an [*UploadTypeError], an [*UploadScanError] or an [*UploadScanFailure].
+func BindFormInspect(inspector *UploadInspector) BindOption {
+	return func(c *bindConfig) { c.inspector = inspector }
+}
+
 // BindForm parses r as multipart/form-data, falling back to
 // application/x-www-form-urlencoded when the request is not
 // multipart. On success, r.MultipartForm and r.PostForm are populated;
@@ -231,7 +242,7 @@ func BindForm(r *http.Request, opts ...BindOption) (fatal bool, err error) {
 
 	var bindErrs []error
 	for _, spec := range cfg.files {
-		if e := bindFormFile(r, spec, cfg.maxFilenameLen); e != nil {
+		if e := bindFormFile(r, spec, cfg.maxFilenameLen, cfg.inspector); e != nil {
 			bindErrs = append(bindErrs, e)
 		}
 	}
@@ -336,7 +347,7 @@ type urlencodedFile struct {
 
 func (urlencodedFile) Close() error { return nil }
 
-func bindFormFile(r *http.Request, spec formFileSpec, maxFilenameLen int) error {
+func bindFormFile(r *http.Request, spec formFileSpec, maxFilenameLen int, inspector *UploadInspector) error {
 	file, header, err := FormFile(r, spec.name)
 	if err != nil {
 		if stderrors.Is(err, http.ErrMissingFile) {
@@ -354,6 +365,12 @@ func bindFormFile(r *http.Request, spec formFileSpec, maxFilenameLen int) error
 		return err
 	}
 
+	if inspector != nil {
+		if _, err := inspector.InspectFile(r.Context(), spec.name, file, header); err != nil {
+			return err
+		}
+	}
+
 	if spec.bind == nil {
 		return nil
 	}
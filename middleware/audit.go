@@ -0,0 +1,362 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	stdContext "context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/go-openapi/spec"
+)
+
+// RedactedValue replaces the value of sensitive parameters in [AuditEntry.Params].
+const RedactedValue = "[REDACTED]"
+
+const (
+	formatPassword     = "password"
+	maxRedactionDepth  = 32
+	auditParamsPresize = 8
+)
+
+// AuditEntry is the record produced for every operation served by a [Context]
+// configured with [Context.SetAuditSink].
+//
+// Parameters are keyed by their name in the spec. Values for parameters (or body
+// properties) declared with "format: password" are replaced by [RedactedValue].
+type AuditEntry struct {
+	Time         time.Time      `json:"time"`
+	OperationID  string         `json:"operationId,omitempty"`
+	Method       string         `json:"method"`
+	Path         string         `json:"path"`
+	Principal    any            `json:"principal,omitempty"`
+	Scopes       []string       `json:"scopes,omitempty"`
+	Params       map[string]any `json:"params,omitempty"`
+	StatusCode   int            `json:"status"`
+	Latency      time.Duration  `json:"latency"`
+	ResponseSize int64          `json:"responseSize"`
+}
+
+// AuditSink receives batches of [AuditEntry].
+//
+// The [Context] hands over a single entry per served request: batching is left to
+// wrappers such as [AsyncAuditSink].
+//
+// Implementations must be safe for concurrent use.
+type AuditSink interface {
+	WriteAudit(ctx stdContext.Context, entries []AuditEntry) error
+}
+
+// AuditSinkFunc wraps a func as an [AuditSink].
+type AuditSinkFunc func(stdContext.Context, []AuditEntry) error
+
+// WriteAudit writes a batch of audit entries.
+func (fn AuditSinkFunc) WriteAudit(ctx stdContext.Context, entries []AuditEntry) error {
+	return fn(ctx, entries)
+}
+
+// SetAuditSink enables the audit trail for all operations served by this [Context].
+//
+// Once the response has been written, the sink receives the operation ID, the
+// authenticated principal and scopes, the bound parameters (redacted), the status
+// code, the latency and the size of the response body.
+//
+// Passing a nil sink disables the audit trail.
+//
+// Returns the receiver for fluent configuration:
+//
+//	ctx := middleware.NewContext(spec, api, nil).SetAuditSink(sink)
+func (c *Context) SetAuditSink(sink AuditSink) *Context {
+	c.auditSink = sink
+
+	return c
+}
+
+// auditState collects what the inner handlers learn about a request,
+// since they only propagate it downstream with shallow copies of the request.
+type auditState struct {
+	mx        sync.Mutex
+	bound     any
+	principal any
+	scopes    []string
+}
+
+func auditStateFrom(request *http.Request) *auditState {
+	st, _ := request.Context().Value(ctxAuditState).(*auditState)
+
+	return st
+}
+
+func (c *Context) auditBound(request *http.Request, bound any) {
+	st := auditStateFrom(request)
+	if st == nil {
+		return
+	}
+
+	st.mx.Lock()
+	st.bound = bound
+	st.mx.Unlock()
+}
+
+func (c *Context) auditPrincipal(request *http.Request, principal any, scopes []string) {
+	st := auditStateFrom(request)
+	if st == nil {
+		return
+	}
+
+	st.mx.Lock()
+	st.principal = principal
+	st.scopes = scopes
+	st.mx.Unlock()
+}
+
+// newAuditHandler wraps the operation executor. It runs after routing, so the
+// matched route is always available from the request.
+func newAuditHandler(ctx *Context, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		st := new(auditState)
+		r = r.WithContext(stdContext.WithValue(r.Context(), ctxAuditState, st))
+		rec := &auditResponseWriter{ResponseWriter: rw}
+
+		next.ServeHTTP(rec, r)
+
+		entry := AuditEntry{
+			Time:         start.UTC(),
+			Method:       r.Method,
+			Path:         r.URL.EscapedPath(),
+			StatusCode:   rec.Status(),
+			Latency:      time.Since(start),
+			ResponseSize: rec.size,
+		}
+
+		route := MatchedRouteFrom(r)
+		if route != nil && route.Operation != nil {
+			entry.OperationID = route.Operation.ID
+		}
+
+		st.mx.Lock()
+		entry.Principal = st.principal
+		entry.Scopes = st.scopes
+		if route != nil {
+			entry.Params = ctx.auditParams(route, st.bound)
+		}
+		st.mx.Unlock()
+
+		// the sink outlives the request: don't let a canceled request abort the audit trail
+		if err := ctx.auditSink.WriteAudit(stdContext.WithoutCancel(r.Context()), []AuditEntry{entry}); err != nil {
+			ctx.debugLogf("audit sink failed for %s %s: %v", r.Method, entry.Path, err)
+		}
+	})
+}
+
+// auditParams builds the redacted parameters map from the bound parameters.
+//
+// The untyped API binds parameters into a map keyed by parameter name. Typed
+// (generated) APIs bind into a struct, whose fields are matched against the spec
+// parameter names.
+func (c *Context) auditParams(route *MatchedRoute, bound any) map[string]any {
+	if bound == nil || len(route.Parameters) == 0 {
+		return nil
+	}
+
+	params := make(map[string]any, auditParamsPresize)
+	switch b := bound.(type) {
+	case map[string]any:
+		for _, param := range route.Parameters {
+			if v, ok := b[param.Name]; ok {
+				params[param.Name] = c.redactParam(param, v)
+			}
+		}
+	default:
+		val := reflect.Indirect(reflect.ValueOf(bound))
+		if val.Kind() != reflect.Struct {
+			return nil
+		}
+
+		fields := make(map[string]reflect.Value, val.NumField())
+		for i := range val.NumField() {
+			field := val.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fields[normalizeAuditName(field.Name)] = val.Field(i)
+		}
+
+		for _, param := range route.Parameters {
+			fv, ok := fields[normalizeAuditName(param.Name)]
+			if !ok || !fv.CanInterface() {
+				continue
+			}
+			params[param.Name] = c.redactParam(param, fv.Interface())
+		}
+	}
+
+	if len(params) == 0 {
+		return nil
+	}
+
+	return params
+}
+
+func (c *Context) redactParam(param spec.Parameter, value any) any {
+	if param.In != "body" {
+		if param.Format == formatPassword || (param.Items != nil && param.Items.Format == formatPassword) {
+			return RedactedValue
+		}
+
+		return value
+	}
+
+	if param.Schema == nil {
+		return value
+	}
+
+	var root *spec.Swagger
+	if c.spec != nil {
+		root = c.spec.Spec()
+	}
+
+	return redactSchema(root, param.Schema, toAuditValue(value), 0)
+}
+
+// toAuditValue converts typed bodies to a generic representation that can be walked
+// against the schema, using the same field naming as the JSON encoding.
+func toAuditValue(value any) any {
+	switch value.(type) {
+	case nil, map[string]any, []any, string, bool, float64:
+		return value
+	}
+
+	buf, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+
+	var generic any
+	if err := json.Unmarshal(buf, &generic); err != nil {
+		return value
+	}
+
+	return generic
+}
+
+func redactSchema(root *spec.Swagger, schema *spec.Schema, value any, depth int) any {
+	if schema == nil || value == nil || depth > maxRedactionDepth {
+		return value
+	}
+
+	if schema.Ref.String() != "" && root != nil {
+		resolved, err := spec.ResolveRef(root, &schema.Ref)
+		if err != nil {
+			return value
+		}
+		schema = resolved
+	}
+
+	if schema.Format == formatPassword {
+		return RedactedValue
+	}
+
+	for i := range schema.AllOf {
+		value = redactSchema(root, &schema.AllOf[i], value, depth+1)
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		redacted := make(map[string]any, len(v))
+		for k, pv := range v {
+			if ps, ok := schema.Properties[k]; ok {
+				redacted[k] = redactSchema(root, &ps, pv, depth+1)
+				continue
+			}
+
+			if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+				redacted[k] = redactSchema(root, schema.AdditionalProperties.Schema, pv, depth+1)
+				continue
+			}
+
+			redacted[k] = pv
+		}
+
+		return redacted
+	case []any:
+		if schema.Items == nil || schema.Items.Schema == nil {
+			return v
+		}
+
+		redacted := make([]any, len(v))
+		for i, item := range v {
+			redacted[i] = redactSchema(root, schema.Items.Schema, item, depth+1)
+		}
+
+		return redacted
+	default:
+		return value
+	}
+}
+
+// normalizeAuditName folds a name so that a spec parameter name like "pet_id"
+// matches a generated field name like "PetID".
+func normalizeAuditName(name string) string {
+	var b strings.Builder
+	b.Grow(len(name))
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+
+	return b.String()
+}
+
+// auditResponseWriter captures the status code and the size of the response body.
+type auditResponseWriter struct {
+	http.ResponseWriter
+
+	status int
+	size   int64
+}
+
+func (w *auditResponseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *auditResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.size += int64(n)
+
+	return n, err
+}
+
+func (w *auditResponseWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+
+	return w.status
+}
+
+// Flush supports streaming responders.
+func (w *auditResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap supports [http.ResponseController].
+func (w *auditResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
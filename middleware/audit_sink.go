@@ -0,0 +1,286 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	stdContext "context"
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	defaultAuditBatchSize     = 100
+	defaultAuditFlushInterval = time.Second
+	defaultAuditQueueSize     = 1024
+	auditFileMode             = 0o600
+)
+
+// ErrAuditSinkClosed is returned when writing to an [AsyncAuditSink] that has been closed.
+var ErrAuditSinkClosed = stderrors.New("audit sink is closed")
+
+// MemoryAuditSink is an [AuditSink] that retains all entries in memory.
+//
+// This is mostly useful for testing.
+type MemoryAuditSink struct {
+	mx      sync.Mutex
+	entries []AuditEntry
+}
+
+// NewMemoryAuditSink creates an in-memory [AuditSink].
+func NewMemoryAuditSink() *MemoryAuditSink {
+	return &MemoryAuditSink{}
+}
+
+// WriteAudit retains a batch of entries.
+func (s *MemoryAuditSink) WriteAudit(_ stdContext.Context, entries []AuditEntry) error {
+	s.mx.Lock()
+	s.entries = append(s.entries, entries...)
+	s.mx.Unlock()
+
+	return nil
+}
+
+// Entries returns a copy of the retained entries.
+func (s *MemoryAuditSink) Entries() []AuditEntry {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	return slices.Clone(s.entries)
+}
+
+// Reset discards all retained entries.
+func (s *MemoryAuditSink) Reset() {
+	s.mx.Lock()
+	s.entries = nil
+	s.mx.Unlock()
+}
+
+// JSONLinesAuditSink is an [AuditSink] that writes entries as JSON lines
+// (one JSON document per line) to an [io.Writer].
+type JSONLinesAuditSink struct {
+	mx  sync.Mutex
+	w   io.Writer
+	enc *json.Encoder
+}
+
+// NewJSONLinesAuditSink creates an [AuditSink] that writes JSON lines to w.
+//
+// If w is an [io.Closer], it is closed by [JSONLinesAuditSink.Close].
+func NewJSONLinesAuditSink(w io.Writer) *JSONLinesAuditSink {
+	return &JSONLinesAuditSink{
+		w:   w,
+		enc: json.NewEncoder(w),
+	}
+}
+
+// OpenJSONLinesAuditFile opens (or creates) a file in append mode and returns
+// an [AuditSink] that writes JSON lines to it.
+func OpenJSONLinesAuditFile(name string) (*JSONLinesAuditSink, error) {
+	f, err := os.OpenFile(name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, auditFileMode)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewJSONLinesAuditSink(f), nil
+}
+
+// WriteAudit writes a batch of entries, one per line.
+func (s *JSONLinesAuditSink) WriteAudit(_ stdContext.Context, entries []AuditEntry) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	for _, entry := range entries {
+		if err := s.enc.Encode(entry); err != nil {
+			return err
+		}
+	}
+
+	if syncer, ok := s.w.(interface{ Sync() error }); ok {
+		return syncer.Sync()
+	}
+
+	return nil
+}
+
+// Close closes the underlying writer, if it is an [io.Closer].
+func (s *JSONLinesAuditSink) Close() error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if closer, ok := s.w.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// AsyncAuditOption configures an [AsyncAuditSink].
+type AsyncAuditOption func(*asyncAuditOptions)
+
+type asyncAuditOptions struct {
+	batchSize     int
+	flushInterval time.Duration
+	queueSize     int
+	onError       func(error)
+}
+
+// WithAuditBatchSize sets the maximum number of entries passed to the
+// underlying sink in one call (defaults to 100).
+func WithAuditBatchSize(size int) AsyncAuditOption {
+	return func(o *asyncAuditOptions) {
+		if size > 0 {
+			o.batchSize = size
+		}
+	}
+}
+
+// WithAuditFlushInterval sets the maximum delay before a partial batch is flushed
+// (defaults to 1s).
+func WithAuditFlushInterval(interval time.Duration) AsyncAuditOption {
+	return func(o *asyncAuditOptions) {
+		if interval > 0 {
+			o.flushInterval = interval
+		}
+	}
+}
+
+// WithAuditQueueSize sets the capacity of the queue of pending entries (defaults to 1024).
+//
+// Writers block when the queue is full.
+func WithAuditQueueSize(size int) AsyncAuditOption {
+	return func(o *asyncAuditOptions) {
+		if size > 0 {
+			o.queueSize = size
+		}
+	}
+}
+
+// WithAuditErrorHandler sets a callback invoked whenever the underlying sink fails.
+func WithAuditErrorHandler(fn func(error)) AsyncAuditOption {
+	return func(o *asyncAuditOptions) {
+		o.onError = fn
+	}
+}
+
+// AsyncAuditSink decouples the request path from a (possibly slow) [AuditSink].
+//
+// Entries are queued and handed over to the underlying sink in batches, whenever
+// the batch is full or the flush interval has elapsed.
+//
+// [AsyncAuditSink.Close] must be called to flush pending entries.
+type AsyncAuditSink struct {
+	next   AuditSink
+	opts   asyncAuditOptions
+	queue  chan AuditEntry
+	done   chan struct{}
+	mx     sync.RWMutex
+	closed bool
+}
+
+// NewAsyncAuditSink wraps an [AuditSink] with asynchronous batching.
+func NewAsyncAuditSink(next AuditSink, opts ...AsyncAuditOption) *AsyncAuditSink {
+	o := asyncAuditOptions{
+		batchSize:     defaultAuditBatchSize,
+		flushInterval: defaultAuditFlushInterval,
+		queueSize:     defaultAuditQueueSize,
+	}
+	for _, apply := range opts {
+		apply(&o)
+	}
+
+	s := &AsyncAuditSink{
+		next:  next,
+		opts:  o,
+		queue: make(chan AuditEntry, o.queueSize),
+		done:  make(chan struct{}),
+	}
+	go s.run()
+
+	return s
+}
+
+// WriteAudit queues entries for asynchronous delivery.
+func (s *AsyncAuditSink) WriteAudit(ctx stdContext.Context, entries []AuditEntry) error {
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	if s.closed {
+		return ErrAuditSinkClosed
+	}
+
+	for _, entry := range entries {
+		select {
+		case s.queue <- entry:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// Close flushes all pending entries and stops the background worker.
+//
+// If the underlying sink is an [io.Closer], it is closed too.
+func (s *AsyncAuditSink) Close() error {
+	s.mx.Lock()
+	if s.closed {
+		s.mx.Unlock()
+
+		return nil
+	}
+	s.closed = true
+	close(s.queue)
+	s.mx.Unlock()
+
+	<-s.done
+
+	if closer, ok := s.next.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+func (s *AsyncAuditSink) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.opts.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]AuditEntry, 0, s.opts.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		if err := s.next.WriteAudit(stdContext.Background(), batch); err != nil && s.opts.onError != nil {
+			s.opts.onError(err)
+		}
+		batch = make([]AuditEntry, 0, s.opts.batchSize)
+	}
+
+	for {
+		select {
+		case entry, ok := <-s.queue:
+			if !ok {
+				flush()
+
+				return
+			}
+
+			batch = append(batch, entry)
+			if len(batch) >= s.opts.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
@@ -0,0 +1,307 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	stdcontext "context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	apierrors "github.com/go-openapi/errors"
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/middleware/untyped"
+	"github.com/go-openapi/runtime/security"
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+const auditTestSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "audit", "version": "1.0"},
+  "basePath": "/api",
+  "consumes": ["application/json"],
+  "produces": ["application/json"],
+  "securityDefinitions": {"basic": {"type": "basic"}},
+  "paths": {
+    "/accounts": {
+      "post": {
+        "operationId": "createAccount",
+        "security": [{"basic": []}],
+        "parameters": [
+          {"name": "dryRun", "in": "query", "type": "boolean"},
+          {"name": "account", "in": "body", "required": true, "schema": {"$ref": "#/definitions/account"}}
+        ],
+        "responses": {"201": {"description": "created"}}
+      }
+    }
+  },
+  "definitions": {
+    "account": {
+      "type": "object",
+      "properties": {
+        "login": {"type": "string"},
+        "password": {"type": "string", "format": "password"},
+        "recovery": {"type": "array", "items": {"type": "string", "format": "password"}}
+      }
+    }
+  }
+}`
+
+type auditTestHandler struct{}
+
+func (auditTestHandler) Handle(_ any) (any, error) {
+	return map[string]any{"id": 1}, nil
+}
+
+func auditTestAPI(t *testing.T) (*loads.Document, *untyped.API) {
+	t.Helper()
+
+	doc, err := loads.Analyzed(json.RawMessage(auditTestSpec), "")
+	require.NoError(t, err)
+
+	api := untyped.NewAPI(doc)
+	api.RegisterAuth("basic", security.BasicAuth(func(user, pass string) (any, error) {
+		if user == "admin" && pass == "secret" {
+			return user, nil
+		}
+
+		return nil, apierrors.Unauthenticated("basic")
+	}))
+	api.RegisterOperation("post", "/accounts", auditTestHandler{})
+
+	return doc, api
+}
+
+func TestAuditTrail(t *testing.T) {
+	doc, api := auditTestAPI(t)
+	sink := NewMemoryAuditSink()
+	handler := NewContext(doc, api, nil).SetAuditSink(sink).APIHandler(nil)
+
+	t.Run("should record a successful operation with redacted params", func(t *testing.T) {
+		sink.Reset()
+		body := `{"login":"bob","password":"hunter2","recovery":["a","b"]}`
+		request := httptest.NewRequestWithContext(stdcontext.Background(), http.MethodPost, "/api/accounts?dryRun=true", strings.NewReader(body))
+		request.Header.Set(runtime.HeaderContentType, runtime.JSONMime)
+		request.SetBasicAuth("admin", "secret")
+		recorder := httptest.NewRecorder()
+
+		handler.ServeHTTP(recorder, request)
+		require.EqualT(t, http.StatusCreated, recorder.Code, recorder.Body.String())
+
+		entries := sink.Entries()
+		require.Len(t, entries, 1)
+		entry := entries[0]
+
+		assert.EqualT(t, "createAccount", entry.OperationID)
+		assert.EqualT(t, http.MethodPost, entry.Method)
+		assert.EqualT(t, "/api/accounts", entry.Path)
+		assert.Equal(t, "admin", entry.Principal)
+		assert.EqualT(t, http.StatusCreated, entry.StatusCode)
+		assert.EqualT(t, int64(recorder.Body.Len()), entry.ResponseSize)
+		assert.Positive(t, entry.Latency)
+
+		require.NotNil(t, entry.Params)
+		assert.Equal(t, true, entry.Params["dryRun"])
+
+		account, ok := entry.Params["account"].(map[string]any)
+		require.TrueT(t, ok)
+		assert.Equal(t, "bob", account["login"])
+		assert.Equal(t, RedactedValue, account["password"])
+		assert.Equal(t, []any{RedactedValue, RedactedValue}, account["recovery"])
+
+		// the bound parameters seen by the handler are not altered
+		assert.NotContains(t, recorder.Body.String(), RedactedValue)
+	})
+
+	t.Run("should record a failed authentication", func(t *testing.T) {
+		sink.Reset()
+		request := httptest.NewRequestWithContext(stdcontext.Background(), http.MethodPost, "/api/accounts", strings.NewReader(`{}`))
+		request.Header.Set(runtime.HeaderContentType, runtime.JSONMime)
+		request.SetBasicAuth("admin", "wrong")
+		recorder := httptest.NewRecorder()
+
+		handler.ServeHTTP(recorder, request)
+		require.EqualT(t, http.StatusUnauthorized, recorder.Code)
+
+		entries := sink.Entries()
+		require.Len(t, entries, 1)
+		assert.EqualT(t, "createAccount", entries[0].OperationID)
+		assert.EqualT(t, http.StatusUnauthorized, entries[0].StatusCode)
+		assert.Nil(t, entries[0].Principal)
+		assert.Nil(t, entries[0].Params)
+	})
+
+	t.Run("should not record unrouted requests", func(t *testing.T) {
+		sink.Reset()
+		request := httptest.NewRequestWithContext(stdcontext.Background(), http.MethodGet, "/api/unknown", nil)
+		recorder := httptest.NewRecorder()
+
+		handler.ServeHTTP(recorder, request)
+		require.EqualT(t, http.StatusNotFound, recorder.Code)
+		assert.Empty(t, sink.Entries())
+	})
+}
+
+func TestAuditParamsTyped(t *testing.T) {
+	doc, api := auditTestAPI(t)
+	ctx := NewContext(doc, api, nil)
+	ctx.router = DefaultRouter(doc, ctx.api)
+
+	route, ok := ctx.router.Lookup(http.MethodPost, "/api/accounts")
+	require.TrueT(t, ok)
+
+	// the untyped binder does not support password-formatted simple parameters,
+	// but typed binders do
+	route.Parameters = maps.Clone(route.Parameters)
+	route.Parameters["headerX-Token"] = *spec.HeaderParam("X-Token").Typed("string", "password")
+
+	type account struct {
+		Login    string `json:"login"`
+		Password string `json:"password"`
+	}
+	type createAccountParams struct {
+		HTTPRequest *http.Request `json:"-"`
+		XToken      *string
+		DryRun      *bool
+		Account     *account
+	}
+
+	token := "t0ken"
+	dryRun := false
+	params := ctx.auditParams(route, &createAccountParams{
+		XToken:  &token,
+		DryRun:  &dryRun,
+		Account: &account{Login: "bob", Password: "hunter2"},
+	})
+
+	assert.Equal(t, RedactedValue, params["X-Token"])
+	assert.Equal(t, &dryRun, params["dryRun"])
+	assert.Equal(t, map[string]any{"login": "bob", "password": RedactedValue}, params["account"])
+}
+
+func TestJSONLinesAuditSink(t *testing.T) {
+	t.Run("should write one JSON document per entry", func(t *testing.T) {
+		var buf bytes.Buffer
+		sink := NewJSONLinesAuditSink(&buf)
+
+		require.NoError(t, sink.WriteAudit(stdcontext.Background(), []AuditEntry{
+			{OperationID: "a", StatusCode: http.StatusOK},
+			{OperationID: "b", StatusCode: http.StatusNotFound},
+		}))
+		require.NoError(t, sink.Close())
+
+		scanner := bufio.NewScanner(&buf)
+		var ids []string
+		for scanner.Scan() {
+			var entry AuditEntry
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+			ids = append(ids, entry.OperationID)
+		}
+		assert.Equal(t, []string{"a", "b"}, ids)
+	})
+
+	t.Run("should append to a file", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), "audit.jsonl")
+
+		for _, id := range []string{"a", "b"} {
+			sink, err := OpenJSONLinesAuditFile(name)
+			require.NoError(t, err)
+			require.NoError(t, sink.WriteAudit(stdcontext.Background(), []AuditEntry{{OperationID: id}}))
+			require.NoError(t, sink.Close())
+		}
+
+		content, err := os.ReadFile(name)
+		require.NoError(t, err)
+		assert.Len(t, strings.Split(strings.TrimSpace(string(content)), "\n"), 2)
+	})
+
+	t.Run("should fail to open an invalid location", func(t *testing.T) {
+		_, err := OpenJSONLinesAuditFile(filepath.Join(t.TempDir(), "missing", "audit.jsonl"))
+		require.Error(t, err)
+	})
+}
+
+type batchRecorder struct {
+	mx      sync.Mutex
+	batches [][]AuditEntry
+	closed  bool
+}
+
+func (b *batchRecorder) WriteAudit(_ stdcontext.Context, entries []AuditEntry) error {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+	b.batches = append(b.batches, entries)
+
+	return nil
+}
+
+func (b *batchRecorder) Close() error {
+	b.closed = true
+
+	return nil
+}
+
+func TestAsyncAuditSink(t *testing.T) {
+	t.Run("should batch entries and flush on close", func(t *testing.T) {
+		rec := new(batchRecorder)
+		sink := NewAsyncAuditSink(rec, WithAuditBatchSize(2), WithAuditFlushInterval(time.Hour))
+
+		for range 5 {
+			require.NoError(t, sink.WriteAudit(stdcontext.Background(), []AuditEntry{{OperationID: "op"}}))
+		}
+		require.NoError(t, sink.Close())
+		require.NoError(t, sink.Close()) // idempotent
+
+		require.Len(t, rec.batches, 3)
+		assert.Len(t, rec.batches[0], 2)
+		assert.Len(t, rec.batches[1], 2)
+		assert.Len(t, rec.batches[2], 1)
+		assert.TrueT(t, rec.closed)
+
+		err := sink.WriteAudit(stdcontext.Background(), []AuditEntry{{}})
+		require.ErrorIs(t, err, ErrAuditSinkClosed)
+	})
+
+	t.Run("should flush partial batches periodically", func(t *testing.T) {
+		rec := new(batchRecorder)
+		sink := NewAsyncAuditSink(rec, WithAuditBatchSize(100), WithAuditFlushInterval(10*time.Millisecond))
+		t.Cleanup(func() { _ = sink.Close() })
+
+		require.NoError(t, sink.WriteAudit(stdcontext.Background(), []AuditEntry{{OperationID: "op"}}))
+
+		require.Eventually(t, func() bool {
+			rec.mx.Lock()
+			defer rec.mx.Unlock()
+
+			return len(rec.batches) == 1
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("should report errors from the underlying sink", func(t *testing.T) {
+		failure := errors.New("disk full")
+		var reported error
+		sink := NewAsyncAuditSink(
+			AuditSinkFunc(func(stdcontext.Context, []AuditEntry) error { return failure }),
+			WithAuditErrorHandler(func(err error) { reported = err }),
+			WithAuditQueueSize(1),
+		)
+
+		require.NoError(t, sink.WriteAudit(stdcontext.Background(), []AuditEntry{{}}))
+		require.NoError(t, sink.Close())
+		require.ErrorIs(t, reported, failure)
+	})
+}
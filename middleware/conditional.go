@@ -0,0 +1,374 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/runtime/server-middleware/negotiate/header"
+)
+
+const (
+	headerETag              = "ETag"
+	headerLastModified      = "Last-Modified"
+	headerIfMatch           = "If-Match"
+	headerIfNoneMatch       = "If-None-Match"
+	headerIfModifiedSince   = "If-Modified-Since"
+	headerIfUnmodifiedSince = "If-Unmodified-Since"
+
+	weakPrefix = "W/"
+	etagHashed = 16 // number of bytes of the body digest retained in computed ETags
+
+	autoETagMaxSize = 1 << 20 // responses larger than this are not buffered to compute an ETag
+)
+
+// Conditional is implemented by responders or operation results that know the
+// validators of the representation they render, per RFC 9110 §8.8.
+//
+// The etag is an entity-tag including its quotes, and optionally its weakness
+// indicator (e.g. `"v1"` or `W/"v1"`). Either validator may be left empty.
+//
+// When responding with such a value, the [Context] sets the ETag and Last-Modified
+// response headers, and evaluates the conditional request headers If-Match,
+// If-None-Match, If-Modified-Since and If-Unmodified-Since.
+// The response is short-circuited with 304 (Not Modified) or 412 (Precondition Failed)
+// when these preconditions call for it.
+type Conditional interface {
+	Validators() (etag string, lastModified time.Time)
+}
+
+// WithValidators decorates an operation result (a payload or a [Responder])
+// with validators, so the [Context] may evaluate conditional requests against it.
+//
+// See [Conditional].
+func WithValidators(result any, etag string, lastModified time.Time) any {
+	return &conditionalResult{
+		result:       result,
+		etag:         etag,
+		lastModified: lastModified,
+	}
+}
+
+type conditionalResult struct {
+	result       any
+	etag         string
+	lastModified time.Time
+}
+
+func (c *conditionalResult) Validators() (string, time.Time) {
+	return c.etag, c.lastModified
+}
+
+// ConditionalError reports that a conditional request must be short-circuited,
+// with status 304 (Not Modified) or 412 (Precondition Failed).
+//
+// The [Context] renders a 304 without a body, and a 412 like any other error.
+type ConditionalError struct {
+	code         int
+	ETag         string
+	LastModified time.Time
+}
+
+func (e *ConditionalError) Error() string {
+	if e.code == http.StatusNotModified {
+		return "not modified"
+	}
+
+	return "precondition failed"
+}
+
+// Code returns the HTTP status code for this error.
+func (e *ConditionalError) Code() int32 {
+	return int32(e.code) //nolint:gosec // HTTP status codes fit in an int32
+}
+
+// CheckPreconditions evaluates the conditional headers of a request against the
+// current validators of the target resource, following RFC 9110 §13.2.2.
+//
+// It returns nil when the request may proceed, or a [*ConditionalError] to be
+// returned by the handler.
+//
+// This is typically used by handlers for state-changing operations (optimistic
+// concurrency with If-Match), which must check preconditions before applying changes.
+// Safe operations may simply return their result decorated with [WithValidators].
+func CheckPreconditions(r *http.Request, etag string, lastModified time.Time) error {
+	code := evaluatePreconditions(r, etag, lastModified)
+	if code == 0 {
+		return nil
+	}
+
+	return &ConditionalError{
+		code:         code,
+		ETag:         etag,
+		LastModified: lastModified,
+	}
+}
+
+// SetAutoETag toggles the computation of weak ETags from the produced body of
+// successful responses to GET requests, when the operation result doesn't
+// declare validators (see [Conditional]).
+//
+// With this enabled, responses are buffered in order to be hashed, and
+// If-None-Match is honored by replying 304 (Not Modified).
+//
+// Errors, [Responder] results (e.g. [RangeContent]) and streams ([io.Reader] results) are not
+// buffered, and neither are the bodies larger than 1 MiB, nor flushed responses: these are
+// written as they are produced, without an ETag.
+//
+// Default: false.
+//
+// Returns the receiver for fluent configuration:
+//
+//	ctx := middleware.NewContext(spec, api, nil).SetAutoETag(true)
+func (c *Context) SetAutoETag(enable bool) *Context {
+	c.autoETag = enable
+
+	return c
+}
+
+// respondConditional handles validators declared by the data to respond with.
+//
+// It returns the data to be responded, which is unwrapped from [WithValidators] or
+// replaced by a 412 error, and true whenever the response has already been written.
+func (c *Context) respondConditional(rw http.ResponseWriter, r *http.Request, data any) (any, bool) {
+	if err, ok := data.(error); ok {
+		var condErr *ConditionalError
+		if stderrors.As(err, &condErr) && condErr.code == http.StatusNotModified {
+			writeNotModified(rw, condErr.ETag, condErr.LastModified)
+
+			return nil, true
+		}
+
+		return data, false
+	}
+
+	conditional, ok := data.(Conditional)
+	if !ok {
+		return data, false
+	}
+
+	if wrapped, isWrapper := data.(*conditionalResult); isWrapper {
+		data = wrapped.result
+	}
+
+	etag, lastModified := conditional.Validators()
+	switch evaluatePreconditions(r, etag, lastModified) {
+	case http.StatusNotModified:
+		writeNotModified(rw, etag, lastModified)
+
+		return nil, true
+	case http.StatusPreconditionFailed:
+		return &ConditionalError{code: http.StatusPreconditionFailed, ETag: etag, LastModified: lastModified}, false
+	default:
+		setValidators(rw.Header(), etag, lastModified)
+
+		return data, false
+	}
+}
+
+// evaluatePreconditions follows the evaluation order of RFC 9110 §13.2.2.
+//
+// It returns 0 when the request may proceed.
+func evaluatePreconditions(r *http.Request, etag string, lastModified time.Time) int {
+	isSafe := r.Method == http.MethodGet || r.Method == http.MethodHead
+
+	// step 1 & 2: If-Match, or else If-Unmodified-Since
+	if ifMatch := header.ParseList(r.Header, headerIfMatch); len(ifMatch) > 0 {
+		if !matchETag(ifMatch, etag, true) {
+			return http.StatusPreconditionFailed
+		}
+	} else if since := header.ParseTime(r.Header, headerIfUnmodifiedSince); !since.IsZero() && !lastModified.IsZero() {
+		if lastModified.Truncate(time.Second).After(since) {
+			return http.StatusPreconditionFailed
+		}
+	}
+
+	// step 3 & 4: If-None-Match, or else If-Modified-Since
+	if ifNoneMatch := header.ParseList(r.Header, headerIfNoneMatch); len(ifNoneMatch) > 0 {
+		if matchETag(ifNoneMatch, etag, false) {
+			if isSafe {
+				return http.StatusNotModified
+			}
+
+			return http.StatusPreconditionFailed
+		}
+	} else if since := header.ParseTime(r.Header, headerIfModifiedSince); isSafe && !since.IsZero() && !lastModified.IsZero() {
+		if !lastModified.Truncate(time.Second).After(since) {
+			return http.StatusNotModified
+		}
+	}
+
+	return 0
+}
+
+// matchETag compares the current entity-tag against a list of entity-tags from a
+// conditional header, using either the strong or the weak comparison function
+// (RFC 9110 §8.8.3.2).
+func matchETag(candidates []string, etag string, strong bool) bool {
+	for _, candidate := range candidates {
+		if candidate == "*" {
+			return etag != ""
+		}
+
+		if etag == "" {
+			continue
+		}
+
+		if strong && (strings.HasPrefix(candidate, weakPrefix) || strings.HasPrefix(etag, weakPrefix)) {
+			continue
+		}
+
+		if strings.TrimPrefix(candidate, weakPrefix) == strings.TrimPrefix(etag, weakPrefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func setValidators(h http.Header, etag string, lastModified time.Time) {
+	if etag != "" {
+		h.Set(headerETag, etag)
+	}
+
+	if !lastModified.IsZero() {
+		h.Set(headerLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+}
+
+func writeNotModified(rw http.ResponseWriter, etag string, lastModified time.Time) {
+	h := rw.Header()
+	h.Del("Content-Type")
+	h.Del("Content-Length")
+	setValidators(h, etag, lastModified)
+	rw.WriteHeader(http.StatusNotModified)
+}
+
+// WeakETag computes a weak entity-tag from a representation.
+func WeakETag(body []byte) string {
+	sum := sha256.Sum256(body)
+
+	return fmt.Sprintf(`%s"%s"`, weakPrefix, base64.RawURLEncoding.EncodeToString(sum[:etagHashed]))
+}
+
+// bufferedForETag tells if the response for some data may be buffered to compute its ETag.
+func bufferedForETag(data any) bool {
+	switch data.(type) {
+	case error, Responder, io.Reader:
+		return false
+	default:
+		return true
+	}
+}
+
+// etagResponseWriter buffers a response to compute its weak ETag.
+//
+// Responses larger than autoETagMaxSize, or flushed, are passed through without an ETag.
+type etagResponseWriter struct {
+	rw          http.ResponseWriter
+	header      http.Header
+	status      int
+	buf         bytes.Buffer
+	passthrough bool
+}
+
+func newETagResponseWriter(rw http.ResponseWriter) *etagResponseWriter {
+	return &etagResponseWriter{
+		rw:     rw,
+		header: rw.Header(),
+	}
+}
+
+func (w *etagResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *etagResponseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+func (w *etagResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	if !w.passthrough && w.buf.Len()+len(b) > autoETagMaxSize {
+		if err := w.pass(); err != nil {
+			return 0, err
+		}
+	}
+
+	if w.passthrough {
+		return w.rw.Write(b)
+	}
+
+	return w.buf.Write(b)
+}
+
+// Flush supports streaming responders: the response is no longer buffered.
+func (w *etagResponseWriter) Flush() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	if !w.passthrough {
+		_ = w.pass()
+	}
+
+	if f, ok := w.rw.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap supports [http.ResponseController].
+func (w *etagResponseWriter) Unwrap() http.ResponseWriter {
+	return w.rw
+}
+
+// pass stops buffering: the status and the body buffered so far are written.
+func (w *etagResponseWriter) pass() error {
+	w.passthrough = true
+	w.rw.WriteHeader(w.status)
+	_, err := w.rw.Write(w.buf.Bytes())
+	w.buf = bytes.Buffer{}
+
+	return err
+}
+
+// finish sets the computed ETag on successful responses and replies 304 when
+// the client already holds the current representation.
+func (w *etagResponseWriter) finish(r *http.Request) {
+	if w.passthrough {
+		return
+	}
+
+	status := w.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	if status == http.StatusOK && w.header.Get(headerETag) == "" {
+		etag := WeakETag(w.buf.Bytes())
+		if ifNoneMatch := header.ParseList(r.Header, headerIfNoneMatch); len(ifNoneMatch) > 0 && matchETag(ifNoneMatch, etag, false) {
+			writeNotModified(w.rw, etag, time.Time{})
+
+			return
+		}
+
+		w.header.Set(headerETag, etag)
+	}
+
+	w.rw.WriteHeader(status)
+	_, _ = w.rw.Write(w.buf.Bytes())
+}
@@ -0,0 +1,278 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"bytes"
+	stdcontext "context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/internal/testing/petstore"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestEvaluatePreconditions(t *testing.T) {
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	before := modified.Add(-time.Hour).Format(http.TimeFormat)
+	after := modified.Add(time.Hour).Format(http.TimeFormat)
+	same := modified.Format(http.TimeFormat)
+
+	for _, tc := range []struct {
+		name     string
+		method   string
+		headers  map[string]string
+		etag     string
+		expected int
+	}{
+		{name: "no condition", method: http.MethodGet, etag: `"v1"`},
+		{name: "If-None-Match matches", method: http.MethodGet, etag: `"v1"`, headers: map[string]string{headerIfNoneMatch: `"v0", "v1"`}, expected: http.StatusNotModified},
+		{name: "If-None-Match matches weakly", method: http.MethodHead, etag: `"v1"`, headers: map[string]string{headerIfNoneMatch: `W/"v1"`}, expected: http.StatusNotModified},
+		{name: "If-None-Match star", method: http.MethodGet, etag: `"v1"`, headers: map[string]string{headerIfNoneMatch: `*`}, expected: http.StatusNotModified},
+		{name: "If-None-Match differs", method: http.MethodGet, etag: `"v1"`, headers: map[string]string{headerIfNoneMatch: `"v0"`}},
+		{name: "If-None-Match on unsafe method", method: http.MethodPut, etag: `"v1"`, headers: map[string]string{headerIfNoneMatch: `*`}, expected: http.StatusPreconditionFailed},
+		{name: "If-None-Match star without representation", method: http.MethodPut, headers: map[string]string{headerIfNoneMatch: `*`}},
+		{name: "If-Match matches", method: http.MethodPut, etag: `"v1"`, headers: map[string]string{headerIfMatch: `"v1"`}},
+		{name: "If-Match differs", method: http.MethodPut, etag: `"v2"`, headers: map[string]string{headerIfMatch: `"v1"`}, expected: http.StatusPreconditionFailed},
+		{name: "If-Match weak never matches", method: http.MethodPut, etag: `W/"v1"`, headers: map[string]string{headerIfMatch: `W/"v1"`}, expected: http.StatusPreconditionFailed},
+		{name: "If-Match star", method: http.MethodPut, etag: `"v1"`, headers: map[string]string{headerIfMatch: `*`}},
+		{name: "If-Match star without representation", method: http.MethodPut, headers: map[string]string{headerIfMatch: `*`}, expected: http.StatusPreconditionFailed},
+		{name: "If-Modified-Since older", method: http.MethodGet, headers: map[string]string{headerIfModifiedSince: before}},
+		{name: "If-Modified-Since same", method: http.MethodGet, headers: map[string]string{headerIfModifiedSince: same}, expected: http.StatusNotModified},
+		{name: "If-Modified-Since ignored on unsafe method", method: http.MethodPost, headers: map[string]string{headerIfModifiedSince: after}},
+		{name: "If-Modified-Since ignored with If-None-Match", method: http.MethodGet, etag: `"v1"`, headers: map[string]string{headerIfNoneMatch: `"v0"`, headerIfModifiedSince: after}},
+		{name: "If-Unmodified-Since older", method: http.MethodDelete, headers: map[string]string{headerIfUnmodifiedSince: before}, expected: http.StatusPreconditionFailed},
+		{name: "If-Unmodified-Since later", method: http.MethodDelete, headers: map[string]string{headerIfUnmodifiedSince: after}},
+		{name: "If-Unmodified-Since ignored with If-Match", method: http.MethodDelete, etag: `"v1"`, headers: map[string]string{headerIfMatch: `"v1"`, headerIfUnmodifiedSince: before}},
+		{name: "invalid date ignored", method: http.MethodGet, headers: map[string]string{headerIfModifiedSince: "yesterday"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			request := httptest.NewRequestWithContext(stdcontext.Background(), tc.method, "/", nil)
+			for k, v := range tc.headers {
+				request.Header.Set(k, v)
+			}
+
+			assert.EqualT(t, tc.expected, evaluatePreconditions(request, tc.etag, modified))
+		})
+	}
+}
+
+func TestCheckPreconditions(t *testing.T) {
+	request := httptest.NewRequestWithContext(stdcontext.Background(), http.MethodPut, "/", nil)
+	request.Header.Set(headerIfMatch, `"v1"`)
+
+	require.NoError(t, CheckPreconditions(request, `"v1"`, time.Time{}))
+
+	err := CheckPreconditions(request, `"v2"`, time.Time{})
+	require.Error(t, err)
+
+	var condErr *ConditionalError
+	require.ErrorAs(t, err, &condErr)
+	assert.EqualT(t, int32(http.StatusPreconditionFailed), condErr.Code())
+	assert.EqualT(t, "precondition failed", condErr.Error())
+}
+
+func TestContextConditionalResponses(t *testing.T) {
+	spec, api := petstore.NewAPI(t)
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	ctx := NewContext(spec, api, nil)
+	ctx.router = DefaultRouter(spec, ctx.api)
+
+	respond := func(t *testing.T, request *http.Request, data any) *httptest.ResponseRecorder {
+		t.Helper()
+
+		route, rCtx, ok := ctx.RouteInfo(request)
+		require.TrueT(t, ok)
+		recorder := httptest.NewRecorder()
+		ctx.Respond(recorder, rCtx, route.Produces, route, data)
+
+		return recorder
+	}
+
+	newRequest := func(method string, headers ...string) *http.Request {
+		request := httptest.NewRequestWithContext(stdcontext.Background(), method, "/api/pets/1", nil)
+		request.Header.Set(runtime.HeaderAccept, runtime.JSONMime)
+		for i := 0; i+1 < len(headers); i += 2 {
+			request.Header.Set(headers[i], headers[i+1])
+		}
+
+		return request
+	}
+
+	pet := map[string]any{"id": 1, "name": "fido"}
+
+	t.Run("should set validators on a fresh response", func(t *testing.T) {
+		recorder := respond(t, newRequest(http.MethodGet), WithValidators(pet, `"v1"`, modified))
+
+		assert.EqualT(t, http.StatusOK, recorder.Code)
+		assert.EqualT(t, `"v1"`, recorder.Header().Get(headerETag))
+		assert.EqualT(t, modified.Format(http.TimeFormat), recorder.Header().Get(headerLastModified))
+		assert.JSONEqT(t, `{"id":1,"name":"fido"}`, recorder.Body.String())
+	})
+
+	t.Run("should reply 304 when the client holds the representation", func(t *testing.T) {
+		recorder := respond(t, newRequest(http.MethodGet, headerIfNoneMatch, `"v1"`), WithValidators(pet, `"v1"`, modified))
+
+		assert.EqualT(t, http.StatusNotModified, recorder.Code)
+		assert.EqualT(t, `"v1"`, recorder.Header().Get(headerETag))
+		assert.Empty(t, recorder.Header().Get(runtime.HeaderContentType))
+		assert.Empty(t, recorder.Body.Bytes())
+	})
+
+	t.Run("should reply 304 with a conditional Responder", func(t *testing.T) {
+		recorder := respond(t, newRequest(http.MethodGet, headerIfModifiedSince, modified.Format(http.TimeFormat)), &conditionalResponder{modified: modified})
+
+		assert.EqualT(t, http.StatusNotModified, recorder.Code)
+		assert.EqualT(t, modified.Format(http.TimeFormat), recorder.Header().Get(headerLastModified))
+	})
+
+	t.Run("should write a conditional Responder", func(t *testing.T) {
+		recorder := respond(t, newRequest(http.MethodGet), WithValidators(&conditionalResponder{}, `"v2"`, time.Time{}))
+
+		assert.EqualT(t, http.StatusAccepted, recorder.Code)
+		assert.EqualT(t, `"v2"`, recorder.Header().Get(headerETag))
+	})
+
+	t.Run("should reply 412 when the precondition fails", func(t *testing.T) {
+		recorder := respond(t, newRequest(http.MethodDelete, headerIfMatch, `"v0"`), WithValidators(pet, `"v1"`, modified))
+
+		assert.EqualT(t, http.StatusPreconditionFailed, recorder.Code)
+		assert.StringContainsT(t, recorder.Body.String(), "precondition failed")
+	})
+
+	t.Run("should reply 304 from a ConditionalError returned by a handler", func(t *testing.T) {
+		request := newRequest(http.MethodGet, headerIfNoneMatch, `"v1"`)
+		recorder := respond(t, request, CheckPreconditions(request, `"v1"`, time.Time{}))
+
+		assert.EqualT(t, http.StatusNotModified, recorder.Code)
+		assert.EqualT(t, `"v1"`, recorder.Header().Get(headerETag))
+		assert.Empty(t, recorder.Body.Bytes())
+	})
+}
+
+func TestContextAutoETag(t *testing.T) {
+	spec, api := petstore.NewAPI(t)
+	handler := NewContext(spec, api, nil).SetAutoETag(true).APIHandler(nil)
+
+	serve := func(method string, headers ...string) *httptest.ResponseRecorder {
+		request := httptest.NewRequestWithContext(stdcontext.Background(), method, "/api/pets/1", nil)
+		request.Header.Set(runtime.HeaderAccept, runtime.JSONMime)
+		for i := 0; i+1 < len(headers); i += 2 {
+			request.Header.Set(headers[i], headers[i+1])
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		return recorder
+	}
+
+	first := serve(http.MethodGet)
+	require.EqualT(t, http.StatusOK, first.Code)
+	etag := first.Header().Get(headerETag)
+	assert.EqualT(t, WeakETag(first.Body.Bytes()), etag)
+	assert.TrueT(t, strings.HasPrefix(etag, `W/"`))
+
+	t.Run("should reply 304 when the computed ETag matches", func(t *testing.T) {
+		recorder := serve(http.MethodGet, headerIfNoneMatch, etag)
+		assert.EqualT(t, http.StatusNotModified, recorder.Code)
+		assert.EqualT(t, etag, recorder.Header().Get(headerETag))
+		assert.Empty(t, recorder.Body.Bytes())
+	})
+
+	t.Run("should send the body when the computed ETag differs", func(t *testing.T) {
+		recorder := serve(http.MethodGet, headerIfNoneMatch, `W/"other"`)
+		assert.EqualT(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, first.Body.Bytes(), recorder.Body.Bytes())
+	})
+
+	t.Run("should not compute ETags for errors", func(t *testing.T) {
+		request := httptest.NewRequestWithContext(stdcontext.Background(), http.MethodGet, "/api/pets/abc", nil)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		assert.EqualT(t, http.StatusUnprocessableEntity, recorder.Code)
+		assert.Empty(t, recorder.Header().Get(headerETag))
+	})
+
+	t.Run("should not buffer responders nor streams", func(t *testing.T) {
+		ctx := NewContext(spec, api, nil).SetAutoETag(true)
+		ctx.router = DefaultRouter(spec, ctx.api)
+		for _, data := range []any{
+			ResponderFunc(func(rw http.ResponseWriter, _ runtime.Producer) {
+				_, _ = rw.Write([]byte("written"))
+				_, isBuffered := rw.(*etagResponseWriter)
+				assert.FalseT(t, isBuffered)
+			}),
+			strings.NewReader("streamed"),
+		} {
+			request := httptest.NewRequestWithContext(stdcontext.Background(), http.MethodGet, "/api/pets/1", nil)
+			route, rCtx, ok := ctx.RouteInfo(request)
+			require.TrueT(t, ok)
+			recorder := httptest.NewRecorder()
+			ctx.Respond(recorder, rCtx, route.Produces, route, data)
+
+			assert.EqualT(t, http.StatusOK, recorder.Code)
+			assert.Empty(t, recorder.Header().Get(headerETag))
+		}
+	})
+}
+
+func TestETagResponseWriter(t *testing.T) {
+	request := httptest.NewRequestWithContext(stdcontext.Background(), http.MethodGet, "/", nil)
+
+	t.Run("should pass large responses through", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		w := newETagResponseWriter(recorder)
+		chunk := bytes.Repeat([]byte("x"), autoETagMaxSize/2)
+		for range 3 {
+			_, err := w.Write(chunk)
+			require.NoError(t, err)
+		}
+		w.finish(request)
+
+		assert.EqualT(t, http.StatusOK, recorder.Code)
+		assert.EqualT(t, 3*len(chunk), recorder.Body.Len())
+		assert.Empty(t, recorder.Header().Get(headerETag))
+	})
+
+	t.Run("should pass flushed responses through", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		w := newETagResponseWriter(recorder)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("event: 1\n"))
+		require.NoError(t, http.NewResponseController(w).Flush())
+
+		assert.TrueT(t, recorder.Flushed)
+		assert.EqualT(t, "event: 1\n", recorder.Body.String())
+
+		_, _ = w.Write([]byte("event: 2\n"))
+		w.finish(request)
+
+		assert.EqualT(t, http.StatusCreated, recorder.Code)
+		assert.EqualT(t, "event: 1\nevent: 2\n", recorder.Body.String())
+		assert.Empty(t, recorder.Header().Get(headerETag))
+	})
+
+	t.Run("should unwrap the response writer", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		assert.True(t, newETagResponseWriter(recorder).Unwrap() == recorder)
+	})
+}
+
+type conditionalResponder struct {
+	modified time.Time
+}
+
+func (c *conditionalResponder) Validators() (string, time.Time) {
+	return "", c.modified
+}
+
+func (c *conditionalResponder) WriteResponse(rw http.ResponseWriter, _ runtime.Producer) {
+	rw.WriteHeader(http.StatusAccepted)
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-openapi/analysis"
 	"github.com/go-openapi/errors"
@@ -83,6 +84,15 @@ type Context struct {
 	debugLogf        func(string, ...any) // a logging function to debug context and all components using it
 	ignoreParameters bool                 // see SetIgnoreParameters / WithIgnoreParameters
 	matchSuffix      bool                 // see SetMatchSuffix / WithMatchSuffix
+	auditSink        AuditSink            // see SetAuditSink
+	problemDetails   bool                 // see SetProblemDetails
+	autoETag         bool                 // see SetAutoETag
+	specOptions      []docui.SpecOption   // see SetSpecOptions
+	routerOptions    []DefaultRouterOpt   // see SetDefaultRouterOptions
+	cors             *corsPolicy          // see SetCORS
+	paths            *pathPolicy          // see SetPathPolicy
+	deprecationHook  DeprecationHook      // see SetDeprecationHook
+	deprecationDate  time.Time            // see SetDeprecationDate
 }
 
 // NewRoutableContext creates a new context for a routable API.
@@ -175,6 +185,20 @@ func (c *Context) SetMatchSuffix(enable bool) *Context {
 	return c
 }
 
+// SetDefaultRouterOptions sets the options of the [DefaultRouter] built by this Context,
+// when no [Router] was provided.
+//
+// Returns the receiver for fluent configuration:
+//
+//	ctx := middleware.NewContext(spec, api, nil).SetDefaultRouterOptions(
+//		middleware.WithUploadInspection(runtime.InspectDigest(crypto.SHA256)),
+//	)
+func (c *Context) SetDefaultRouterOptions(opts ...DefaultRouterOpt) *Context {
+	c.routerOptions = opts
+
+	return c
+}
+
 type routableUntypedAPI struct {
 	api             *untyped.API
 	hlock           *sync.Mutex
@@ -218,6 +242,7 @@ func newRoutableUntypedAPI(spec *loads.Document, api *untyped.API, context *Cont
 				var bound any
 				var validation error
 				bound, r, validation = context.BindAndValidate(r, route)
+				defer closeStreams(bound)
 				if validation != nil {
 					context.Respond(w, r, route.Produces, route, validation)
 					return
@@ -262,6 +287,12 @@ func (r *routableUntypedAPI) HandlerFor(method, path string) (http.Handler, bool
 	r.hlock.Unlock()
 	return handler, ok
 }
+
+// OperationHandlerFor returns the operation handler registered for a method and a path.
+func (r *routableUntypedAPI) OperationHandlerFor(method, path string) (runtime.OperationHandler, bool) {
+	return r.api.OperationHandlerFor(method, path)
+}
+
 func (r *routableUntypedAPI) ServeErrorFor(_ string) func(http.ResponseWriter, *http.Request, error) {
 	return r.api.ServeError
 }
@@ -306,6 +337,7 @@ const (
 	ctxBoundParams
 	ctxSecurityPrincipal
 	ctxSecurityScopes
+	ctxAuditState
 )
 
 // MatchedRouteFrom request context value.
@@ -397,7 +429,10 @@ func (c *Context) BindValidRequest(request *http.Request, route *MatchedRoute, b
 	// now bind the request with the provided binder
 	// it's assumed the binder will also validate the request and return an error if the
 	// request is invalid
-	return binder.BindRequest(request, route)
+	err := binder.BindRequest(request, route)
+	c.auditBound(request, binder)
+
+	return err
 }
 
 // ContentType gets the parsed value of a content type
@@ -497,6 +532,7 @@ func (c *Context) BindAndValidate(request *http.Request, matched *MatchedRoute)
 		return v.bound, request, nil
 	}
 	result := validateRequest(c, request, matched)
+	c.auditBound(request, result.bound)
 	rCtx = stdContext.WithValue(rCtx, ctxBoundParams, result)
 	request = request.WithContext(rCtx)
 	if len(result.result) > 0 {
@@ -520,6 +556,23 @@ func (c *Context) Respond(rw http.ResponseWriter, r *http.Request, produces []st
 	format, r = c.ResponseFormat(r, offers)
 	rw.Header().Set(runtime.HeaderContentType, format)
 
+	data, handled := c.respondConditional(rw, r, data)
+	if handled {
+		return
+	}
+
+	if c.autoETag && r.Method == http.MethodGet && bufferedForETag(data) {
+		ew := newETagResponseWriter(rw)
+		c.respond(ew, r, produces, route, data, format, offers)
+		ew.finish(r)
+
+		return
+	}
+
+	c.respond(rw, r, produces, route, data, format, offers)
+}
+
+func (c *Context) respond(rw http.ResponseWriter, r *http.Request, produces []string, route *MatchedRoute, data any, format string, offers []string) {
 	if resp, ok := data.(Responder); ok {
 		c.respondWithResponder(rw, r, route, resp, format)
 		return
@@ -540,7 +593,7 @@ func (c *Context) Respond(rw http.ResponseWriter, r *http.Request, produces []st
 		return
 	}
 
-	c.api.ServeErrorFor(route.Operation.ID)(rw, r, fmt.Errorf("%d: %s", http.StatusInternalServerError, "can't produce response"))
+	c.serveErrorFor(route.Operation.ID)(rw, r, fmt.Errorf("%d: %s", http.StatusInternalServerError, "can't produce response"))
 }
 
 // APIHandlerSwaggerUI returns a handler to serve the API.
@@ -584,7 +637,8 @@ func (c *Context) APIHandler(builder Builder, opts ...UIOption) http.Handler {
 // This handler includes a swagger spec, router and the contract defined in the swagger spec.
 //
 // A spec UI is served at {API base path}/docs and the spec document at /swagger.json
-// (these can be modified with combined [UIOption]).
+// (these can be modified with combined [UIOption]). The spec document may also be served as YAML
+// (see [Context.SetSpecOptions]).
 //
 // Notice that any function that accepts the [docui.Option] set and returns a valid middleware may be injected here.
 //
@@ -612,20 +666,43 @@ func (c *Context) APIHandlerWithUI(builder Builder, uiMiddleware docui.UIMiddlew
 	prepend = append(prepend, opts...)
 
 	// aligns spec serve path with UI setting to fetch spec document.
-	return docui.UseSpec(c.spec.Raw(), docui.WithSpecPathFromOptions(prepend...))(
+	specOpts := append([]docui.SpecOption{docui.WithSpecPathFromOptions(prepend...)}, c.specOptions...)
+
+	return docui.UseSpec(c.spec.Raw(), specOpts...)(
 		uiMiddleware(prepend...)(
 			c.RoutesHandler(b),
 		),
 	)
 }
 
+// ensureRouter builds the [DefaultRouter] when no [Router] was provided.
+func (c *Context) ensureRouter() Router {
+	if c.router == nil {
+		opts := append([]DefaultRouterOpt{WithDefaultRouterLoggerFunc(c.debugLogf)}, c.routerOptions...)
+		if c.paths != nil {
+			opts = append(opts, withPathPolicy(c.paths))
+		}
+		if !c.deprecationDate.IsZero() {
+			opts = append(opts, withDeprecationDate(c.deprecationDate))
+		}
+		c.router = DefaultRouter(c.spec, c.api, opts...)
+	}
+
+	return c.router
+}
+
 // RoutesHandler returns a handler to serve the API, just the routes and the contract defined in the swagger spec.
 func (c *Context) RoutesHandler(builder Builder) http.Handler {
 	b := builder
 	if b == nil {
 		b = PassthroughBuilder
 	}
-	return NewRouter(c, b(NewOperationExecutor(c)))
+	next := b(NewOperationExecutor(c))
+	if c.auditSink != nil {
+		next = newAuditHandler(c, next)
+	}
+
+	return NewRouter(c, next)
 }
 
 // authorizeImpl is the real authentication+authorization body shared
@@ -668,6 +745,8 @@ func (c *Context) authorizeImpl(request *http.Request, route *MatchedRoute) (any
 
 	rCtx = stdContext.WithValue(rCtx, ctxSecurityPrincipal, usr)
 	rCtx = stdContext.WithValue(rCtx, ctxSecurityScopes, route.Authenticator.AllScopes())
+	c.auditPrincipal(request, usr, route.Authenticator.AllScopes())
+
 	return usr, request.WithContext(rCtx), nil
 }
 
@@ -691,7 +770,6 @@ func (c *Context) bindRequestBody(request *http.Request, route *MatchedRoute) (s
 }
 
 func (c *Context) respondWithResponder(rw http.ResponseWriter, r *http.Request, route *MatchedRoute, resp Responder, format string) {
-	_ = r
 	producers := route.Producers
 
 	// producers contains keys with normalized format, if a format has MIME type parameter such as `text/plain; charset=utf-8`
@@ -706,6 +784,12 @@ func (c *Context) respondWithResponder(rw http.ResponseWriter, r *http.Request,
 		prod = pr
 	}
 
+	if rr, isRequestResponder := resp.(requestResponder); isRequestResponder {
+		rr.writeRequestResponse(rw, r, prod)
+
+		return
+	}
+
 	resp.WriteResponse(rw, prod)
 }
 
@@ -721,11 +805,20 @@ func (c *Context) respondWithError(rw http.ResponseWriter, r *http.Request, prod
 	}
 
 	if route == nil || route.Operation == nil {
-		c.api.ServeErrorFor("")(rw, r, err)
+		c.serveErrorFor("")(rw, r, err)
 		return
 	}
 
-	c.api.ServeErrorFor(route.Operation.ID)(rw, r, err)
+	c.serveErrorFor(route.Operation.ID)(rw, r, err)
+}
+
+// serveErrorFor picks the error handler for an operation, unless problem details are enabled.
+func (c *Context) serveErrorFor(operationID string) func(http.ResponseWriter, *http.Request, error) {
+	if c.problemDetails {
+		return ServeProblem
+	}
+
+	return c.api.ServeErrorFor(operationID)
 }
 
 func (c *Context) respondWithoutCode(rw http.ResponseWriter, r *http.Request, data any, format string, offers []string) {
@@ -0,0 +1,340 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/spec"
+
+	"github.com/go-openapi/runtime"
+)
+
+// CORSExtension is the extension of a spec which declares its CORS policy, e.g.:
+//
+//	x-cors:
+//	  origins: [https://app.example.com, https://*.example.org]
+//	  allowCredentials: true
+//	  maxAge: 600
+//	  allowHeaders: [X-Requested-With]
+//	  exposeHeaders: [X-Trace-Id]
+//
+// A Context serves CORS when its spec has this extension, or when [Context.SetCORS] is called.
+const CORSExtension = "x-cors"
+
+const (
+	headerOrigin           = "Origin"
+	headerVary             = "Vary"
+	headerRequestMethod    = "Access-Control-Request-Method"
+	headerRequestHeaders   = "Access-Control-Request-Headers"
+	headerAllowOrigin      = "Access-Control-Allow-Origin"
+	headerAllowMethods     = "Access-Control-Allow-Methods"
+	headerAllowHeaders     = "Access-Control-Allow-Headers"
+	headerAllowCredentials = "Access-Control-Allow-Credentials"
+	headerExposeHeaders    = "Access-Control-Expose-Headers"
+	headerMaxAge           = "Access-Control-Max-Age"
+	anyOrigin              = "*"
+)
+
+// CORSOption configures the CORS policy of a [Context], see [Context.SetCORS].
+//
+// Options take precedence over the [CORSExtension] of the spec.
+type CORSOption func(*corsPolicy)
+
+// WithCORSOrigins sets the origins allowed to call the API: exact origins, origins with a wildcard
+// subdomain (e.g. https://*.example.com), or "*" for any origin.
+func WithCORSOrigins(origins ...string) CORSOption {
+	return func(p *corsPolicy) {
+		p.Origins = origins
+	}
+}
+
+// WithCORSCredentials allows requests with credentials (cookies, HTTP authentication).
+//
+// Credentials are only allowed for the origins listed explicitly, or matching a wildcard subdomain:
+// origins allowed by "*" are not allowed credentials.
+func WithCORSCredentials(allow bool) CORSOption {
+	return func(p *corsPolicy) {
+		p.AllowCredentials = allow
+	}
+}
+
+// WithCORSMaxAge sets how long the response to a preflight request may be cached.
+func WithCORSMaxAge(maxAge time.Duration) CORSOption {
+	return func(p *corsPolicy) {
+		p.MaxAge = int(maxAge / time.Second)
+	}
+}
+
+// WithCORSAllowedHeaders adds request headers to the ones allowed for all operations.
+//
+// The headers of an operation are derived from the spec: its header parameters, and the headers
+// of its security schemes.
+func WithCORSAllowedHeaders(headers ...string) CORSOption {
+	return func(p *corsPolicy) {
+		p.AllowHeaders = append(p.AllowHeaders, headers...)
+	}
+}
+
+// WithCORSExposedHeaders adds response headers to the ones exposed for all operations.
+//
+// The exposed headers of an operation are derived from the headers declared by its responses.
+func WithCORSExposedHeaders(headers ...string) CORSOption {
+	return func(p *corsPolicy) {
+		p.ExposeHeaders = append(p.ExposeHeaders, headers...)
+	}
+}
+
+// corsPolicy is the CORS policy of a Context, as declared by the [CORSExtension] of its spec.
+type corsPolicy struct {
+	Origins          []string `json:"origins,omitempty"`
+	AllowCredentials bool     `json:"allowCredentials,omitempty"`
+	MaxAge           int      `json:"maxAge,omitempty"`
+	AllowHeaders     []string `json:"allowHeaders,omitempty"`
+	ExposeHeaders    []string `json:"exposeHeaders,omitempty"`
+}
+
+// SetCORS serves CORS for the API: preflight requests are answered with the methods declared for the path,
+// and the headers of the operation.
+//
+// The policy declared by the [CORSExtension] of the spec, if any, is amended by options.
+//
+// Returns the receiver for fluent configuration:
+//
+//	ctx := middleware.NewContext(spec, api, nil).SetCORS(
+//		middleware.WithCORSOrigins("https://app.example.com"),
+//	)
+func (c *Context) SetCORS(opts ...CORSOption) *Context {
+	policy := specCORSPolicy(c.spec)
+	if policy == nil {
+		policy = &corsPolicy{}
+	}
+	for _, apply := range opts {
+		apply(policy)
+	}
+	c.cors = policy
+
+	return c
+}
+
+// corsPolicy returns the CORS policy of the Context, if any.
+func (c *Context) corsPolicy() *corsPolicy {
+	if c.cors == nil {
+		c.cors = specCORSPolicy(c.spec)
+	}
+
+	return c.cors
+}
+
+func specCORSPolicy(doc *loads.Document) *corsPolicy {
+	if doc == nil || doc.Spec() == nil {
+		return nil
+	}
+
+	extension, ok := doc.Spec().Extensions[CORSExtension]
+	if !ok {
+		return nil
+	}
+
+	raw, err := json.Marshal(extension)
+	if err != nil {
+		return nil
+	}
+
+	var policy corsPolicy
+	if err := json.Unmarshal(raw, &policy); err != nil {
+		return nil
+	}
+
+	return &policy
+}
+
+// allowsOrigin returns the value of the Access-Control-Allow-Origin header for an origin, if allowed.
+//
+// Origins allowed by "*" only are answered with "*", so that they are never allowed credentials.
+func (p *corsPolicy) allowsOrigin(origin string) (string, bool) {
+	for _, allowed := range p.Origins {
+		if strings.EqualFold(allowed, origin) || matchesWildcardOrigin(allowed, origin) {
+			return origin, true
+		}
+	}
+
+	if slices.Contains(p.Origins, anyOrigin) {
+		return anyOrigin, true
+	}
+
+	return "", false
+}
+
+// matchesWildcardOrigin matches origins like https://*.example.com.
+func matchesWildcardOrigin(pattern, origin string) bool {
+	prefix, suffix, ok := strings.Cut(strings.ToLower(pattern), "*.")
+	if !ok {
+		return false
+	}
+
+	origin = strings.ToLower(origin)
+
+	return strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, "."+suffix) &&
+		len(origin) > len(prefix)+len(suffix)+1
+}
+
+// serveCORS answers preflight requests, and sets the CORS headers of other requests from allowed origins.
+//
+// It returns true when the request is answered.
+func (c *Context) serveCORS(rw http.ResponseWriter, r *http.Request, policy *corsPolicy) bool {
+	origin := r.Header.Get(headerOrigin)
+	preflight := isPreflight(r)
+	if preflight {
+		rw.Header().Add(headerVary, headerOrigin)
+		rw.Header().Add(headerVary, headerRequestMethod)
+		rw.Header().Add(headerVary, headerRequestHeaders)
+	} else {
+		rw.Header().Add(headerVary, headerOrigin)
+	}
+
+	if origin == "" {
+		return false
+	}
+
+	allowOrigin, allowed := policy.allowsOrigin(origin)
+	if !preflight {
+		if allowed {
+			c.setCORSHeaders(rw, policy, allowOrigin)
+		}
+
+		return false
+	}
+
+	method := strings.ToUpper(r.Header.Get(headerRequestMethod))
+	route, ok := c.router.Lookup(method, r.URL.EscapedPath())
+	if !ok {
+		// let the router answer with 404 or 405
+		return false
+	}
+
+	if !allowed {
+		rw.WriteHeader(http.StatusForbidden)
+
+		return true
+	}
+
+	methods := c.AllowedMethods(r)
+	slices.Sort(methods)
+
+	c.setCORSHeaders(rw, policy, allowOrigin)
+	rw.Header().Set(headerAllowMethods, strings.Join(methods, ", "))
+	if headers := c.allowedHeaders(policy, route); len(headers) > 0 {
+		rw.Header().Set(headerAllowHeaders, strings.Join(headers, ", "))
+	}
+	if policy.MaxAge > 0 {
+		rw.Header().Set(headerMaxAge, strconv.Itoa(policy.MaxAge))
+	}
+	rw.WriteHeader(http.StatusNoContent)
+
+	return true
+}
+
+func isPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions && r.Header.Get(headerRequestMethod) != ""
+}
+
+func (c *Context) setCORSHeaders(rw http.ResponseWriter, policy *corsPolicy, allowOrigin string) {
+	rw.Header().Set(headerAllowOrigin, allowOrigin)
+	if policy.AllowCredentials && allowOrigin != anyOrigin {
+		// credentials are not allowed with a wildcard (Fetch standard, section 3.2.5)
+		rw.Header().Set(headerAllowCredentials, "true")
+	}
+}
+
+// allowedHeaders derives the request headers of an operation: its header parameters, the headers of its
+// security schemes, and Content-Type when it consumes a body.
+func (c *Context) allowedHeaders(policy *corsPolicy, route *MatchedRoute) []string {
+	headers := slices.Clone(policy.AllowHeaders)
+	for _, param := range route.Parameters {
+		switch param.In {
+		case "header":
+			headers = append(headers, param.Name)
+		case "body", "formData":
+			headers = append(headers, runtime.HeaderContentType)
+		}
+	}
+
+	definitions := c.spec.Spec().SecurityDefinitions
+	for _, auth := range route.Authenticators {
+		for _, name := range auth.Schemes {
+			scheme, ok := definitions[name]
+			if !ok {
+				continue
+			}
+
+			switch {
+			case scheme.Type == "apiKey" && scheme.In == "header":
+				headers = append(headers, scheme.Name)
+			case scheme.Type == "basic" || scheme.Type == "oauth2":
+				headers = append(headers, runtime.HeaderAuthorization)
+			}
+		}
+	}
+
+	return canonicalHeaders(headers)
+}
+
+// exposeCORSHeaders exposes the response headers of an operation to the allowed origins.
+func (c *Context) exposeCORSHeaders(rw http.ResponseWriter, policy *corsPolicy, route *MatchedRoute) {
+	if rw.Header().Get(headerAllowOrigin) == "" {
+		return
+	}
+
+	if exposed := c.exposedHeaders(policy, route); len(exposed) > 0 {
+		rw.Header().Set(headerExposeHeaders, strings.Join(exposed, ", "))
+	}
+}
+
+// exposedHeaders derives the response headers of an operation from its responses.
+func (c *Context) exposedHeaders(policy *corsPolicy, route *MatchedRoute) []string {
+	headers := slices.Clone(policy.ExposeHeaders)
+	if route.Operation == nil || route.Operation.Responses == nil {
+		return canonicalHeaders(headers)
+	}
+
+	responses := make([]spec.Response, 0, len(route.Operation.Responses.StatusCodeResponses)+1)
+	for _, response := range route.Operation.Responses.StatusCodeResponses {
+		responses = append(responses, response)
+	}
+	if route.Operation.Responses.Default != nil {
+		responses = append(responses, *route.Operation.Responses.Default)
+	}
+
+	for _, response := range responses {
+		if response.Ref.String() != "" {
+			resolved, err := spec.ResolveResponse(c.spec.Spec(), response.Ref)
+			if err != nil {
+				continue
+			}
+			response = *resolved
+		}
+
+		for name := range response.Headers {
+			headers = append(headers, name)
+		}
+	}
+
+	return canonicalHeaders(headers)
+}
+
+func canonicalHeaders(headers []string) []string {
+	for i, header := range headers {
+		headers[i] = http.CanonicalHeaderKey(header)
+	}
+	slices.Sort(headers)
+
+	return slices.Compact(headers)
+}
@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/internal/testing/petstore"
+)
+
+const corsTestSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "cors", "version": "1.0"},
+  "basePath": "/api",
+  "consumes": ["application/json"],
+  "produces": ["application/json"],
+  "x-cors": {
+    "origins": ["https://app.example.com", "https://*.example.org"],
+    "maxAge": 600
+  },
+  "securityDefinitions": {
+    "key": {"type": "apiKey", "in": "header", "name": "X-Api-Key"}
+  },
+  "paths": {
+    "/orders": {
+      "get": {
+        "operationId": "listOrders",
+        "parameters": [{"name": "X-Tenant", "in": "header", "type": "string"}],
+        "responses": {
+          "200": {"description": "ok", "headers": {"X-Total-Count": {"type": "integer"}}},
+          "default": {"$ref": "#/responses/Error"}
+        }
+      },
+      "post": {
+        "operationId": "createOrder",
+        "security": [{"key": []}],
+        "parameters": [{"name": "order", "in": "body", "schema": {"type": "object"}}],
+        "responses": {"201": {"description": "created", "headers": {"Location": {"type": "string"}}}}
+      }
+    }
+  },
+  "responses": {
+    "Error": {"description": "error", "headers": {"X-Request-Id": {"type": "string"}}}
+  }
+}`
+
+func corsTestContext(t *testing.T) *Context {
+	t.Helper()
+
+	doc, api := testAPI(t, corsTestSpec)
+	api.RegisterAuth("key", runtime.AuthenticatorFunc(func(any) (bool, any, error) { return true, "user", nil }))
+	handler := runtime.OperationHandlerFunc(func(any) (any, error) { return map[string]any{}, nil })
+	api.RegisterOperation("get", "/orders", handler)
+	api.RegisterOperation("post", "/orders", handler)
+
+	return NewContext(doc, api, nil)
+}
+
+func TestCORS(t *testing.T) {
+	handler := corsTestContext(t).RoutesHandler(nil)
+
+	t.Run("should answer preflight requests from the spec", func(t *testing.T) {
+		rec := serveTest(handler, http.MethodOptions, "/api/orders", map[string]string{
+			headerOrigin:         "https://app.example.com",
+			headerRequestMethod:  http.MethodPost,
+			headerRequestHeaders: "content-type, x-api-key",
+		})
+		require.EqualT(t, http.StatusNoContent, rec.Code)
+		assert.EqualT(t, "https://app.example.com", rec.Header().Get(headerAllowOrigin))
+		assert.EqualT(t, "GET, POST", rec.Header().Get(headerAllowMethods))
+		assert.EqualT(t, "Content-Type, X-Api-Key", rec.Header().Get(headerAllowHeaders))
+		assert.EqualT(t, "600", rec.Header().Get(headerMaxAge))
+		assert.Contains(t, rec.Header().Values(headerVary), headerOrigin)
+		assert.Empty(t, rec.Header().Get(headerAllowCredentials))
+
+		rec = serveTest(handler, http.MethodOptions, "/api/orders", map[string]string{
+			headerOrigin:        "https://shop.example.org",
+			headerRequestMethod: http.MethodGet,
+		})
+		require.EqualT(t, http.StatusNoContent, rec.Code)
+		assert.EqualT(t, "https://shop.example.org", rec.Header().Get(headerAllowOrigin))
+		assert.EqualT(t, "X-Tenant", rec.Header().Get(headerAllowHeaders))
+	})
+
+	t.Run("should reject preflight requests from other origins", func(t *testing.T) {
+		for _, origin := range []string{"https://evil.example.com", "https://example.org", "http://shop.example.org"} {
+			rec := serveTest(handler, http.MethodOptions, "/api/orders", map[string]string{
+				headerOrigin:        origin,
+				headerRequestMethod: http.MethodGet,
+			})
+			assert.EqualT(t, http.StatusForbidden, rec.Code, origin)
+			assert.Empty(t, rec.Header().Get(headerAllowOrigin))
+		}
+	})
+
+	t.Run("should route preflight requests for unknown routes", func(t *testing.T) {
+		rec := serveTest(handler, http.MethodOptions, "/api/orders", map[string]string{
+			headerOrigin:        "https://app.example.com",
+			headerRequestMethod: http.MethodDelete,
+		})
+		assert.EqualT(t, http.StatusMethodNotAllowed, rec.Code)
+
+		rec = serveTest(handler, http.MethodOptions, "/api/customers", map[string]string{
+			headerOrigin:        "https://app.example.com",
+			headerRequestMethod: http.MethodGet,
+		})
+		assert.EqualT(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("should expose the response headers of the operation", func(t *testing.T) {
+		rec := serveTest(handler, http.MethodGet, "/api/orders", map[string]string{
+			headerOrigin: "https://app.example.com",
+		})
+		require.EqualT(t, http.StatusOK, rec.Code)
+		assert.EqualT(t, "https://app.example.com", rec.Header().Get(headerAllowOrigin))
+		assert.EqualT(t, "X-Request-Id, X-Total-Count", rec.Header().Get(headerExposeHeaders))
+
+		rec = serveTest(handler, http.MethodGet, "/api/orders", map[string]string{
+			headerOrigin: "https://evil.example.com",
+		})
+		require.EqualT(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get(headerAllowOrigin))
+		assert.Empty(t, rec.Header().Get(headerExposeHeaders))
+	})
+
+	t.Run("should be configured with options", func(t *testing.T) {
+		handler := corsTestContext(t).SetCORS(
+			WithCORSOrigins("*"),
+			WithCORSCredentials(true),
+			WithCORSMaxAge(time.Hour),
+			WithCORSAllowedHeaders("x-requested-with"),
+		).RoutesHandler(nil)
+
+		rec := serveTest(handler, http.MethodOptions, "/api/orders", map[string]string{
+			headerOrigin:        "https://anywhere.example.net",
+			headerRequestMethod: http.MethodGet,
+		})
+		require.EqualT(t, http.StatusNoContent, rec.Code)
+		// credentials are not allowed with a wildcard origin
+		assert.EqualT(t, "*", rec.Header().Get(headerAllowOrigin))
+		assert.Empty(t, rec.Header().Get(headerAllowCredentials))
+		assert.EqualT(t, "3600", rec.Header().Get(headerMaxAge))
+		assert.EqualT(t, "X-Requested-With, X-Tenant", rec.Header().Get(headerAllowHeaders))
+	})
+
+	t.Run("should allow credentials to listed origins only", func(t *testing.T) {
+		handler := corsTestContext(t).SetCORS(
+			WithCORSOrigins("*", "https://app.example.com"),
+			WithCORSCredentials(true),
+		).RoutesHandler(nil)
+
+		for origin, expected := range map[string]struct {
+			allowOrigin string
+			credentials string
+		}{
+			"https://evil.example.net": {allowOrigin: "*"},
+			"https://app.example.com":  {allowOrigin: "https://app.example.com", credentials: "true"},
+		} {
+			t.Run(origin, func(t *testing.T) {
+				for _, method := range []string{http.MethodOptions, http.MethodGet} {
+					rec := serveTest(handler, method, "/api/orders", map[string]string{
+						headerOrigin:        origin,
+						headerRequestMethod: http.MethodGet,
+					})
+					assert.EqualT(t, expected.allowOrigin, rec.Header().Get(headerAllowOrigin), method)
+					assert.EqualT(t, expected.credentials, rec.Header().Get(headerAllowCredentials), method)
+				}
+			})
+		}
+	})
+
+	t.Run("should not serve CORS by default", func(t *testing.T) {
+		spec, api := petstore.NewAPI(t)
+		handler := NewContext(spec, api, nil).RoutesHandler(nil)
+
+		rec := serveTest(handler, http.MethodOptions, "/api/pets", map[string]string{
+			headerOrigin:        "https://app.example.com",
+			headerRequestMethod: http.MethodGet,
+		})
+		assert.EqualT(t, http.StatusMethodNotAllowed, rec.Code)
+		assert.Empty(t, rec.Header().Get(headerAllowOrigin))
+	})
+}
@@ -43,6 +43,9 @@ type Router struct {
 	SizeHint int
 
 	static map[string]any
+
+	// constrained is true when some records have constraints on their parameters.
+	constrained bool
 }
 
 // New returns a new Router.
@@ -60,6 +63,10 @@ func New() *Router {
 //
 // e.g. when built routing path is "/path/to/:id/:name" and given path is "/path/to/1/alice",
 // params order is [{"id": "1"}, {"name": "alice"}], not [{"name": "alice"}, {"id": "1"}].
+//
+// When records have [Constraint]s, the path is matched by the first record whose parameters satisfy
+// them, backtracking to the next candidate otherwise. When no candidate satisfies its constraints,
+// the path is matched regardless of the constraints, so that callers may report invalid parameters.
 func (rt *Router) Lookup(path string) (data any, params Params, found bool) {
 	if data, found = rt.static[path]; found {
 		return data, nil, true
@@ -67,7 +74,10 @@ func (rt *Router) Lookup(path string) (data any, params Params, found bool) {
 	if len(rt.param.node) == 1 {
 		return nil, nil, false
 	}
-	nd, params, found := rt.param.lookup(path, make([]Param, 0, rt.SizeHint), 1)
+	nd, params, found := rt.param.lookup(path, make([]Param, 0, rt.SizeHint), 1, rt.constrained)
+	if !found && rt.constrained {
+		nd, params, found = rt.param.lookup(path, make([]Param, 0, rt.SizeHint), 1, false)
+	}
 	if !found {
 		return nil, nil, false
 	}
@@ -100,6 +110,11 @@ func (rt *Router) Build(records []Record) error {
 	for _, r := range statics {
 		rt.static[r.Key] = r.Value
 	}
+	for _, p := range params {
+		if len(p.Constraints) > 0 {
+			rt.constrained = true
+		}
+	}
 	if err := rt.param.build(params, 1, 0, make(map[int]struct{})); err != nil {
 		return err
 	}
@@ -202,7 +217,8 @@ const (
 	indexMask   = uint64(0xffffffff)
 )
 
-func (da *doubleArray) lookup(path string, params []Param, idx int) (*node, []Param, bool) {
+// lookup matches a path from the index idx. With strict, the parameters must satisfy the constraints of a candidate node.
+func (da *doubleArray) lookup(path string, params []Param, idx int, strict bool) (*node, []Param, bool) {
 	indices := make([]uint64, 0, 1)
 	for i := range len(path) {
 		if da.bc[idx].IsAnyParam() {
@@ -214,7 +230,9 @@ func (da *doubleArray) lookup(path string, params []Param, idx int) (*node, []Pa
 		}
 	}
 	if next := nextIndex(da.bc[idx].Base(), TerminationCharacter); next < len(da.bc) && da.bc[next].Check() == TerminationCharacter {
-		return da.node[da.bc[next].Base()], params, true
+		if nd := da.node[da.bc[next].Base()].match(params, strict); nd != nil {
+			return nd, params, true
+		}
 	}
 
 BACKTRACKING:
@@ -229,7 +247,7 @@ BACKTRACKING:
 			next := NextSeparator(path, i)
 			nextParams := params
 			nextParams = append(nextParams, Param{Value: path[i:next]})
-			if nd, nextNextParams, found := da.lookup(path[next:], nextParams, nextIdx); found {
+			if nd, nextNextParams, found := da.lookup(path[next:], nextParams, nextIdx, strict); found {
 				return nd, nextNextParams, true
 			}
 		}
@@ -238,7 +256,9 @@ BACKTRACKING:
 			nextIdx := nextIndex(da.bc[idx].Base(), WildcardCharacter)
 			nextParams := params
 			nextParams = append(nextParams, Param{Value: path[i:]})
-			return da.node[da.bc[nextIdx].Base()], nextParams, true
+			if nd := da.node[da.bc[nextIdx].Base()].match(nextParams, strict); nd != nil {
+				return nd, nextParams, true
+			}
 		}
 	}
 	return nil, nil, false
@@ -247,12 +267,12 @@ BACKTRACKING:
 // build builds double-array from records.
 func (da *doubleArray) build(srcs []*record, idx, depth int, usedBase map[int]struct{}) error {
 	sort.Stable(recordSlice(srcs))
-	base, siblings, leaf, err := da.arrange(srcs, idx, depth, usedBase)
+	base, siblings, leaves, err := da.arrange(srcs, idx, depth, usedBase)
 	if err != nil {
 		return err
 	}
-	if leaf != nil {
-		nd, err := makeNode(leaf)
+	if len(leaves) > 0 {
+		nd, err := makeNode(leaves)
 		if err != nil {
 			return err
 		}
@@ -340,20 +360,20 @@ func (da *doubleArray) findBase(siblings []sibling, start int, usedBase map[int]
 	return base
 }
 
-func (da *doubleArray) arrange(records []*record, idx, depth int, usedBase map[int]struct{}) (base int, siblings []sibling, leaf *record, err error) {
-	siblings, leaf, err = makeSiblings(records, depth)
+func (da *doubleArray) arrange(records []*record, idx, depth int, usedBase map[int]struct{}) (base int, siblings []sibling, leaves []*record, err error) {
+	siblings, leaves, err = makeSiblings(records, depth)
 	if err != nil {
 		return -1, nil, nil, err
 	}
 	if len(siblings) < 1 {
-		return -1, nil, leaf, nil
+		return -1, nil, leaves, nil
 	}
 	base = da.findBase(siblings, idx, usedBase)
 	if base > MaxSize {
 		return -1, nil, nil, errors.New("denco: too many elements of internal slice")
 	}
 	da.setBase(idx, base)
-	return base, siblings, leaf, err
+	return base, siblings, leaves, err
 }
 
 // node represents a node of Double-Array.
@@ -362,18 +382,77 @@ type node struct {
 
 	// Names of path parameters.
 	paramNames []string
+
+	// Constraints of path parameters, in the order of paramNames.
+	constraints []Constraint
+
+	// next is the next candidate for the same path, when the constraints of this node are not satisfied.
+	next *node
+}
+
+// match returns the first candidate node whose constraints are satisfied by params.
+//
+// Without strict, the constraints are ignored.
+func (nd *node) match(params []Param, strict bool) *node {
+	if !strict {
+		return nd
+	}
+
+	for candidate := nd; candidate != nil; candidate = candidate.next {
+		if candidate.accepts(params) {
+			return candidate
+		}
+	}
+
+	return nil
+}
+
+func (nd *node) accepts(params []Param) bool {
+	for i, constraint := range nd.constraints {
+		if constraint != nil && i < len(params) && !constraint(params[i].Value) {
+			return false
+		}
+	}
+
+	return true
 }
 
-// makeNode returns a new node from record.
-func makeNode(r *record) (*node, error) {
-	dups := make(map[string]bool)
-	for _, name := range r.paramNames {
-		if dups[name] {
-			return nil, fmt.Errorf("denco: path parameter `%v' is duplicated in the key `%v'", name, r.Key)
+// makeNode returns a new node from the records ending at the same place, chaining them by constraints:
+// the most constrained records come first, and the last record wins among unconstrained ones.
+func makeNode(leaves []*record) (*node, error) {
+	candidates := slices.Clone(leaves)
+	slices.Reverse(candidates)
+	slices.SortStableFunc(candidates, func(a, b *record) int {
+		return len(b.Constraints) - len(a.Constraints)
+	})
+
+	var head, tail *node
+	for _, r := range candidates {
+		dups := make(map[string]bool)
+		for _, name := range r.paramNames {
+			if dups[name] {
+				return nil, fmt.Errorf("denco: path parameter `%v' is duplicated in the key `%v'", name, r.Key)
+			}
+			dups[name] = true
+		}
+
+		nd := &node{data: r.Value, paramNames: r.paramNames}
+		if constraints := r.Constraints; len(constraints) > 0 {
+			nd.constraints = make([]Constraint, len(r.paramNames))
+			for i, name := range r.paramNames {
+				nd.constraints[i] = constraints[name]
+			}
+		}
+
+		if head == nil {
+			head = nd
+		} else {
+			tail.next = nd
 		}
-		dups[name] = true
+		tail = nd
 	}
-	return &node{data: r.Value, paramNames: r.paramNames}, nil
+
+	return head, nil
 }
 
 // sibling represents an intermediate data of build for Double-Array.
@@ -394,14 +473,14 @@ func nextIndex(base int, c byte) int {
 }
 
 // makeSiblings returns slice of sibling.
-func makeSiblings(records []*record, depth int) (sib []sibling, leaf *record, err error) {
+func makeSiblings(records []*record, depth int) (sib []sibling, leaves []*record, err error) {
 	var (
 		pc byte
 		n  int
 	)
 	for i, r := range records {
 		if len(r.Key) <= depth {
-			leaf = r
+			leaves = append(leaves, r)
 			continue
 		}
 		c := r.Key[depth]
@@ -420,10 +499,10 @@ func makeSiblings(records []*record, depth int) (sib []sibling, leaf *record, er
 		n++
 	}
 	if n == 0 {
-		return nil, leaf, nil
+		return nil, leaves, nil
 	}
 	sib[n-1].end = len(records)
-	return sib, leaf, nil
+	return sib, leaves, nil
 }
 
 // Record represents a record data for router construction.
@@ -433,8 +512,16 @@ type Record struct {
 
 	// Result value for Key.
 	Value any
+
+	// Constraints on the values of path parameters, by parameter name (see [NewConstrainedRecord]).
+	Constraints map[string]Constraint
 }
 
+// Constraint tells if the value of a path parameter is acceptable for a [Record].
+//
+// The value is passed as found in the path, i.e. escaped.
+type Constraint func(value string) bool
+
 // NewRecord returns a new Record.
 func NewRecord(key string, value any) Record {
 	return Record{
@@ -443,6 +530,19 @@ func NewRecord(key string, value any) Record {
 	}
 }
 
+// NewConstrainedRecord returns a new Record with constraints on the values of its path parameters,
+// by parameter name.
+//
+// Records with the same structure (e.g. "/items/:id" and "/items/:name") are tried in turn,
+// the most constrained first, until the values of the parameters satisfy their constraints.
+func NewConstrainedRecord(key string, value any, constraints map[string]Constraint) Record {
+	return Record{
+		Key:         key,
+		Value:       value,
+		Constraints: constraints,
+	}
+}
+
 // record represents a record that use to build the Double-Array.
 type record struct {
 	Record
@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"math/rand"
 	"reflect"
+	"regexp"
+	"strconv"
 	"testing"
 
 	"github.com/go-openapi/runtime/middleware/denco"
@@ -18,167 +20,167 @@ import (
 
 func routes() []denco.Record {
 	return []denco.Record{
-		{"/", testRoute0},
-		{pathPathToRoute, testRoute1},
-		{"/path/to/other", testRoute2},
-		{"/path/to/route/a", testRoute3},
-		{"/path/to/:param", "testroute4"},
-		{"/gists/:param1/foo/:param2", "testroute12"},
-		{"/gists/:param1/foo/bar", "testroute11"},
-		{"/:param1/:param2/foo/:param3", "testroute13"},
-		{"/path/to/wildcard/*routepath", "testroute5"},
-		{"/path/to/:param1/:param2", "testroute6"},
-		{"/path/to/:param1/sep/:param2", "testroute7"},
-		{"/:year/:month/:day", "testroute8"},
-		{pathUserID, "testroute9"},
-		{"/a/to/b/:param/*routepath", "testroute10"},
-		{"/path/with/key=:value", "testroute14"},
+		{Key: "/", Value: testRoute0},
+		{Key: pathPathToRoute, Value: testRoute1},
+		{Key: "/path/to/other", Value: testRoute2},
+		{Key: "/path/to/route/a", Value: testRoute3},
+		{Key: "/path/to/:param", Value: "testroute4"},
+		{Key: "/gists/:param1/foo/:param2", Value: "testroute12"},
+		{Key: "/gists/:param1/foo/bar", Value: "testroute11"},
+		{Key: "/:param1/:param2/foo/:param3", Value: "testroute13"},
+		{Key: "/path/to/wildcard/*routepath", Value: "testroute5"},
+		{Key: "/path/to/:param1/:param2", Value: "testroute6"},
+		{Key: "/path/to/:param1/sep/:param2", Value: "testroute7"},
+		{Key: "/:year/:month/:day", Value: "testroute8"},
+		{Key: pathUserID, Value: "testroute9"},
+		{Key: "/a/to/b/:param/*routepath", Value: "testroute10"},
+		{Key: "/path/with/key=:value", Value: "testroute14"},
 	}
 }
 
 var realURIs = []denco.Record{
-	{pathAuthorizations, pathAuthorizations},
-	{pathAuthorizationsID, pathAuthorizationsID},
-	{pathAppsTokens, pathAppsTokens},
-	{pathEvents, pathEvents},
-	{pathReposEvents, pathReposEvents},
-	{pathNetworksEvents, pathNetworksEvents},
-	{pathOrgsEvents, pathOrgsEvents},
-	{pathUsersReceivedEvents, pathUsersReceivedEvents},
-	{pathUsersReceivedEventsPublic, pathUsersReceivedEventsPublic},
-	{pathUsersEvents, pathUsersEvents},
-	{pathUsersEventsPublic, pathUsersEventsPublic},
-	{pathUsersEventsOrgs, pathUsersEventsOrgs},
-	{pathFeeds, pathFeeds},
-	{pathNotifications, pathNotifications},
-	{pathReposNotifications, pathReposNotifications},
-	{pathNotificationThreads, pathNotificationThreads},
-	{pathNotificationThreadSub, pathNotificationThreadSub},
-	{pathReposStargazers, pathReposStargazers},
-	{pathUsersStarred, pathUsersStarred},
-	{pathUserStarred, pathUserStarred},
-	{pathUserStarredOwnerRepo, pathUserStarredOwnerRepo},
-	{pathReposSubscribers, pathReposSubscribers},
-	{pathUsersSubscriptions, pathUsersSubscriptions},
-	{pathUserSubscriptions, pathUserSubscriptions},
-	{pathReposSubscription, pathReposSubscription},
-	{pathUserSubscriptionsOwnerRepo, pathUserSubscriptionsOwnerRepo},
-	{pathUsersGists, pathUsersGists},
-	{pathGists, pathGists},
-	{pathGistsID, pathGistsID},
-	{pathGistsIDStar, pathGistsIDStar},
-	{pathReposGitBlobs, pathReposGitBlobs},
-	{pathReposGitCommits, pathReposGitCommits},
-	{pathReposGitRefs, pathReposGitRefs},
-	{pathReposGitTags, pathReposGitTags},
-	{pathReposGitTrees, pathReposGitTrees},
-	{pathIssues, pathIssues},
-	{pathUserIssues, pathUserIssues},
-	{pathOrgsIssues, pathOrgsIssues},
-	{pathReposIssues, pathReposIssues},
-	{pathReposIssue, pathReposIssue},
-	{pathReposAssignees, pathReposAssignees},
-	{pathReposAssignee, pathReposAssignee},
-	{pathReposIssueComments, pathReposIssueComments},
-	{pathReposIssueEvents, pathReposIssueEvents},
-	{pathReposLabels, pathReposLabels},
-	{pathReposLabel, pathReposLabel},
-	{pathReposIssueLabels, pathReposIssueLabels},
-	{pathReposMilestoneLabels, pathReposMilestoneLabels},
-	{pathReposMilestones, pathReposMilestones},
-	{pathReposMilestone, pathReposMilestone},
-	{pathEmojis, pathEmojis},
-	{pathGitignoreTemplates, pathGitignoreTemplates},
-	{pathGitignoreTemplate, pathGitignoreTemplate},
-	{pathMeta, pathMeta},
-	{pathRateLimit, pathRateLimit},
-	{pathUsersOrgs, pathUsersOrgs},
-	{pathUserOrgs, pathUserOrgs},
-	{pathOrgsOrg, pathOrgsOrg},
-	{pathOrgsMembers, pathOrgsMembers},
-	{pathOrgsMember, pathOrgsMember},
-	{pathOrgsPublicMembers, pathOrgsPublicMembers},
-	{pathOrgsPublicMember, pathOrgsPublicMember},
-	{pathOrgsTeams, pathOrgsTeams},
-	{pathTeamsID, pathTeamsID},
-	{pathTeamsMembers, pathTeamsMembers},
-	{pathTeamsMember, pathTeamsMember},
-	{pathTeamsRepos, pathTeamsRepos},
-	{pathTeamsRepo, pathTeamsRepo},
-	{pathUserTeams, pathUserTeams},
-	{pathReposPulls, pathReposPulls},
-	{pathReposPull, pathReposPull},
-	{pathReposPullCommits, pathReposPullCommits},
-	{pathReposPullFiles, pathReposPullFiles},
-	{pathReposPullMerge, pathReposPullMerge},
-	{pathReposPullComments, pathReposPullComments},
-	{pathUserRepos, pathUserRepos},
-	{pathUsersRepos, pathUsersRepos},
-	{pathOrgsRepos, pathOrgsRepos},
-	{pathRepositories, pathRepositories},
-	{pathRepoOwnerRepo, pathRepoOwnerRepo},
-	{pathReposContributors, pathReposContributors},
-	{pathReposLanguages, pathReposLanguages},
-	{pathReposTeams, pathReposTeams},
-	{pathReposTags, pathReposTags},
-	{pathReposBranches, pathReposBranches},
-	{pathReposBranch, pathReposBranch},
-	{pathReposCollaborators, pathReposCollaborators},
-	{pathReposCollaborator, pathReposCollaborator},
-	{pathReposComments, pathReposComments},
-	{pathReposCommitsSHAComments, pathReposCommitsSHAComments},
-	{pathReposComment, pathReposComment},
-	{pathReposCommits, pathReposCommits},
-	{pathReposCommit, pathReposCommit},
-	{pathReposReadme, pathReposReadme},
-	{pathReposKeys, pathReposKeys},
-	{pathReposKey, pathReposKey},
-	{pathReposDownloads, pathReposDownloads},
-	{pathReposDownload, pathReposDownload},
-	{pathReposForks, pathReposForks},
-	{pathReposHooks, pathReposHooks},
-	{pathReposHook, pathReposHook},
-	{pathReposReleases, pathReposReleases},
-	{pathReposRelease, pathReposRelease},
-	{pathReposReleaseAssets, pathReposReleaseAssets},
-	{pathReposStatsContributors, pathReposStatsContributors},
-	{pathReposStatsCommitActivity, pathReposStatsCommitActivity},
-	{pathReposStatsCodeFrequency, pathReposStatsCodeFrequency},
-	{pathReposStatsParticipation, pathReposStatsParticipation},
-	{pathReposStatsPunchCard, pathReposStatsPunchCard},
-	{pathReposStatuses, pathReposStatuses},
-	{pathSearchRepositories, pathSearchRepositories},
-	{pathSearchCode, pathSearchCode},
-	{pathSearchIssues, pathSearchIssues},
-	{pathSearchUsers, pathSearchUsers},
-	{pathLegacyIssuesSearch, pathLegacyIssuesSearch},
-	{pathLegacyReposSearch, pathLegacyReposSearch},
-	{pathLegacyUserSearch, pathLegacyUserSearch},
-	{pathLegacyUserEmail, pathLegacyUserEmail},
-	{pathUsersUser, pathUsersUser},
-	{pathUser, pathUser},
-	{pathUsers, pathUsers},
-	{pathUserEmails, pathUserEmails},
-	{pathUsersFollowers, pathUsersFollowers},
-	{pathUserFollowers, pathUserFollowers},
-	{pathUsersFollowing, pathUsersFollowing},
-	{pathUserFollowing, pathUserFollowing},
-	{pathUserFollowingUser, pathUserFollowingUser},
-	{pathUsersFollowingTarget, pathUsersFollowingTarget},
-	{pathUsersKeys, pathUsersKeys},
-	{pathUserKeys, pathUserKeys},
-	{pathUserKey, pathUserKey},
-	{pathPeopleUserID, pathPeopleUserID},
-	{pathPeople, pathPeople},
-	{pathActivitiesPeople, pathActivitiesPeople},
-	{pathPeoplePeople, pathPeoplePeople},
-	{pathPeopleOpenIDConnect, pathPeopleOpenIDConnect},
-	{pathPeopleActivities, pathPeopleActivities},
-	{pathActivitiesActivityID, pathActivitiesActivityID},
-	{pathActivities, pathActivities},
-	{pathActivitiesComments, pathActivitiesComments},
-	{pathCommentsCommentID, pathCommentsCommentID},
-	{pathPeopleMoments, pathPeopleMoments},
+	{Key: pathAuthorizations, Value: pathAuthorizations},
+	{Key: pathAuthorizationsID, Value: pathAuthorizationsID},
+	{Key: pathAppsTokens, Value: pathAppsTokens},
+	{Key: pathEvents, Value: pathEvents},
+	{Key: pathReposEvents, Value: pathReposEvents},
+	{Key: pathNetworksEvents, Value: pathNetworksEvents},
+	{Key: pathOrgsEvents, Value: pathOrgsEvents},
+	{Key: pathUsersReceivedEvents, Value: pathUsersReceivedEvents},
+	{Key: pathUsersReceivedEventsPublic, Value: pathUsersReceivedEventsPublic},
+	{Key: pathUsersEvents, Value: pathUsersEvents},
+	{Key: pathUsersEventsPublic, Value: pathUsersEventsPublic},
+	{Key: pathUsersEventsOrgs, Value: pathUsersEventsOrgs},
+	{Key: pathFeeds, Value: pathFeeds},
+	{Key: pathNotifications, Value: pathNotifications},
+	{Key: pathReposNotifications, Value: pathReposNotifications},
+	{Key: pathNotificationThreads, Value: pathNotificationThreads},
+	{Key: pathNotificationThreadSub, Value: pathNotificationThreadSub},
+	{Key: pathReposStargazers, Value: pathReposStargazers},
+	{Key: pathUsersStarred, Value: pathUsersStarred},
+	{Key: pathUserStarred, Value: pathUserStarred},
+	{Key: pathUserStarredOwnerRepo, Value: pathUserStarredOwnerRepo},
+	{Key: pathReposSubscribers, Value: pathReposSubscribers},
+	{Key: pathUsersSubscriptions, Value: pathUsersSubscriptions},
+	{Key: pathUserSubscriptions, Value: pathUserSubscriptions},
+	{Key: pathReposSubscription, Value: pathReposSubscription},
+	{Key: pathUserSubscriptionsOwnerRepo, Value: pathUserSubscriptionsOwnerRepo},
+	{Key: pathUsersGists, Value: pathUsersGists},
+	{Key: pathGists, Value: pathGists},
+	{Key: pathGistsID, Value: pathGistsID},
+	{Key: pathGistsIDStar, Value: pathGistsIDStar},
+	{Key: pathReposGitBlobs, Value: pathReposGitBlobs},
+	{Key: pathReposGitCommits, Value: pathReposGitCommits},
+	{Key: pathReposGitRefs, Value: pathReposGitRefs},
+	{Key: pathReposGitTags, Value: pathReposGitTags},
+	{Key: pathReposGitTrees, Value: pathReposGitTrees},
+	{Key: pathIssues, Value: pathIssues},
+	{Key: pathUserIssues, Value: pathUserIssues},
+	{Key: pathOrgsIssues, Value: pathOrgsIssues},
+	{Key: pathReposIssues, Value: pathReposIssues},
+	{Key: pathReposIssue, Value: pathReposIssue},
+	{Key: pathReposAssignees, Value: pathReposAssignees},
+	{Key: pathReposAssignee, Value: pathReposAssignee},
+	{Key: pathReposIssueComments, Value: pathReposIssueComments},
+	{Key: pathReposIssueEvents, Value: pathReposIssueEvents},
+	{Key: pathReposLabels, Value: pathReposLabels},
+	{Key: pathReposLabel, Value: pathReposLabel},
+	{Key: pathReposIssueLabels, Value: pathReposIssueLabels},
+	{Key: pathReposMilestoneLabels, Value: pathReposMilestoneLabels},
+	{Key: pathReposMilestones, Value: pathReposMilestones},
+	{Key: pathReposMilestone, Value: pathReposMilestone},
+	{Key: pathEmojis, Value: pathEmojis},
+	{Key: pathGitignoreTemplates, Value: pathGitignoreTemplates},
+	{Key: pathGitignoreTemplate, Value: pathGitignoreTemplate},
+	{Key: pathMeta, Value: pathMeta},
+	{Key: pathRateLimit, Value: pathRateLimit},
+	{Key: pathUsersOrgs, Value: pathUsersOrgs},
+	{Key: pathUserOrgs, Value: pathUserOrgs},
+	{Key: pathOrgsOrg, Value: pathOrgsOrg},
+	{Key: pathOrgsMembers, Value: pathOrgsMembers},
+	{Key: pathOrgsMember, Value: pathOrgsMember},
+	{Key: pathOrgsPublicMembers, Value: pathOrgsPublicMembers},
+	{Key: pathOrgsPublicMember, Value: pathOrgsPublicMember},
+	{Key: pathOrgsTeams, Value: pathOrgsTeams},
+	{Key: pathTeamsID, Value: pathTeamsID},
+	{Key: pathTeamsMembers, Value: pathTeamsMembers},
+	{Key: pathTeamsMember, Value: pathTeamsMember},
+	{Key: pathTeamsRepos, Value: pathTeamsRepos},
+	{Key: pathTeamsRepo, Value: pathTeamsRepo},
+	{Key: pathUserTeams, Value: pathUserTeams},
+	{Key: pathReposPulls, Value: pathReposPulls},
+	{Key: pathReposPull, Value: pathReposPull},
+	{Key: pathReposPullCommits, Value: pathReposPullCommits},
+	{Key: pathReposPullFiles, Value: pathReposPullFiles},
+	{Key: pathReposPullMerge, Value: pathReposPullMerge},
+	{Key: pathReposPullComments, Value: pathReposPullComments},
+	{Key: pathUserRepos, Value: pathUserRepos},
+	{Key: pathUsersRepos, Value: pathUsersRepos},
+	{Key: pathOrgsRepos, Value: pathOrgsRepos},
+	{Key: pathRepositories, Value: pathRepositories},
+	{Key: pathRepoOwnerRepo, Value: pathRepoOwnerRepo},
+	{Key: pathReposContributors, Value: pathReposContributors},
+	{Key: pathReposLanguages, Value: pathReposLanguages},
+	{Key: pathReposTeams, Value: pathReposTeams},
+	{Key: pathReposTags, Value: pathReposTags},
+	{Key: pathReposBranches, Value: pathReposBranches},
+	{Key: pathReposBranch, Value: pathReposBranch},
+	{Key: pathReposCollaborators, Value: pathReposCollaborators},
+	{Key: pathReposCollaborator, Value: pathReposCollaborator},
+	{Key: pathReposComments, Value: pathReposComments},
+	{Key: pathReposCommitsSHAComments, Value: pathReposCommitsSHAComments},
+	{Key: pathReposComment, Value: pathReposComment},
+	{Key: pathReposCommits, Value: pathReposCommits},
+	{Key: pathReposCommit, Value: pathReposCommit},
+	{Key: pathReposReadme, Value: pathReposReadme},
+	{Key: pathReposKeys, Value: pathReposKeys},
+	{Key: pathReposKey, Value: pathReposKey},
+	{Key: pathReposDownloads, Value: pathReposDownloads},
+	{Key: pathReposDownload, Value: pathReposDownload},
+	{Key: pathReposForks, Value: pathReposForks},
+	{Key: pathReposHooks, Value: pathReposHooks},
+	{Key: pathReposHook, Value: pathReposHook},
+	{Key: pathReposReleases, Value: pathReposReleases},
+	{Key: pathReposRelease, Value: pathReposRelease},
+	{Key: pathReposReleaseAssets, Value: pathReposReleaseAssets},
+	{Key: pathReposStatsContributors, Value: pathReposStatsContributors},
+	{Key: pathReposStatsCommitActivity, Value: pathReposStatsCommitActivity},
+	{Key: pathReposStatsCodeFrequency, Value: pathReposStatsCodeFrequency},
+	{Key: pathReposStatsParticipation, Value: pathReposStatsParticipation},
+	{Key: pathReposStatsPunchCard, Value: pathReposStatsPunchCard},
+	{Key: pathReposStatuses, Value: pathReposStatuses},
+	{Key: pathSearchRepositories, Value: pathSearchRepositories},
+	{Key: pathSearchCode, Value: pathSearchCode},
+	{Key: pathSearchIssues, Value: pathSearchIssues},
+	{Key: pathSearchUsers, Value: pathSearchUsers},
+	{Key: pathLegacyIssuesSearch, Value: pathLegacyIssuesSearch},
+	{Key: pathLegacyReposSearch, Value: pathLegacyReposSearch},
+	{Key: pathLegacyUserSearch, Value: pathLegacyUserSearch},
+	{Key: pathLegacyUserEmail, Value: pathLegacyUserEmail},
+	{Key: pathUsersUser, Value: pathUsersUser},
+	{Key: pathUser, Value: pathUser},
+	{Key: pathUsers, Value: pathUsers},
+	{Key: pathUserEmails, Value: pathUserEmails},
+	{Key: pathUsersFollowers, Value: pathUsersFollowers},
+	{Key: pathUserFollowers, Value: pathUserFollowers},
+	{Key: pathUsersFollowing, Value: pathUsersFollowing},
+	{Key: pathUserFollowing, Value: pathUserFollowing},
+	{Key: pathUserFollowingUser, Value: pathUserFollowingUser},
+	{Key: pathUsersFollowingTarget, Value: pathUsersFollowingTarget},
+	{Key: pathUsersKeys, Value: pathUsersKeys},
+	{Key: pathUserKeys, Value: pathUserKeys},
+	{Key: pathUserKey, Value: pathUserKey},
+	{Key: pathPeopleUserID, Value: pathPeopleUserID},
+	{Key: pathPeople, Value: pathPeople},
+	{Key: pathActivitiesPeople, Value: pathActivitiesPeople},
+	{Key: pathPeoplePeople, Value: pathPeoplePeople},
+	{Key: pathPeopleOpenIDConnect, Value: pathPeopleOpenIDConnect},
+	{Key: pathPeopleActivities, Value: pathPeopleActivities},
+	{Key: pathActivitiesActivityID, Value: pathActivitiesActivityID},
+	{Key: pathActivities, Value: pathActivities},
+	{Key: pathActivitiesComments, Value: pathActivitiesComments},
+	{Key: pathCommentsCommentID, Value: pathCommentsCommentID},
+	{Key: pathPeopleMoments, Value: pathPeopleMoments},
 }
 
 type testcase struct {
@@ -225,9 +227,9 @@ func TestRouter_Lookup(t *testing.T) {
 	runLookupTest(t, routes(), testcases)
 
 	records := []denco.Record{
-		{"/", testRoute0},
-		{"/:b", testRoute1},
-		{"/*wildcard", testRoute2},
+		{Key: "/", Value: testRoute0},
+		{Key: "/:b", Value: testRoute1},
+		{Key: "/*wildcard", Value: testRoute2},
 	}
 	testcases = []testcase{
 		{"/", testRoute0, nil, true},
@@ -237,10 +239,10 @@ func TestRouter_Lookup(t *testing.T) {
 	runLookupTest(t, records, testcases)
 
 	records = []denco.Record{
-		{pathNetworksEvents, testRoute0},
-		{pathOrgsEvents, testRoute1},
-		{pathNotificationThreads, testRoute2},
-		{"/mypathisgreat/:thing-id", testRoute3},
+		{Key: pathNetworksEvents, Value: testRoute0},
+		{Key: pathOrgsEvents, Value: testRoute1},
+		{Key: pathNotificationThreads, Value: testRoute2},
+		{Key: "/mypathisgreat/:thing-id", Value: testRoute3},
 	}
 	testcases = []testcase{
 		{pathNetworksEvents, testRoute0, []denco.Param{{paramOwner, ":owner"}, {paramRepo, ":repo"}}, true},
@@ -251,25 +253,60 @@ func TestRouter_Lookup(t *testing.T) {
 	runLookupTest(t, records, testcases)
 
 	runLookupTest(t, []denco.Record{
-		{"/", "route2"},
+		{Key: "/", Value: "route2"},
 	}, []testcase{
 		{pathUserAlice, nil, nil, false},
 	})
 
 	runLookupTest(t, []denco.Record{
-		{"/user/:name", "route1"},
+		{Key: "/user/:name", Value: "route1"},
 	}, []testcase{
 		{"/", nil, nil, false},
 	})
 
 	runLookupTest(t, []denco.Record{
-		{"/*wildcard", testRoute0},
-		{"/a/:b", testRoute1},
+		{Key: "/*wildcard", Value: testRoute0},
+		{Key: "/a/:b", Value: testRoute1},
 	}, []testcase{
 		{"/a", testRoute0, []denco.Param{{"wildcard", "a"}}, true},
 	})
 }
 
+func TestRouter_Lookup_withConstraints(t *testing.T) {
+	isNumber := func(value string) bool {
+		_, err := strconv.Atoi(value)
+		return err == nil
+	}
+	isLower := regexp.MustCompile(`^[a-z/]+$`).MatchString
+
+	records := []denco.Record{
+		denco.NewConstrainedRecord("/items/:id", testRoute0, map[string]denco.Constraint{paramID: isNumber}),
+		denco.NewConstrainedRecord("/items/:name", testRoute1, map[string]denco.Constraint{"name": isLower}),
+		denco.NewConstrainedRecord("/items/:id/parts/:part", testRoute2, map[string]denco.Constraint{paramID: isNumber}),
+		{Key: "/items/:name/parts/:part", Value: testRoute3},
+		{Key: "/files/*path", Value: "testroute4", Constraints: map[string]denco.Constraint{"path": isLower}},
+		{Key: "/files/:name", Value: "testroute5"},
+	}
+	testcases := []testcase{
+		{"/items/42", testRoute0, []denco.Param{{paramID, "42"}}, true},
+		{"/items/bolts", testRoute1, []denco.Param{{"name", "bolts"}}, true},
+		{"/items/42/parts/7", testRoute2, []denco.Param{{paramID, "42"}, {"part", "7"}}, true},
+		{"/items/bolts/parts/7", testRoute3, []denco.Param{{"name", "bolts"}, {"part", "7"}}, true},
+		{"/files/docs/a", "testroute4", []denco.Param{{"path", "docs/a"}}, true},
+		// backtracking from the wildcard to the single parameter
+		{"/files/README", "testroute5", []denco.Param{{"name", "README"}}, true},
+		// no candidate satisfies the constraints: the last one of the most constrained matches
+		{"/items/Bolts", testRoute1, []denco.Param{{"name", "Bolts"}}, true},
+		{"/items", nil, nil, false},
+	}
+	runLookupTest(t, records, testcases)
+
+	t.Run("should keep the value of constrained records", func(t *testing.T) {
+		assert.Equal(t, testRoute0, records[0].Value)
+		assert.Len(t, records[0].Constraints, 1)
+	})
+}
+
 func TestRouter_Lookup_withManyRoutes(t *testing.T) {
 	n := 1000
 	records := make([]denco.Record, n)
@@ -464,8 +501,8 @@ func TestRouter_Build(t *testing.T) {
 		r := denco.New()
 		require.Errorf(t,
 			r.Build([]denco.Record{
-				{"/:user/:id/:id", testRoute0},
-				{"/:user/:user/:id", testRoute0},
+				{Key: "/:user/:id/:id", Value: testRoute0},
+				{Key: "/:user/:user/:id", Value: testRoute0},
 			}),
 			"no error returned by duplicate name of path parameters",
 		)
@@ -520,7 +557,7 @@ func TestRouter_Build_withSizeHint(t *testing.T) {
 		r := denco.New()
 		r.SizeHint = v.sizeHint
 		records := []denco.Record{
-			{v.key, paramValue},
+			{Key: v.key, Value: paramValue},
 		}
 		require.NoError(t, r.Build(records))
 		actual := r.SizeHint
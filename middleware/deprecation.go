@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-openapi/spec"
+)
+
+const (
+	// DeprecationDateExtension is the vendor extension declaring when an operation was deprecated,
+	// as an RFC 3339 date or date-time:
+	//
+	//	deprecated: true
+	//	x-deprecation-date: 2026-01-31
+	//
+	// It is announced by the Deprecation header of responses (RFC 9745), as "@" followed by
+	// the Unix time of the date. Deprecated operations without a valid date are announced with
+	// the date set with [Context.SetDeprecationDate].
+	DeprecationDateExtension = "x-deprecation-date"
+
+	// SunsetExtension is the vendor extension declaring when an operation will be removed,
+	// as an RFC 3339 date or date-time. It is announced by the Sunset header of responses (RFC 8594).
+	SunsetExtension = "x-sunset"
+
+	// DeprecationLinkExtension is the vendor extension declaring the URL of the documentation
+	// about the deprecation of an operation, e.g. a migration guide.
+	// It is announced by the Link header of responses.
+	DeprecationLinkExtension = "x-deprecation-link"
+)
+
+// DeprecationHook is called for every request routed to an operation which is deprecated,
+// or declares a [SunsetExtension].
+type DeprecationHook func(r *http.Request, route *MatchedRoute)
+
+// SetDeprecationHook sets the [DeprecationHook] of this Context, e.g. to count the calls
+// to deprecated operations before removing them.
+//
+// Responses from these operations carry Deprecation, Sunset and Link headers regardless of the hook.
+//
+// Returns the receiver for fluent configuration:
+//
+//	ctx := middleware.NewContext(spec, api, nil).SetDeprecationHook(func(r *http.Request, route *middleware.MatchedRoute) {
+//		deprecatedCalls.WithLabelValues(route.Operation.ID).Inc()
+//	})
+func (c *Context) SetDeprecationHook(hook DeprecationHook) *Context {
+	c.deprecationHook = hook
+
+	return c
+}
+
+// SetDeprecationDate sets the date announced by the Deprecation header of deprecated operations
+// which declare no valid [DeprecationDateExtension].
+//
+// It defaults to the time the routes are built, since RFC 9745 requires a date.
+//
+// Returns the receiver for fluent configuration.
+func (c *Context) SetDeprecationDate(date time.Time) *Context {
+	c.deprecationDate = date
+
+	return c
+}
+
+// withDeprecationDate passes the deprecation date of a Context to the [DefaultRouter].
+func withDeprecationDate(date time.Time) DefaultRouterOpt {
+	return func(o *defaultRouterOpts) {
+		o.deprecationDate = date
+	}
+}
+
+// announceDeprecation sets the deprecation headers of a route on a response, and calls the deprecation hook.
+func (c *Context) announceDeprecation(rw http.ResponseWriter, r *http.Request, route *MatchedRoute) {
+	header := rw.Header()
+	for name, values := range route.deprecation {
+		header[name] = values
+	}
+
+	if c.deprecationHook != nil {
+		c.deprecationHook(r, route)
+	}
+}
+
+// deprecationHeaders returns the headers announcing the deprecation or the sunset of an operation,
+// or nil when it is neither deprecated nor sunset.
+//
+// A deprecated operation without a valid date is announced as deprecated since the fallback date.
+func deprecationHeaders(operation *spec.Operation, fallback time.Time, debugLogf func(string, ...any)) http.Header {
+	sunset, hasSunset := extensionDate(operation, SunsetExtension, debugLogf)
+	if !operation.Deprecated && !hasSunset {
+		return nil
+	}
+
+	header := make(http.Header)
+	relation := "sunset"
+	if operation.Deprecated {
+		relation = "deprecation"
+		since, ok := extensionDate(operation, DeprecationDateExtension, debugLogf)
+		if !ok {
+			// RFC 9745 requires the date
+			Logger.Printf("deprecated operation %q has no valid %s: it is announced as deprecated since %s",
+				operation.ID, DeprecationDateExtension, fallback.UTC().Format(time.RFC3339))
+			since = fallback
+		}
+		header.Set("Deprecation", "@"+strconv.FormatInt(since.Unix(), 10))
+	}
+	if hasSunset {
+		header.Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+	}
+	if link, ok := operation.Extensions.GetString(DeprecationLinkExtension); ok && link != "" {
+		header.Set("Link", "<"+link+`>; rel="`+relation+`"`)
+	}
+
+	return header
+}
+
+// extensionDate reads an RFC 3339 date or date-time from a vendor extension of an operation.
+func extensionDate(operation *spec.Operation, name string, debugLogf func(string, ...any)) (time.Time, bool) {
+	value, ok := operation.Extensions.GetString(name)
+	if !ok || value == "" {
+		return time.Time{}, false
+	}
+
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, true
+		}
+	}
+	debugLogf("ignoring %s of operation %q: %q is not an RFC 3339 date", name, operation.ID, value)
+
+	return time.Time{}, false
+}
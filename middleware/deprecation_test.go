@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+const deprecationSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "deprecation", "version": "1.0"},
+  "consumes": ["application/json"],
+  "produces": ["application/json"],
+  "paths": {
+    "/v1/users": {
+      "get": {
+        "operationId": "listUsersV1",
+        "deprecated": true,
+        "x-deprecation-date": "2026-01-31",
+        "x-sunset": "2026-12-31T12:00:00+01:00",
+        "x-deprecation-link": "https://example.com/migrate",
+        "responses": {"200": {"description": "ok"}}
+      }
+    },
+    "/v1/groups": {
+      "get": {"operationId": "listGroupsV1", "deprecated": true, "x-deprecation-date": "last year", "responses": {"200": {"description": "ok"}}}
+    },
+    "/v1/teams": {
+      "get": {"operationId": "listTeamsV1", "deprecated": true, "responses": {"200": {"description": "ok"}}}
+    },
+    "/v1/reports": {
+      "get": {"operationId": "listReportsV1", "x-sunset": "2027-06-30", "x-deprecation-link": "https://example.com/reports", "responses": {"200": {"description": "ok"}}}
+    },
+    "/v2/users": {
+      "get": {"operationId": "listUsers", "responses": {"200": {"description": "ok"}}}
+    }
+  }
+}`
+
+func TestDeprecationHeaders(t *testing.T) {
+	calls := make(map[string]int)
+	ctx := testContext(t, deprecationSpec).SetDeprecationHook(func(_ *http.Request, route *MatchedRoute) {
+		calls[route.Operation.ID]++
+	}).SetDeprecationDate(time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC))
+	handler := ctx.APIHandler(nil)
+
+	for _, tc := range []struct {
+		path        string
+		deprecation string
+		sunset      string
+		link        string
+	}{
+		{"/v1/users", "@1769817600", "Thu, 31 Dec 2026 11:00:00 GMT", `<https://example.com/migrate>; rel="deprecation"`},
+		// deprecated since the date of the Context without a valid date
+		{"/v1/groups", "@1764547200", "", ""},
+		{"/v1/teams", "@1764547200", "", ""},
+		{"/v1/reports", "", "Wed, 30 Jun 2027 00:00:00 GMT", `<https://example.com/reports>; rel="sunset"`},
+		{"/v2/users", "", "", ""},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			for range 2 {
+				rec := serveTest(handler, http.MethodGet, tc.path, nil)
+
+				assert.EqualT(t, http.StatusOK, rec.Code)
+				assert.EqualT(t, tc.deprecation, rec.Header().Get("Deprecation"))
+				assert.EqualT(t, tc.sunset, rec.Header().Get("Sunset"))
+				assert.EqualT(t, tc.link, rec.Header().Get("Link"))
+			}
+		})
+	}
+
+	assert.Equal(t, map[string]int{"listUsersV1": 2, "listGroupsV1": 2, "listTeamsV1": 2, "listReportsV1": 2}, calls)
+}
+
+func TestDeprecationHeaders_DefaultDate(t *testing.T) {
+	before := time.Now().Unix()
+	handler := testContext(t, deprecationSpec).APIHandler(nil)
+	after := time.Now().Unix()
+
+	// deprecated since the routes were built
+	rec := serveTest(handler, http.MethodGet, "/v1/teams", nil)
+	since, ok := strings.CutPrefix(rec.Header().Get("Deprecation"), "@")
+	require.TrueT(t, ok)
+	unix, err := strconv.ParseInt(since, 10, 64)
+	require.NoError(t, err)
+	assert.TrueT(t, unix >= before && unix <= after)
+}
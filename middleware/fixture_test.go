@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	stdcontext "context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/middleware/untyped"
+)
+
+// noopOperation is an operation handler which answers with no content.
+var noopOperation = runtime.OperationHandlerFunc(func(any) (any, error) { return nil, nil })
+
+// testSpec loads an inline JSON spec.
+func testSpec(t testing.TB, raw string) *loads.Document {
+	t.Helper()
+
+	doc, err := loads.Analyzed(json.RawMessage(raw), "")
+	require.NoError(t, err)
+
+	return doc
+}
+
+// testAPI loads an inline JSON spec, and registers a [noopOperation] for each of its operations.
+//
+// Tests register other handlers over these where they matter.
+func testAPI(t testing.TB, raw string) (*loads.Document, *untyped.API) {
+	t.Helper()
+
+	doc := testSpec(t, raw)
+	api := untyped.NewAPI(doc)
+	for method, operations := range doc.Analyzer.Operations() {
+		for path := range operations {
+			api.RegisterOperation(method, path, noopOperation)
+		}
+	}
+
+	return doc, api
+}
+
+// testContext creates a [Context] for an inline JSON spec, with the operations of [testAPI].
+func testContext(t testing.TB, raw string) *Context {
+	t.Helper()
+
+	doc, api := testAPI(t, raw)
+
+	return NewContext(doc, api, nil)
+}
+
+// serveTest serves a request without body, with some headers, and records the response.
+func serveTest(handler http.Handler, method, target string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequestWithContext(stdcontext.Background(), method, target, nil)
+	for name, value := range header {
+		req.Header.Set(name, value)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	return rec
+}
+
+// splitAllow splits the methods of an Allow header.
+func splitAllow(value string) []string {
+	methods := strings.Split(value, ",")
+	for i, method := range methods {
+		methods[i] = strings.TrimSpace(method)
+	}
+
+	return methods
+}
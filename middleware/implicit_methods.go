@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// optionsRouter is implemented by routers which answer OPTIONS requests, see [WithImplicitOptions].
+type optionsRouter interface {
+	answersOptions() bool
+}
+
+func answersOptions(router Router) bool {
+	r, ok := router.(optionsRouter)
+
+	return ok && r.answersOptions()
+}
+
+// serveOptions answers an OPTIONS request with the methods allowed for its path.
+func serveOptions(rw http.ResponseWriter, allowed []string) {
+	methods := slices.Clone(allowed)
+	if !slices.Contains(methods, http.MethodOptions) {
+		methods = append(methods, http.MethodOptions)
+	}
+	slices.Sort(methods)
+
+	rw.Header().Set("Allow", strings.Join(methods, ", "))
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// serveHead serves a HEAD request as a GET request, discarding the body of the response.
+//
+// The response is produced as for a GET request, so that its Content-Length is known.
+func serveHead(next http.Handler, rw http.ResponseWriter, r *http.Request) {
+	get := new(http.Request)
+	*get = *r
+	get.Method = http.MethodGet
+
+	hw := &headResponseWriter{ResponseWriter: rw}
+	next.ServeHTTP(hw, get)
+	hw.commit()
+}
+
+// headResponseWriter discards the body of a response, and sets its Content-Length from the size of
+// the discarded body.
+//
+// The status code is held until the handler returns, unless the response is flushed.
+type headResponseWriter struct {
+	http.ResponseWriter
+
+	status    int
+	size      int64
+	committed bool
+}
+
+func (w *headResponseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+func (w *headResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.size += int64(len(b))
+
+	return len(b), nil
+}
+
+// Flush commits the headers of the response, without a Content-Length.
+func (w *headResponseWriter) Flush() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.size = -1
+	w.commit()
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap supports [http.ResponseController].
+func (w *headResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *headResponseWriter) commit() {
+	if w.committed || w.status == 0 {
+		return
+	}
+	w.committed = true
+
+	header := w.ResponseWriter.Header()
+	if w.size >= 0 && header.Get("Content-Length") == "" && bodyAllowedForStatus(w.status) {
+		header.Set("Content-Length", strconv.FormatInt(w.size, 10))
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+func bodyAllowedForStatus(status int) bool {
+	switch {
+	case status >= 100 && status < 200:
+		return false
+	case status == http.StatusNoContent, status == http.StatusNotModified:
+		return false
+	default:
+		return true
+	}
+}
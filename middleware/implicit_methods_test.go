@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/go-openapi/runtime/internal/testing/petstore"
+)
+
+func TestImplicitHead(t *testing.T) {
+	t.Run("should route HEAD to the GET operation", func(t *testing.T) {
+		handler := corsTestContext(t).SetDefaultRouterOptions(WithImplicitHead()).RoutesHandler(nil)
+
+		get := serveTest(handler, http.MethodGet, "/api/orders", nil)
+		require.EqualT(t, http.StatusOK, get.Code)
+		require.Positive(t, get.Body.Len())
+
+		rec := serveTest(handler, http.MethodHead, "/api/orders", nil)
+		require.EqualT(t, http.StatusOK, rec.Code)
+		assert.EqualT(t, 0, rec.Body.Len())
+		assert.EqualT(t, get.Header().Get("Content-Type"), rec.Header().Get("Content-Type"))
+		assert.EqualT(t, get.Body.Len(), mustAtoi(t, rec.Header().Get("Content-Length")))
+	})
+
+	t.Run("should list HEAD in the allowed methods", func(t *testing.T) {
+		handler := corsTestContext(t).SetDefaultRouterOptions(WithImplicitHead()).RoutesHandler(nil)
+
+		rec := serveTest(handler, http.MethodPut, "/api/orders", nil)
+		require.EqualT(t, http.StatusMethodNotAllowed, rec.Code)
+		assert.ElementsMatch(t, []string{http.MethodGet, http.MethodHead, http.MethodPost}, splitAllow(rec.Header().Get("Allow")))
+	})
+
+	t.Run("should not route HEAD by default", func(t *testing.T) {
+		handler := corsTestContext(t).RoutesHandler(nil)
+
+		rec := serveTest(handler, http.MethodHead, "/api/orders", nil)
+		assert.EqualT(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+}
+
+func TestImplicitOptions(t *testing.T) {
+	t.Run("should answer OPTIONS with the allowed methods", func(t *testing.T) {
+		spec, api := petstore.NewAPI(t)
+		handler := NewContext(spec, api, nil).
+			SetDefaultRouterOptions(WithImplicitHead(), WithImplicitOptions()).
+			RoutesHandler(nil)
+
+		rec := serveTest(handler, http.MethodOptions, "/api/pets/1", nil)
+		require.EqualT(t, http.StatusNoContent, rec.Code)
+		assert.EqualT(t, "DELETE, GET, HEAD, OPTIONS", rec.Header().Get("Allow"))
+		assert.EqualT(t, 0, rec.Body.Len())
+
+		rec = serveTest(handler, http.MethodOptions, "/api/owners", nil)
+		assert.EqualT(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("should leave preflight requests to the CORS policy", func(t *testing.T) {
+		handler := corsTestContext(t).SetDefaultRouterOptions(WithImplicitOptions()).RoutesHandler(nil)
+
+		rec := serveTest(handler, http.MethodOptions, "/api/orders", map[string]string{
+			headerOrigin:        "https://app.example.com",
+			headerRequestMethod: http.MethodGet,
+		})
+		require.EqualT(t, http.StatusNoContent, rec.Code)
+		assert.EqualT(t, "https://app.example.com", rec.Header().Get(headerAllowOrigin))
+		assert.Empty(t, rec.Header().Get("Allow"))
+
+		rec = serveTest(handler, http.MethodOptions, "/api/orders", nil)
+		require.EqualT(t, http.StatusNoContent, rec.Code)
+		assert.EqualT(t, "GET, OPTIONS, POST", rec.Header().Get("Allow"))
+	})
+}
+
+func mustAtoi(t *testing.T, value string) int {
+	t.Helper()
+
+	n, err := strconv.Atoi(value)
+	require.NoError(t, err)
+
+	return n
+}
@@ -0,0 +1,305 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"fmt"
+	fpath "path"
+	"slices"
+	"strings"
+
+	"github.com/go-openapi/runtime"
+)
+
+// RouteOutcome tells how a request is routed.
+type RouteOutcome string
+
+const (
+	// RouteMatched is the outcome of a request which matches a route.
+	RouteMatched RouteOutcome = "matched"
+
+	// RouteMethodNotAllowed is the outcome of a request which matches routes for other methods only (405).
+	RouteMethodNotAllowed RouteOutcome = "method not allowed"
+
+	// RouteNotFound is the outcome of a request which matches no route (404).
+	RouteNotFound RouteOutcome = "not found"
+)
+
+// RouteDescription describes a route served by a router.
+type RouteDescription struct {
+	Method string `json:"method"`
+
+	// PathPattern is the path template of the route, including the base path
+	PathPattern string   `json:"pathPattern"`
+	BasePath    string   `json:"basePath,omitempty"`
+	OperationID string   `json:"operationId,omitempty"`
+	Consumes    []string `json:"consumes,omitempty"`
+	Produces    []string `json:"produces,omitempty"`
+
+	// Security lists the alternative security requirements of the route, as in the spec:
+	// each requirement maps the names of security schemes to their scopes.
+	// An empty requirement allows anonymous requests.
+	Security []map[string][]string `json:"security,omitempty"`
+
+	// Handler is the type of the handler registered for the route
+	Handler string `json:"handler"`
+}
+
+// RouteExplanation reports how a request is routed, see [RouteExplainer].
+type RouteExplanation struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+
+	Outcome RouteOutcome `json:"outcome"`
+
+	// Reason explains the outcome
+	Reason string `json:"reason"`
+
+	// Route is the matched route
+	Route *RouteDescription `json:"route,omitempty"`
+
+	// Params holds the path parameters of the matched route
+	Params RouteParams `json:"params,omitempty"`
+
+	// AllowedMethods lists the methods of the routes matching the path, when the method is not allowed
+	AllowedMethods []string `json:"allowedMethods,omitempty"`
+
+	// Candidates lists the path templates of the routes for the method which would match the path,
+	// when the router knows them. Static segments take precedence over parameters: the matched route is
+	// the most specific one.
+	Candidates []string `json:"candidates,omitempty"`
+}
+
+// RouteExplainer is implemented by routers which describe their routes, such as the [DefaultRouter].
+//
+// [Context] and [Reloadable] implement it as well, for the router they use.
+type RouteExplainer interface {
+	// Routes describes the routes, sorted by path and method.
+	Routes() []RouteDescription
+
+	// Explain reports how a request with a method and an (escaped) path is routed.
+	Explain(method, path string) RouteExplanation
+}
+
+type operationHandlerProvider interface {
+	OperationHandlerFor(method, path string) (runtime.OperationHandler, bool)
+}
+
+// describeRoute describes a route of the default router.
+func describeRoute(method string, entry *routeEntry, api RoutableAPI, apiPath string) RouteDescription {
+	description := RouteDescription{
+		Method:      method,
+		PathPattern: entry.PathPattern,
+		BasePath:    entry.BasePath,
+		OperationID: entry.Operation.ID,
+		Consumes:    entry.Consumes,
+		Produces:    entry.Produces,
+		Handler:     fmt.Sprintf("%T", entry.Handler),
+	}
+
+	// the untyped API wraps operation handlers: report the registered one
+	if provider, ok := api.(operationHandlerProvider); ok {
+		if handler, ok := provider.OperationHandlerFor(method, apiPath); ok {
+			description.Handler = fmt.Sprintf("%T", handler)
+		}
+	}
+
+	for _, auth := range entry.Authenticators {
+		requirement := make(map[string][]string, len(auth.Schemes))
+		if !auth.AllowsAnonymous() {
+			for _, scheme := range auth.Schemes {
+				requirement[scheme] = auth.Scopes[scheme]
+			}
+		}
+		description.Security = append(description.Security, requirement)
+	}
+
+	return description
+}
+
+func sortRoutes(routes []RouteDescription) {
+	slices.SortFunc(routes, func(a, b RouteDescription) int {
+		if c := strings.Compare(a.PathPattern, b.PathPattern); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Method, b.Method)
+	})
+}
+
+// explainRoute explains the routing of a request with any router.
+//
+// The routes of the router are used to find the candidates, when known.
+func explainRoute(router Router, routes []RouteDescription, method, path string) RouteExplanation {
+	method = strings.ToUpper(method)
+	explanation := RouteExplanation{
+		Method: method,
+		Path:   path,
+	}
+
+	for _, route := range routes {
+		if route.Method == method && matchesPattern(route.PathPattern, path) {
+			explanation.Candidates = append(explanation.Candidates, route.PathPattern)
+		}
+	}
+
+	if matched, ok := router.Lookup(method, path); ok {
+		explanation.Outcome = RouteMatched
+		explanation.Params = matched.Params
+		for _, route := range routes {
+			if route.Method == method && route.PathPattern == matched.PathPattern {
+				explanation.Route = &route
+
+				break
+			}
+		}
+		if explanation.Route == nil {
+			explanation.Route = &RouteDescription{
+				Method:      method,
+				PathPattern: matched.PathPattern,
+				BasePath:    matched.BasePath,
+				Handler:     fmt.Sprintf("%T", matched.Handler),
+			}
+			if matched.Operation != nil {
+				explanation.Route.OperationID = matched.Operation.ID
+			}
+		}
+
+		explanation.Reason = fmt.Sprintf("%s %s matches the route %s %s", method, path, method, matched.PathPattern)
+		if matched.Operation != nil && matched.Operation.ID != "" {
+			explanation.Reason += fmt.Sprintf(" of operation %q", matched.Operation.ID)
+		}
+		if len(explanation.Candidates) > 1 {
+			explanation.Reason += fmt.Sprintf(", the most specific of %d candidates", len(explanation.Candidates))
+		}
+
+		return explanation
+	}
+
+	if others := router.OtherMethods(method, path); len(others) > 0 {
+		slices.Sort(others)
+		explanation.Outcome = RouteMethodNotAllowed
+		explanation.AllowedMethods = others
+		explanation.Reason = fmt.Sprintf("no route for method %s matches %s, but routes for %s do", method, path, strings.Join(others, ", "))
+
+		return explanation
+	}
+
+	explanation.Outcome = RouteNotFound
+	explanation.Reason = notFoundReason(routes, method, path)
+
+	return explanation
+}
+
+func notFoundReason(routes []RouteDescription, method, path string) string {
+	if len(routes) == 0 {
+		return fmt.Sprintf("no route matches %s %s", method, path)
+	}
+
+	var hasMethod, inBasePath bool
+	for _, route := range routes {
+		hasMethod = hasMethod || route.Method == method
+		inBasePath = inBasePath || route.BasePath == "" || route.BasePath == "/" ||
+			path == route.BasePath || strings.HasPrefix(path, route.BasePath+"/")
+	}
+
+	switch {
+	case !inBasePath:
+		return fmt.Sprintf("%s is outside of the base path of the API", path)
+	case !hasMethod:
+		return fmt.Sprintf("no route is defined for method %s", method)
+	default:
+		return fmt.Sprintf("no route for method %s matches %s", method, path)
+	}
+}
+
+// matchesPattern tells whether a path matches a path template, segment by segment.
+//
+// A segment with parameters matches any non-empty segment.
+func matchesPattern(pattern, path string) bool {
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(fpath.Clean(path), "/"), "/")
+	if len(patternSegments) != len(pathSegments) {
+		return false
+	}
+
+	for i, segment := range patternSegments {
+		if strings.Contains(segment, "{") {
+			if pathSegments[i] == "" {
+				return false
+			}
+
+			continue
+		}
+
+		if segment != pathSegments[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Routes describes the routes of the default router, sorted by path and method.
+func (d *defaultRouter) Routes() []RouteDescription {
+	return slices.Clone(d.routes)
+}
+
+// Explain reports how a request with a method and an (escaped) path is routed.
+func (d *defaultRouter) Explain(method, path string) RouteExplanation {
+	if len(d.routers) == 0 {
+		return RouteExplanation{
+			Method:  strings.ToUpper(method),
+			Path:    path,
+			Outcome: RouteNotFound,
+			Reason:  "no route is defined",
+		}
+	}
+
+	return explainRoute(d, d.routes, method, path)
+}
+
+// Routes describes the routes served by this Context, sorted by path and method.
+//
+// Routes are only known when the router implements [RouteExplainer], as the [DefaultRouter] does.
+func (c *Context) Routes() []RouteDescription {
+	if explainer, ok := c.ensureRouter().(RouteExplainer); ok {
+		return explainer.Routes()
+	}
+
+	return nil
+}
+
+// Explain reports how a request with a method and an (escaped) path is routed by this Context.
+func (c *Context) Explain(method, path string) RouteExplanation {
+	router := c.ensureRouter()
+	if explainer, ok := router.(RouteExplainer); ok {
+		return explainer.Explain(method, path)
+	}
+
+	return explainRoute(router, nil, method, path)
+}
+
+// Routes describes the routes of the current version of the API.
+func (r *Reloadable) Routes() []RouteDescription {
+	if ctx := r.Context(); ctx != nil {
+		return ctx.Routes()
+	}
+
+	return nil
+}
+
+// Explain reports how a request is routed by the current version of the API.
+func (r *Reloadable) Explain(method, path string) RouteExplanation {
+	if ctx := r.Context(); ctx != nil {
+		return ctx.Explain(method, path)
+	}
+
+	return RouteExplanation{
+		Method:  strings.ToUpper(method),
+		Path:    path,
+		Outcome: RouteNotFound,
+		Reason:  "no API is loaded",
+	}
+}
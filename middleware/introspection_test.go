@@ -0,0 +1,140 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	stdcontext "context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/internal/testing/petstore"
+	"github.com/go-openapi/runtime/middleware/untyped"
+)
+
+func TestContext_Routes(t *testing.T) {
+	spec, api := petstore.NewAPI(t)
+	ctx := NewContext(spec, api, nil)
+
+	routes := ctx.Routes()
+	require.Len(t, routes, 4)
+
+	assert.EqualT(t, http.MethodGet, routes[0].Method)
+	assert.EqualT(t, "/api/pets", routes[0].PathPattern)
+	assert.EqualT(t, "/api", routes[0].BasePath)
+	assert.EqualT(t, "getAllPets", routes[0].OperationID)
+	assert.Equal(t, []map[string][]string{{"basic": {}}}, routes[0].Security)
+	assert.EqualT(t, "*petstore.stubOperationHandler", routes[0].Handler)
+	assert.Contains(t, routes[0].Produces, runtime.JSONMime)
+
+	assert.EqualT(t, http.MethodPost, routes[1].Method)
+	assert.EqualT(t, http.MethodDelete, routes[2].Method)
+	assert.EqualT(t, "/api/pets/{id}", routes[2].PathPattern)
+	assert.Equal(t, []map[string][]string{{"apiKey": {}}}, routes[2].Security)
+	assert.EqualT(t, http.MethodGet, routes[3].Method)
+	assert.Empty(t, routes[3].Security)
+}
+
+func TestContext_Explain(t *testing.T) {
+	spec, api := petstore.NewAPI(t)
+	ctx := NewContext(spec, api, nil)
+
+	t.Run("should explain a match", func(t *testing.T) {
+		explanation := ctx.Explain("get", "/api/pets/42")
+		assert.EqualT(t, RouteMatched, explanation.Outcome)
+		require.NotNil(t, explanation.Route)
+		assert.EqualT(t, "getPetById", explanation.Route.OperationID)
+		assert.EqualT(t, "42", explanation.Params.Get("id"))
+		assert.StringContainsT(t, explanation.Reason, `operation "getPetById"`)
+	})
+
+	t.Run("should explain a method not allowed", func(t *testing.T) {
+		explanation := ctx.Explain(http.MethodPut, "/api/pets")
+		assert.EqualT(t, RouteMethodNotAllowed, explanation.Outcome)
+		assert.Equal(t, []string{http.MethodGet, http.MethodPost}, explanation.AllowedMethods)
+	})
+
+	t.Run("should explain a miss", func(t *testing.T) {
+		explanation := ctx.Explain(http.MethodGet, "/api/owners")
+		assert.EqualT(t, RouteNotFound, explanation.Outcome)
+		assert.StringContainsT(t, explanation.Reason, "no route for method GET")
+
+		explanation = ctx.Explain(http.MethodGet, "/pets")
+		assert.EqualT(t, RouteNotFound, explanation.Outcome)
+		assert.StringContainsT(t, explanation.Reason, "outside of the base path")
+
+		explanation = ctx.Explain(http.MethodPatch, "/api/owners")
+		assert.StringContainsT(t, explanation.Reason, "no route is defined for method PATCH")
+	})
+
+	t.Run("should list the candidates", func(t *testing.T) {
+		doc, err := loads.Analyzed(json.RawMessage(`{
+  "swagger": "2.0",
+  "info": {"title": "candidates", "version": "1.0"},
+  "paths": {
+    "/pets/{id}": {"get": {"operationId": "getPet", "responses": {"200": {"description": "ok"}}}},
+    "/pets/mine": {"get": {"operationId": "getMyPets", "responses": {"200": {"description": "ok"}}}}
+  }
+}`), "")
+		require.NoError(t, err)
+		candidates := untyped.NewAPI(doc)
+		candidates.RegisterOperation("get", "/pets/{id}", runtime.OperationHandlerFunc(func(any) (any, error) { return nil, nil }))
+		candidates.RegisterOperation("get", "/pets/mine", runtime.OperationHandlerFunc(func(any) (any, error) { return nil, nil }))
+
+		explanation := NewContext(doc, candidates, nil).Explain(http.MethodGet, "/pets/mine")
+		assert.EqualT(t, RouteMatched, explanation.Outcome)
+		assert.EqualT(t, "getMyPets", explanation.Route.OperationID)
+		assert.ElementsMatch(t, []string{"/pets/mine", "/pets/{id}"}, explanation.Candidates)
+		assert.StringContainsT(t, explanation.Reason, "the most specific of 2 candidates")
+	})
+}
+
+func TestRoutesAdminHandler(t *testing.T) {
+	spec, api := petstore.NewAPI(t)
+	handler := RoutesAdminHandler(NewContext(spec, api, nil))
+
+	get := func(target, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequestWithContext(stdcontext.Background(), http.MethodGet, target, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	t.Run("should render routes as JSON", func(t *testing.T) {
+		rec := get("/admin/routes", "")
+		require.EqualT(t, http.StatusOK, rec.Code)
+		assert.EqualT(t, runtime.JSONMime, rec.Header().Get(runtime.HeaderContentType))
+
+		var routes []RouteDescription
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &routes))
+		assert.Len(t, routes, 4)
+	})
+
+	t.Run("should render an explanation as JSON", func(t *testing.T) {
+		rec := get("/admin/routes?method=delete&path=/api/pets/1", runtime.JSONMime)
+
+		var explanation RouteExplanation
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &explanation))
+		assert.EqualT(t, RouteMatched, explanation.Outcome)
+		assert.EqualT(t, "deletePet", explanation.Route.OperationID)
+	})
+
+	t.Run("should render HTML", func(t *testing.T) {
+		rec := get("/admin/routes?path=/api/nope", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.8")
+		require.EqualT(t, http.StatusOK, rec.Code)
+		assert.StringContainsT(t, rec.Header().Get(runtime.HeaderContentType), runtime.HTMLMime)
+		assert.StringContainsT(t, rec.Body.String(), "<code>/api/pets/{id}</code>")
+		assert.StringContainsT(t, rec.Body.String(), "GET /api/nope: not found")
+	})
+}
@@ -0,0 +1,304 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"cmp"
+	"mime"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-openapi/errors"
+)
+
+// MountOption configures how requests are dispatched to an API mounted on a [MultiAPI].
+type MountOption func(*mountOpts)
+
+type mountOpts struct {
+	hosts      []string
+	anyHost    bool
+	headers    map[string]string
+	mediaTypes []string
+	handler    http.Handler
+}
+
+// WithMountHosts sets the hosts served by an API (default: the host declared by its spec, if any).
+//
+// Without hosts, the API is served for any host.
+func WithMountHosts(hosts ...string) MountOption {
+	return func(o *mountOpts) {
+		o.hosts = hosts
+		o.anyHost = len(hosts) == 0
+	}
+}
+
+// WithMountHeader serves an API for requests with a header set to a value, e.g. an API version header.
+func WithMountHeader(name, value string) MountOption {
+	return func(o *mountOpts) {
+		if o.headers == nil {
+			o.headers = make(map[string]string)
+		}
+		o.headers[http.CanonicalHeaderKey(name)] = value
+	}
+}
+
+// WithMountMediaType serves an API for requests which accept, or send, a media type,
+// e.g. "application/vnd.acme.v2+json" or "application/json; version=2".
+//
+// Media types match regardless of the parameters of requests which are not declared by the option.
+func WithMountMediaType(mediaTypes ...string) MountOption {
+	return func(o *mountOpts) {
+		o.mediaTypes = append(o.mediaTypes, mediaTypes...)
+	}
+}
+
+// WithMountHandler sets the handler serving an API (default: [Context.APIHandler], without builder,
+// serving the spec at {base path}/swagger.json).
+func WithMountHandler(handler http.Handler) MountOption {
+	return func(o *mountOpts) {
+		o.handler = handler
+	}
+}
+
+// MultiAPI serves several APIs on a single handler, dispatching requests to their [Context]
+// by host, base path, header or media type.
+//
+// Among the APIs which accept a request, the most specific serves it: the API with the most matching
+// rules (host, headers and media types), then with the longest base path, then the first mounted.
+// Each API serves its own spec at {base path}/swagger.json, and its documentation at {base path}/docs.
+//
+// Requests matching no route of these APIs are answered with a 404, or a 405 listing the methods
+// allowed for the path by all of them. These errors are served by the most specific API, or the first
+// mounted when no API accepts the request, e.g. as problem details (see [Context.SetProblemDetails]).
+//
+// APIs are mounted before serving requests:
+//
+//	api := middleware.NewMultiAPI().
+//		Mount(v1).
+//		Mount(v2, middleware.WithMountMediaType("application/vnd.acme.v2+json"))
+type MultiAPI struct {
+	mounts []*mountedAPI
+}
+
+type mountedAPI struct {
+	context    *Context
+	handler    http.Handler
+	basePath   string
+	hosts      []string
+	headers    map[string]string
+	mediaTypes []string
+	order      int
+}
+
+// NewMultiAPI creates a handler for several APIs, which are added with [MultiAPI.Mount].
+func NewMultiAPI() *MultiAPI {
+	return &MultiAPI{}
+}
+
+// Mount adds an API.
+//
+// Returns the receiver for fluent configuration.
+func (m *MultiAPI) Mount(ctx *Context, opts ...MountOption) *MultiAPI {
+	var o mountOpts
+	for _, apply := range opts {
+		apply(&o)
+	}
+
+	if o.hosts == nil && !o.anyHost && ctx.spec != nil && ctx.spec.Spec() != nil && ctx.spec.Spec().Host != "" {
+		o.hosts = []string{ctx.spec.Spec().Host}
+	}
+
+	ctx.ensureRouter()
+	basePath := strings.TrimSuffix(ctx.BasePath(), "/")
+	if o.handler == nil {
+		o.handler = ctx.APIHandler(nil, WithUISpecURL(basePath+"/swagger.json"))
+	}
+
+	m.mounts = append(m.mounts, &mountedAPI{
+		context:    ctx,
+		handler:    o.handler,
+		basePath:   basePath,
+		hosts:      o.hosts,
+		headers:    o.headers,
+		mediaTypes: o.mediaTypes,
+		order:      len(m.mounts),
+	})
+
+	return m
+}
+
+// ServeHTTP dispatches a request to the API which serves it.
+func (m *MultiAPI) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	candidates := m.candidates(r)
+	if len(candidates) == 0 {
+		m.serveError(rw, r, nil, errors.NotFound("path %s was not found", r.URL.EscapedPath()))
+
+		return
+	}
+
+	path := r.URL.EscapedPath()
+	for _, mount := range candidates {
+		if _, ok := mount.context.router.Lookup(r.Method, path); ok {
+			mount.handler.ServeHTTP(rw, r)
+
+			return
+		}
+	}
+
+	// the path is not routed for this method: combine the methods of all the APIs
+	var (
+		allowed []string
+		owner   *mountedAPI
+	)
+	for _, mount := range candidates {
+		others := mount.context.AllowedMethods(r)
+		if len(others) == 0 {
+			continue
+		}
+		if owner == nil {
+			owner = mount
+		}
+		for _, method := range others {
+			if !slices.Contains(allowed, method) {
+				allowed = append(allowed, method)
+			}
+		}
+	}
+
+	switch {
+	case owner == nil:
+		// not a route: the most specific API serves its spec, documentation, or a 404
+		candidates[0].handler.ServeHTTP(rw, r)
+	case r.Method == http.MethodOptions:
+		// CORS preflight or implicit OPTIONS, when enabled
+		owner.handler.ServeHTTP(rw, r)
+	default:
+		slices.Sort(allowed)
+		m.serveError(rw, r, candidates[0], errors.MethodNotAllowed(r.Method, allowed))
+	}
+}
+
+// serveError serves an error like an API does, e.g. as problem details (see [Context.SetProblemDetails]).
+//
+// Without an API accepting the request, the first mounted API serves it.
+func (m *MultiAPI) serveError(rw http.ResponseWriter, r *http.Request, mount *mountedAPI, err error) {
+	if mount == nil {
+		if len(m.mounts) == 0 {
+			errors.ServeError(rw, r, err)
+
+			return
+		}
+		mount = m.mounts[0]
+	}
+
+	mount.context.Respond(rw, r, []string{mount.context.api.DefaultProduces()}, nil, err)
+}
+
+// candidates returns the APIs which accept a request, the most specific first.
+func (m *MultiAPI) candidates(r *http.Request) []*mountedAPI {
+	candidates := make([]*mountedAPI, 0, len(m.mounts))
+	for _, mount := range m.mounts {
+		if mount.accepts(r) {
+			candidates = append(candidates, mount)
+		}
+	}
+
+	slices.SortFunc(candidates, func(a, b *mountedAPI) int {
+		return cmp.Or(
+			cmp.Compare(b.rules(), a.rules()),
+			cmp.Compare(len(b.basePath), len(a.basePath)),
+			cmp.Compare(a.order, b.order),
+		)
+	})
+
+	return candidates
+}
+
+func (a *mountedAPI) rules() int {
+	rules := len(a.headers)
+	if len(a.hosts) > 0 {
+		rules++
+	}
+	if len(a.mediaTypes) > 0 {
+		rules++
+	}
+
+	return rules
+}
+
+func (a *mountedAPI) accepts(r *http.Request) bool {
+	if a.basePath != "" {
+		path := r.URL.Path
+		if path != a.basePath && !strings.HasPrefix(path, a.basePath+"/") {
+			return false
+		}
+	}
+
+	if len(a.hosts) > 0 && !slices.ContainsFunc(a.hosts, func(host string) bool { return matchesHost(host, r.Host) }) {
+		return false
+	}
+
+	for name, value := range a.headers {
+		if r.Header.Get(name) != value {
+			return false
+		}
+	}
+
+	if len(a.mediaTypes) > 0 && !slices.ContainsFunc(a.mediaTypes, func(mediaType string) bool { return requestHasMediaType(r, mediaType) }) {
+		return false
+	}
+
+	return true
+}
+
+// matchesHost tells if the host of a request is the host of an API. The port is only compared
+// when the API declares one.
+func matchesHost(apiHost, requestHost string) bool {
+	if _, _, err := net.SplitHostPort(apiHost); err == nil {
+		return strings.EqualFold(apiHost, requestHost)
+	}
+
+	if host, _, err := net.SplitHostPort(requestHost); err == nil {
+		requestHost = host
+	}
+
+	return strings.EqualFold(apiHost, requestHost)
+}
+
+// requestHasMediaType tells if a request accepts or sends a media type, with the parameters it declares.
+func requestHasMediaType(r *http.Request, mediaType string) bool {
+	wantType, wantParams, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		return false
+	}
+
+	offered := strings.Split(r.Header.Get("Accept"), ",")
+	offered = append(offered, r.Header.Get("Content-Type"))
+	for _, value := range offered {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+
+		gotType, gotParams, err := mime.ParseMediaType(value)
+		if err != nil || !strings.EqualFold(gotType, wantType) {
+			continue
+		}
+
+		matches := true
+		for name, want := range wantParams {
+			if got, ok := gotParams[name]; !ok || !strings.EqualFold(got, want) {
+				matches = false
+
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+
+	return false
+}
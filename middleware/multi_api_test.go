@@ -0,0 +1,161 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/middleware/untyped"
+)
+
+const vendorMime = "application/vnd.acme.v2+json"
+
+// multiAPIContext builds an API whose operations answer with its title.
+func multiAPIContext(t *testing.T, title, host, basePath string, methods ...string) *Context {
+	t.Helper()
+
+	operations := make(map[string]any, len(methods))
+	for _, method := range methods {
+		operations[method] = map[string]any{
+			"operationId": method + "Items",
+			"responses":   map[string]any{"200": map[string]any{"description": "ok"}},
+		}
+	}
+	raw, err := json.Marshal(map[string]any{
+		"swagger":  "2.0",
+		"info":     map[string]any{"title": title, "version": "1.0"},
+		"host":     host,
+		"basePath": basePath,
+		"consumes": []string{runtime.JSONMime},
+		"produces": []string{runtime.JSONMime, vendorMime},
+		"paths":    map[string]any{"/items": operations},
+	})
+	require.NoError(t, err)
+
+	doc := testSpec(t, string(raw))
+	api := untyped.NewAPI(doc)
+	api.RegisterProducer(vendorMime, runtime.JSONProducer())
+	for _, method := range methods {
+		api.RegisterOperation(method, "/items", runtime.OperationHandlerFunc(func(any) (any, error) {
+			return title, nil
+		}))
+	}
+
+	return NewContext(doc, api, nil)
+}
+
+func TestMultiAPI(t *testing.T) {
+	served := func(t *testing.T, handler http.Handler, method, target string, header map[string]string) string {
+		t.Helper()
+
+		rec := serveTest(handler, method, target, header)
+		require.EqualT(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var title string
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &title))
+
+		return title
+	}
+
+	t.Run("should dispatch by base path", func(t *testing.T) {
+		handler := NewMultiAPI().
+			Mount(multiAPIContext(t, "v1", "", "/v1", "get")).
+			Mount(multiAPIContext(t, "v2", "", "/v2", "get"))
+
+		assert.EqualT(t, "v1", served(t, handler, http.MethodGet, "/v1/items", nil))
+		assert.EqualT(t, "v2", served(t, handler, http.MethodGet, "/v2/items", nil))
+	})
+
+	t.Run("should dispatch by host", func(t *testing.T) {
+		handler := NewMultiAPI().
+			Mount(multiAPIContext(t, "a", "a.example.com", "/api", "get")).
+			Mount(multiAPIContext(t, "b", "b.example.com", "/api", "get")).
+			Mount(multiAPIContext(t, "any", "c.example.com", "/api", "get"), WithMountHosts())
+
+		req := func(host string) string {
+			return served(t, http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				r.Host = host
+				handler.ServeHTTP(rw, r)
+			}), http.MethodGet, "/api/items", nil)
+		}
+		assert.EqualT(t, "a", req("a.example.com"))
+		assert.EqualT(t, "b", req("B.example.com:8080"))
+		assert.EqualT(t, "any", req("localhost:8080"))
+	})
+
+	t.Run("should dispatch by header and media type", func(t *testing.T) {
+		handler := NewMultiAPI().
+			Mount(multiAPIContext(t, "v1", "", "/api", "get")).
+			Mount(multiAPIContext(t, "v2", "", "/api", "get"), WithMountMediaType(vendorMime)).
+			Mount(multiAPIContext(t, "v3", "", "/api", "get"), WithMountHeader("x-api-version", "3"))
+
+		assert.EqualT(t, "v1", served(t, handler, http.MethodGet, "/api/items", nil))
+		assert.EqualT(t, "v2", served(t, handler, http.MethodGet, "/api/items", map[string]string{
+			"Accept": vendorMime + ";q=0.9, */*;q=0.1",
+		}))
+		assert.EqualT(t, "v3", served(t, handler, http.MethodGet, "/api/items", map[string]string{
+			"X-Api-Version": "3",
+		}))
+	})
+
+	t.Run("should answer unmatched requests for all the APIs", func(t *testing.T) {
+		handler := NewMultiAPI().
+			Mount(multiAPIContext(t, "v1", "", "/api", "get")).
+			Mount(multiAPIContext(t, "v2", "", "/api", "post", "put"), WithMountHeader("X-Api-Version", "2"))
+
+		rec := serveTest(handler, http.MethodDelete, "/api/items", map[string]string{"X-Api-Version": "2"})
+		require.EqualT(t, http.StatusMethodNotAllowed, rec.Code)
+		assert.EqualT(t, "GET,POST,PUT", rec.Header().Get("Allow"))
+
+		rec = serveTest(handler, http.MethodDelete, "/api/items", nil)
+		require.EqualT(t, http.StatusMethodNotAllowed, rec.Code)
+		assert.EqualT(t, "GET", rec.Header().Get("Allow"))
+
+		rec = serveTest(handler, http.MethodGet, "/api/orders", nil)
+		assert.EqualT(t, http.StatusNotFound, rec.Code)
+
+		rec = serveTest(handler, http.MethodGet, "/other/items", nil)
+		assert.EqualT(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("should answer unmatched requests like the APIs", func(t *testing.T) {
+		handler := NewMultiAPI().
+			Mount(multiAPIContext(t, "v1", "", "/api", "get").SetProblemDetails(true)).
+			Mount(multiAPIContext(t, "v2", "", "/api", "post"), WithMountHeader("X-Api-Version", "2"))
+
+		for _, tc := range []struct {
+			method, target string
+			header         map[string]string
+			status         int
+			contentType    string
+		}{
+			{http.MethodDelete, "/api/items", nil, http.StatusMethodNotAllowed, runtime.ProblemJSONMime},
+			{http.MethodGet, "/other/items", nil, http.StatusNotFound, runtime.ProblemJSONMime},
+			{http.MethodDelete, "/api/items", map[string]string{"X-Api-Version": "2"}, http.StatusMethodNotAllowed, runtime.JSONMime},
+		} {
+			rec := serveTest(handler, tc.method, tc.target, tc.header)
+			require.EqualT(t, tc.status, rec.Code)
+			assert.EqualT(t, tc.contentType, rec.Header().Get(runtime.HeaderContentType))
+		}
+	})
+
+	t.Run("should serve the spec of each API", func(t *testing.T) {
+		handler := NewMultiAPI().
+			Mount(multiAPIContext(t, "v1", "", "/v1", "get")).
+			Mount(multiAPIContext(t, "v2", "", "/v2", "get"))
+
+		for _, version := range []string{"v1", "v2"} {
+			rec := serveTest(handler, http.MethodGet, fmt.Sprintf("/%s/swagger.json", version), nil)
+			require.EqualT(t, http.StatusOK, rec.Code)
+			assert.StringContainsT(t, rec.Body.String(), fmt.Sprintf(`"title":%q`, version))
+		}
+	})
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// OperationMiddlewares is a registry of middleware applied to some operations only,
+// selected by tag, operation ID or vendor extension.
+//
+// Middleware are resolved once, when routes are built, and wrap the handler of each operation they select,
+// in the order they were registered: the first registered is the outermost.
+// They run within the [Builder] applied to the whole API.
+//
+//	ops := middleware.NewOperationMiddlewares().
+//		ForTag("admin", audit).
+//		ForExtension("x-expensive", func(v any) bool { return v == true }, rateLimit)
+//
+//	ctx := middleware.NewContext(spec, api, nil).SetDefaultRouterOptions(
+//		middleware.WithOperationMiddlewares(ops),
+//	)
+type OperationMiddlewares struct {
+	rules []operationMiddlewareRule
+}
+
+type operationMiddlewareRule struct {
+	selects  func(*spec.Operation) bool
+	builders []Builder
+}
+
+// NewOperationMiddlewares creates an empty registry of operation middleware.
+func NewOperationMiddlewares() *OperationMiddlewares {
+	return &OperationMiddlewares{}
+}
+
+// ForTag applies middleware to the operations with a tag.
+//
+// Returns the receiver for fluent configuration.
+func (m *OperationMiddlewares) ForTag(tag string, builders ...Builder) *OperationMiddlewares {
+	return m.For(func(operation *spec.Operation) bool {
+		return slices.Contains(operation.Tags, tag)
+	}, builders...)
+}
+
+// ForOperation applies middleware to the operations with an ID.
+//
+// Returns the receiver for fluent configuration.
+func (m *OperationMiddlewares) ForOperation(operationID string, builders ...Builder) *OperationMiddlewares {
+	return m.For(func(operation *spec.Operation) bool {
+		return operation.ID == operationID
+	}, builders...)
+}
+
+// ForExtension applies middleware to the operations with a vendor extension, e.g. "x-expensive" (regardless of case),
+// whose value satisfies a predicate. A nil predicate selects the operations declaring the extension.
+//
+// Values are decoded from the spec as JSON values: bool, float64, string, []any or map[string]any.
+//
+// Returns the receiver for fluent configuration.
+func (m *OperationMiddlewares) ForExtension(name string, predicate func(value any) bool, builders ...Builder) *OperationMiddlewares {
+	return m.For(func(operation *spec.Operation) bool {
+		for key, value := range operation.Extensions {
+			if strings.EqualFold(key, name) {
+				return predicate == nil || predicate(value)
+			}
+		}
+
+		return false
+	}, builders...)
+}
+
+// For applies middleware to the operations selected by a function.
+//
+// Returns the receiver for fluent configuration.
+func (m *OperationMiddlewares) For(selects func(*spec.Operation) bool, builders ...Builder) *OperationMiddlewares {
+	m.rules = append(m.rules, operationMiddlewareRule{selects: selects, builders: builders})
+
+	return m
+}
+
+// wrap wraps the handler of an operation with the middleware selecting it.
+func (m *OperationMiddlewares) wrap(operation *spec.Operation, handler http.Handler) http.Handler {
+	if m == nil {
+		return handler
+	}
+
+	for _, rule := range slices.Backward(m.rules) {
+		if !rule.selects(operation) {
+			continue
+		}
+		for _, builder := range slices.Backward(rule.builders) {
+			handler = builder(handler)
+		}
+	}
+
+	return handler
+}
+
+// WithOperationMiddlewares wraps the handler of operations with the middleware of a registry.
+func WithOperationMiddlewares(middlewares *OperationMiddlewares) DefaultRouterOpt {
+	return func(o *defaultRouterOpts) {
+		o.operationMiddlewares = middlewares
+	}
+}
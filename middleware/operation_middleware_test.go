@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/testify/v2/assert"
+)
+
+const operationMiddlewareSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "operation middleware", "version": "1.0"},
+  "consumes": ["application/json"],
+  "produces": ["application/json"],
+  "paths": {
+    "/users": {
+      "get": {"operationId": "listUsers", "tags": ["users"], "responses": {"200": {"description": "ok"}}},
+      "delete": {"operationId": "purgeUsers", "tags": ["users", "admin"], "x-expensive": true, "responses": {"204": {"description": "purged"}}}
+    },
+    "/reports": {
+      "get": {"operationId": "getReport", "x-Expensive": true, "x-timeout": "30s", "responses": {"200": {"description": "ok"}}}
+    },
+    "/health": {
+      "get": {"operationId": "health", "x-expensive": false, "responses": {"200": {"description": "ok"}}}
+    }
+  }
+}`
+
+// tracing returns a middleware adding its name to the X-Trace header of the response.
+func tracing(name string) Builder {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.Header().Add("X-Trace", name)
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+func TestOperationMiddlewares(t *testing.T) {
+	ops := NewOperationMiddlewares().
+		ForTag("admin", tracing("audit")).
+		ForExtension("x-expensive", func(v any) bool { return v == true }, tracing("limit"), tracing("queue")).
+		ForExtension("X-Timeout", nil, tracing("timeout")).
+		ForOperation("listUsers", tracing("cache")).
+		For(func(operation *spec.Operation) bool { return operation.ID == "health" }, tracing("health"))
+
+	ctx := testContext(t, operationMiddlewareSpec).SetDefaultRouterOptions(WithOperationMiddlewares(ops))
+	handler := ctx.APIHandler(nil)
+
+	for _, tc := range []struct {
+		method   string
+		path     string
+		code     int
+		expected []string
+	}{
+		{http.MethodGet, "/users", http.StatusOK, []string{"cache"}},
+		{http.MethodDelete, "/users", http.StatusNoContent, []string{"audit", "limit", "queue"}},
+		{http.MethodGet, "/reports", http.StatusOK, []string{"limit", "queue", "timeout"}},
+		{http.MethodGet, "/health", http.StatusOK, []string{"health"}},
+	} {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			rec := serveTest(handler, tc.method, tc.path, nil)
+
+			assert.EqualT(t, tc.code, rec.Code)
+			assert.Equal(t, tc.expected, rec.Header().Values("X-Trace"))
+		})
+	}
+
+	t.Run("routes describe the operation handlers", func(t *testing.T) {
+		for _, route := range ctx.Routes() {
+			assert.TrueT(t, strings.HasSuffix(route.Handler, "OperationHandlerFunc"), route.Handler)
+		}
+	})
+}
+
+func TestOperationMiddlewares_None(t *testing.T) {
+	var ops *OperationMiddlewares
+	handler := http.RedirectHandler("/", http.StatusFound)
+
+	assert.Equal(t, handler, ops.wrap(&spec.Operation{}, handler))
+}
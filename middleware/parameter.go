@@ -49,6 +49,7 @@ type untypedParamBinder struct {
 	formats   strfmt.Registry
 	Name      string
 	validator validate.EntityValidator
+	inspector *runtime.UploadInspector
 }
 
 func (p *untypedParamBinder) Type() reflect.Type {
@@ -154,6 +155,12 @@ func (p *untypedParamBinder) bindFormData(request *http.Request, _ RouteParams,
 			return err
 		}
 
+		if p.inspector != nil {
+			if _, err := p.inspector.InspectFile(request.Context(), p.parameter.Name, file, header); err != nil {
+				return err
+			}
+		}
+
 		target.Set(reflect.ValueOf(runtime.File{Data: file, Header: header}))
 		return nil
 	}
@@ -0,0 +1,257 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"net/http"
+	fpath "path"
+	"regexp"
+	"strings"
+
+	"github.com/go-openapi/errors"
+)
+
+// PathPolicy tells how the [DefaultRouter] treats request paths which are not in their canonical form,
+// e.g. with a trailing slash, duplicate slashes or, with [WithCaseInsensitivePaths], a different case.
+type PathPolicy uint8
+
+const (
+	// PathLenient routes a path by its canonical form. This is the default.
+	PathLenient PathPolicy = iota
+
+	// PathStrict answers requests on a path which is not in its canonical form with a 404.
+	PathStrict
+
+	// PathRedirect redirects requests on a path which is not in its canonical form to the canonical path,
+	// with a 301 for GET and HEAD requests, and a 308 for other methods, so that they are sent again
+	// with the same method and body.
+	PathRedirect
+)
+
+// PathOption configures how request paths are matched with routes (see [Context.SetPathPolicy]).
+type PathOption func(*pathPolicy)
+
+// WithCaseInsensitivePaths matches the literal segments of paths regardless of their case,
+// e.g. "/API/Pets" with "/api/pets". Path parameters keep the case of the request.
+func WithCaseInsensitivePaths() PathOption {
+	return func(p *pathPolicy) {
+		p.caseInsensitive = true
+	}
+}
+
+// WithEncodedSlashes keeps encoded slashes ("%2F") in the values of path parameters,
+// which are otherwise decoded like any escaped character once the path is matched.
+//
+// This tells a parameter "a%2Fb" apart from two path segments "a/b".
+func WithEncodedSlashes() PathOption {
+	return func(p *pathPolicy) {
+		p.encodedSlashes = true
+	}
+}
+
+// pathPolicy is the path policy of a Context.
+type pathPolicy struct {
+	policy          PathPolicy
+	caseInsensitive bool
+	encodedSlashes  bool
+}
+
+// SetPathPolicy sets how request paths which are not in their canonical form are routed
+// by the [DefaultRouter], and how paths are matched with routes.
+//
+// The canonical form of a path has no trailing slash, no duplicate slashes and no dot segments,
+// and the case of the spec with [WithCaseInsensitivePaths].
+//
+// Returns the receiver for fluent configuration:
+//
+//	ctx := middleware.NewContext(spec, api, nil).SetPathPolicy(
+//		middleware.PathRedirect,
+//		middleware.WithCaseInsensitivePaths(),
+//	)
+func (c *Context) SetPathPolicy(policy PathPolicy, opts ...PathOption) *Context {
+	paths := &pathPolicy{policy: policy}
+	for _, apply := range opts {
+		apply(paths)
+	}
+	c.paths = paths
+
+	return c
+}
+
+// servePathPolicy answers requests on a path which is not in its canonical form,
+// when the policy is not lenient. It returns true when the request is answered.
+func (c *Context) servePathPolicy(rw http.ResponseWriter, r *http.Request) bool {
+	if c.paths == nil || c.paths.policy == PathLenient {
+		return false
+	}
+
+	canonicalizer, ok := c.router.(pathCanonicalizer)
+	if !ok {
+		return false
+	}
+
+	path := r.URL.EscapedPath()
+	canonical, ok := canonicalizer.canonicalPath(path)
+	if !ok || canonical == path {
+		return false
+	}
+
+	if c.paths.policy == PathStrict {
+		c.Respond(rw, r, []string{c.api.DefaultProduces()}, nil, errors.NotFound("path %s was not found", path))
+
+		return true
+	}
+
+	location := canonical
+	if r.URL.RawQuery != "" {
+		location += "?" + r.URL.RawQuery
+	}
+	code := http.StatusPermanentRedirect
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		code = http.StatusMovedPermanently
+	}
+	rw.Header().Set("Location", location)
+	rw.WriteHeader(code)
+
+	return true
+}
+
+// withPathPolicy passes the options of the path policy of a Context to the [DefaultRouter].
+func withPathPolicy(paths *pathPolicy) DefaultRouterOpt {
+	return func(o *defaultRouterOpts) {
+		o.caseInsensitive = paths.caseInsensitive
+		o.encodedSlashes = paths.encodedSlashes
+	}
+}
+
+// pathCanonicalizer is implemented by routers which know the canonical form of the paths they route.
+type pathCanonicalizer interface {
+	// canonicalPath returns the canonical form of an escaped path, and whether it is routed.
+	canonicalPath(path string) (string, bool)
+}
+
+var encodedSlash = regexp.MustCompile(`%2[fF]`)
+
+func (d *defaultRouter) canonicalPath(path string) (string, bool) {
+	clean := fpath.Clean(path)
+	if d.routed(clean) {
+		return clean, true
+	}
+
+	if d.caseInsensitive {
+		if folded, ok := d.foldCase(clean); ok && d.routed(folded) {
+			return folded, true
+		}
+	}
+
+	return clean, false
+}
+
+// routed tells if a path is routed for some method.
+func (d *defaultRouter) routed(path string) bool {
+	cleanPath := fpath.Clean(escapeLiteralColons(path))
+	for _, router := range d.routers {
+		if _, _, ok := router.Lookup(cleanPath); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// foldCase returns a path with the case of the literal segments of the route it matches regardless of case.
+//
+// Segments with parameters, including composite ones like "{name}.{ext}", keep the case of the request.
+// When several routes match, the route with the most literal segments is preferred.
+func (d *defaultRouter) foldCase(path string) (string, bool) {
+	if d.folded == nil {
+		return "", false
+	}
+
+	segments := strings.Split(fpath.Clean(path), "/")
+	best, _, _ := d.folded.match(segments)
+	if best == nil {
+		return "", false
+	}
+
+	folded := make([]string, len(segments))
+	for i, segment := range best {
+		if strings.Contains(segment, "{") {
+			folded[i] = segments[i]
+		} else {
+			folded[i] = segment
+		}
+	}
+
+	return strings.Join(folded, "/"), true
+}
+
+// foldedRoutes is a tree of the patterns of the routes, by lower case literal segment,
+// to match paths regardless of case.
+//
+// It is built once with the router, when paths are case-insensitive (see [WithCaseInsensitivePaths]).
+type foldedRoutes struct {
+	literals map[string]*foldedRoutes
+	param    *foldedRoutes // segments with parameters
+	pattern  []string      // the segments of the route ending here, if any
+	rank     int           // the rank of this route: among matches with as many literal segments, the first one wins
+}
+
+func newFoldedRoutes(routes []RouteDescription) *foldedRoutes {
+	root := &foldedRoutes{}
+	for rank, route := range routes {
+		pattern := strings.Split(route.PathPattern, "/")
+		node := root
+		for _, segment := range pattern {
+			if strings.Contains(segment, "{") {
+				if node.param == nil {
+					node.param = &foldedRoutes{}
+				}
+				node = node.param
+
+				continue
+			}
+
+			key := strings.ToLower(segment)
+			child, ok := node.literals[key]
+			if !ok {
+				if node.literals == nil {
+					node.literals = make(map[string]*foldedRoutes)
+				}
+				child = &foldedRoutes{}
+				node.literals[key] = child
+			}
+			node = child
+		}
+
+		if node.pattern == nil {
+			node.pattern, node.rank = pattern, rank
+		}
+	}
+
+	return root
+}
+
+// match returns the pattern of the route which matches some segments with the most literal segments,
+// with the number of these literal segments and the rank of the route.
+func (n *foldedRoutes) match(segments []string) (pattern []string, static, rank int) {
+	if len(segments) == 0 {
+		return n.pattern, 0, n.rank
+	}
+
+	if child, ok := n.literals[strings.ToLower(segments[0])]; ok {
+		if found, literals, r := child.match(segments[1:]); found != nil {
+			pattern, static, rank = found, literals+1, r
+		}
+	}
+
+	if n.param != nil && segments[0] != "" {
+		found, literals, r := n.param.match(segments[1:])
+		if found != nil && (pattern == nil || literals > static || literals == static && r < rank) {
+			pattern, static, rank = found, literals, r
+		}
+	}
+
+	return pattern, static, rank
+}
@@ -0,0 +1,247 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+const pathPolicySpec = `{
+  "swagger": "2.0",
+  "info": {"title": "paths", "version": "1.0"},
+  "basePath": "/api",
+  "consumes": ["application/json"],
+  "produces": ["application/json"],
+  "paths": {
+    "/pets": {
+      "get": {"operationId": "listPets", "responses": {"200": {"description": "ok"}}},
+      "post": {"operationId": "createPet", "responses": {"201": {"description": "created"}}}
+    },
+    "/pets/{id}": {
+      "get": {
+        "operationId": "getPet",
+        "parameters": [{"name": "id", "in": "path", "required": true, "type": "string"}],
+        "responses": {"200": {"description": "ok"}}
+      }
+    },
+    "/files/{name}.{ext}": {
+      "get": {
+        "operationId": "getFile",
+        "parameters": [
+          {"name": "name", "in": "path", "required": true, "type": "string"},
+          {"name": "ext", "in": "path", "required": true, "type": "string"}
+        ],
+        "responses": {"200": {"description": "ok"}}
+      }
+    },
+    "/allow/{serverName}/tokenlist:add": {
+      "post": {
+        "operationId": "addToken",
+        "parameters": [{"name": "serverName", "in": "path", "required": true, "type": "string"}],
+        "responses": {"200": {"description": "ok"}}
+      }
+    }
+  }
+}`
+
+// pathPolicyHandler routes requests with a path policy, and answers them with the operation
+// and the parameters of their route.
+func pathPolicyHandler(t *testing.T, policy PathPolicy, opts ...PathOption) http.Handler {
+	t.Helper()
+
+	ctx := testContext(t, pathPolicySpec).SetPathPolicy(policy, opts...)
+
+	return NewRouter(ctx, http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		route := MatchedRouteFrom(r)
+		values := []string{route.Operation.ID}
+		for _, p := range route.Params {
+			values = append(values, p.Name+"="+p.Value)
+		}
+		_, _ = rw.Write([]byte(strings.Join(values, " ")))
+	}))
+}
+
+func TestPathPolicy_Lenient(t *testing.T) {
+	handler := pathPolicyHandler(t, PathLenient)
+
+	for target, expected := range map[string]string{
+		"/api/pets":              "listPets",
+		"/api/pets/":             "listPets",
+		"/api//pets":             "listPets",
+		"/api/pets/fido/":        "getPet id=fido",
+		"/api/files/report.pdf/": "getFile name=report ext=pdf",
+		"/api/pets/a%2Fb":        "getPet id=a/b",
+		"/api/pets/Fido":         "getPet id=Fido",
+	} {
+		t.Run(target, func(t *testing.T) {
+			rec := serveTest(handler, http.MethodGet, target, nil)
+			assert.EqualT(t, http.StatusOK, rec.Code)
+			assert.EqualT(t, expected, rec.Body.String())
+		})
+	}
+
+	t.Run("literal colon with a trailing slash", func(t *testing.T) {
+		rec := serveTest(handler, http.MethodPost, "/api/allow/myserver/tokenlist:add/", nil)
+		assert.EqualT(t, http.StatusOK, rec.Code)
+		assert.EqualT(t, "addToken serverName=myserver", rec.Body.String())
+	})
+
+	t.Run("case sensitive by default", func(t *testing.T) {
+		rec := serveTest(handler, http.MethodGet, "/API/Pets", nil)
+		assert.EqualT(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestPathPolicy_CaseInsensitive(t *testing.T) {
+	handler := pathPolicyHandler(t, PathLenient, WithCaseInsensitivePaths())
+
+	for _, tc := range []struct {
+		method   string
+		target   string
+		expected string
+	}{
+		{http.MethodGet, "/API/Pets", "listPets"},
+		{http.MethodPost, "/api/PETS/", "createPet"},
+		{http.MethodGet, "/Api/Pets/Fido", "getPet id=Fido"},
+		{http.MethodGet, "/api/FILES/Report.PDF", "getFile name=Report ext=PDF"},
+		{http.MethodPost, "/api/Allow/MyServer/TokenList:Add", "addToken serverName=MyServer"},
+	} {
+		t.Run(tc.method+" "+tc.target, func(t *testing.T) {
+			rec := serveTest(handler, tc.method, tc.target, nil)
+			assert.EqualT(t, http.StatusOK, rec.Code)
+			assert.EqualT(t, tc.expected, rec.Body.String())
+		})
+	}
+
+	t.Run("methods of a path in another case", func(t *testing.T) {
+		rec := serveTest(handler, http.MethodDelete, "/API/PETS", nil)
+		assert.EqualT(t, http.StatusMethodNotAllowed, rec.Code)
+		assert.ElementsMatch(t, []string{"GET", "POST"}, splitAllow(rec.Header().Get("Allow")))
+	})
+}
+
+func TestFoldedRoutes(t *testing.T) {
+	folded := newFoldedRoutes([]RouteDescription{
+		{PathPattern: "/api/{kind}/x"},
+		{PathPattern: "/api/pets/{id}"},
+		{PathPattern: "/api/y/{name}"},
+		{PathPattern: "/api/pets/mine"},
+	})
+
+	for path, expected := range map[string]string{
+		"/API/PETS/MINE": "/api/pets/mine", // the most literal segments win
+		"/Api/Pets/Fido": "/api/pets/Fido",
+		"/api/Y/X":       "/api/Y/x", // the first route wins among routes with as many literal segments
+		"/api/Y/Z":       "/api/y/Z",
+		"/api/pets":      "",
+		"/api//x":        "",
+	} {
+		t.Run(path, func(t *testing.T) {
+			pattern, _, _ := folded.match(strings.Split(path, "/"))
+			if expected == "" {
+				assert.Nil(t, pattern)
+
+				return
+			}
+			require.NotNil(t, pattern)
+
+			router := &defaultRouter{folded: folded}
+			actual, ok := router.foldCase(path)
+			require.TrueT(t, ok)
+			assert.EqualT(t, expected, actual)
+		})
+	}
+}
+
+func TestPathPolicy_EncodedSlashes(t *testing.T) {
+	handler := pathPolicyHandler(t, PathLenient, WithEncodedSlashes())
+
+	for target, expected := range map[string]string{
+		"/api/pets/a%2Fb":      "getPet id=a%2Fb",
+		"/api/pets/a%2fb%20c":  "getPet id=a%2Fb c",
+		"/api/pets/a%20b":      "getPet id=a b",
+		"/api/files/a%2Fb.txt": "getFile name=a%2Fb ext=txt",
+	} {
+		t.Run(target, func(t *testing.T) {
+			rec := serveTest(handler, http.MethodGet, target, nil)
+			assert.EqualT(t, http.StatusOK, rec.Code)
+			assert.EqualT(t, expected, rec.Body.String())
+		})
+	}
+
+	t.Run("literal colon", func(t *testing.T) {
+		rec := serveTest(handler, http.MethodPost, "/api/allow/eu%2Fwest/tokenlist:add", nil)
+		assert.EqualT(t, http.StatusOK, rec.Code)
+		assert.EqualT(t, "addToken serverName=eu%2Fwest", rec.Body.String())
+	})
+}
+
+func TestPathPolicy_Redirect(t *testing.T) {
+	handler := pathPolicyHandler(t, PathRedirect, WithCaseInsensitivePaths())
+
+	for _, tc := range []struct {
+		method   string
+		target   string
+		code     int
+		location string
+	}{
+		{http.MethodGet, "/api/pets/", http.StatusMovedPermanently, "/api/pets"},
+		{http.MethodHead, "/api//pets?limit=10", http.StatusMovedPermanently, "/api/pets?limit=10"},
+		{http.MethodPost, "/API/Pets/", http.StatusPermanentRedirect, "/api/pets"},
+		{http.MethodGet, "/api/Pets/Fido/", http.StatusMovedPermanently, "/api/pets/Fido"},
+		{http.MethodGet, "/api/files/report.pdf/", http.StatusMovedPermanently, "/api/files/report.pdf"},
+		{http.MethodPost, "/api/allow/myserver/TOKENLIST:ADD", http.StatusPermanentRedirect, "/api/allow/myserver/tokenlist:add"},
+	} {
+		t.Run(tc.method+" "+tc.target, func(t *testing.T) {
+			rec := serveTest(handler, tc.method, tc.target, nil)
+			assert.EqualT(t, tc.code, rec.Code)
+			assert.EqualT(t, tc.location, rec.Header().Get("Location"))
+		})
+	}
+
+	t.Run("canonical paths are served", func(t *testing.T) {
+		rec := serveTest(handler, http.MethodGet, "/api/pets/Fido", nil)
+		assert.EqualT(t, http.StatusOK, rec.Code)
+		assert.EqualT(t, "getPet id=Fido", rec.Body.String())
+	})
+
+	t.Run("unknown paths are not redirected", func(t *testing.T) {
+		rec := serveTest(handler, http.MethodGet, "/api/owners/", nil)
+		assert.EqualT(t, http.StatusNotFound, rec.Code)
+		assert.Empty(t, rec.Header().Get("Location"))
+	})
+}
+
+func TestPathPolicy_Strict(t *testing.T) {
+	handler := pathPolicyHandler(t, PathStrict)
+
+	for _, target := range []string{"/api/pets/", "/api//pets", "/api/pets/fido/", "/api/files/report.pdf/"} {
+		t.Run(target, func(t *testing.T) {
+			rec := serveTest(handler, http.MethodGet, target, nil)
+			assert.EqualT(t, http.StatusNotFound, rec.Code)
+		})
+	}
+
+	t.Run("literal colon with a trailing slash", func(t *testing.T) {
+		rec := serveTest(handler, http.MethodPost, "/api/allow/myserver/tokenlist:add/", nil)
+		assert.EqualT(t, http.StatusNotFound, rec.Code)
+	})
+
+	for target, expected := range map[string]string{
+		"/api/pets":             "listPets",
+		"/api/pets/a%2Fb":       "getPet id=a/b",
+		"/api/files/report.pdf": "getFile name=report ext=pdf",
+	} {
+		t.Run(target, func(t *testing.T) {
+			rec := serveTest(handler, http.MethodGet, target, nil)
+			assert.EqualT(t, http.StatusOK, rec.Code)
+			assert.EqualT(t, expected, rec.Body.String())
+		})
+	}
+}
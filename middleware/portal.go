@@ -0,0 +1,27 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"github.com/go-openapi/loads"
+
+	"github.com/go-openapi/runtime/server-middleware/docui"
+)
+
+// PortalSpec describes a loaded spec document to be served by a documentation [docui.Portal].
+//
+// The title and version of the API are those of the info section of the spec.
+func PortalSpec(name string, doc *loads.Document) docui.PortalSpec {
+	spec := docui.PortalSpec{
+		Name:     name,
+		Document: doc.Raw(),
+	}
+
+	if info := doc.Spec().Info; info != nil {
+		spec.Title = info.Title
+		spec.Version = info.Version
+	}
+
+	return spec
+}
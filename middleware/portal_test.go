@@ -0,0 +1,22 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"testing"
+
+	"github.com/go-openapi/runtime/internal/testing/petstore"
+	"github.com/go-openapi/testify/v2/assert"
+)
+
+func TestPortalSpec(t *testing.T) {
+	doc, _ := petstore.NewAPI(t)
+
+	spec := PortalSpec("pets", doc)
+
+	assert.EqualT(t, "pets", spec.Name)
+	assert.EqualT(t, doc.Spec().Info.Title, spec.Title)
+	assert.EqualT(t, doc.Spec().Info.Version, spec.Version)
+	assert.JSONEqT(t, string(doc.Raw()), string(spec.Document))
+}
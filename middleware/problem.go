@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-openapi/errors"
+
+	"github.com/go-openapi/runtime"
+)
+
+const maxHTTPCode = 600
+
+// SetProblemDetails toggles the rendering of all errors as RFC 9457 problem details
+// documents (application/problem+json), using [ServeProblem].
+//
+// This applies to every error responded by this [Context]: routing errors (404, 405),
+// security errors (401, 403), content negotiation errors (406, 415), validation errors (422)
+// and errors returned by operation handlers. When enabled, the ServeError handler
+// provided by the [RoutableAPI] is bypassed.
+//
+// Default: false.
+//
+// Returns the receiver for fluent configuration:
+//
+//	ctx := middleware.NewContext(spec, api, nil).SetProblemDetails(true)
+func (c *Context) SetProblemDetails(enable bool) *Context {
+	c.problemDetails = enable
+
+	return c
+}
+
+// ServeProblem is an error handler that renders errors as RFC 9457 problem details
+// documents.
+//
+// It is a drop-in replacement for [errors.ServeError], and may be used as the
+// ServeError handler of an API. See [NewProblem] for how errors are mapped.
+func ServeProblem(rw http.ResponseWriter, r *http.Request, err error) {
+	problem := NewProblem(r, err)
+
+	var errMethodNotAllowed *errors.MethodNotAllowedError
+	if stderrors.As(err, &errMethodNotAllowed) {
+		rw.Header().Set("Allow", strings.Join(errMethodNotAllowed.Allowed, ","))
+	}
+
+	rw.Header().Set(runtime.HeaderContentType, runtime.ProblemJSONMime)
+	rw.WriteHeader(problem.Status)
+	if r != nil && r.Method == http.MethodHead {
+		return
+	}
+
+	enc := json.NewEncoder(rw)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(problem)
+}
+
+// NewProblem maps an error to an RFC 9457 problem details document.
+//
+// The status is determined like with [errors.ServeError]: composite errors are flattened,
+// and the status is taken from the first error. Validation codes beyond the range of
+// HTTP status codes are reported as 422.
+//
+// Every validation or parsing error is reported in the "errors" extension member,
+// with the name and location of the offending parameter.
+//
+// An error that wraps a [runtime.ProblemError] is rendered as is, with missing members
+// filled in.
+func NewProblem(r *http.Request, err error) *runtime.ProblemError {
+	var instance string
+	if r != nil && r.URL != nil {
+		instance = r.URL.EscapedPath()
+	}
+
+	var problem *runtime.ProblemError
+	if stderrors.As(err, &problem) {
+		cp := *problem
+		if cp.Status == 0 {
+			cp.Status = http.StatusInternalServerError
+		}
+		if cp.Type == "" {
+			cp.Type = runtime.ProblemTypeBlank
+		}
+		if cp.Title == "" && cp.Type == runtime.ProblemTypeBlank {
+			cp.Title = http.StatusText(cp.Status)
+		}
+		if cp.Instance == "" {
+			cp.Instance = instance
+		}
+
+		return &cp
+	}
+
+	errs := flattenErrors(err)
+	if len(errs) == 0 {
+		problem = runtime.NewProblemError(http.StatusInternalServerError, "Unknown error")
+		problem.Instance = instance
+
+		return problem
+	}
+
+	status := problemStatus(errs[0])
+	detail := errs[0].Error()
+
+	var composite *errors.CompositeError
+	if len(errs) > 1 && stderrors.As(err, &composite) {
+		detail = composite.Error()
+		if idx := strings.IndexByte(detail, ':'); idx > 0 {
+			detail = detail[:idx]
+		}
+	}
+
+	problem = runtime.NewProblemError(status, detail)
+	problem.Instance = instance
+
+	for _, e := range errs {
+		if fe, ok := problemFieldError(e); ok {
+			problem.Errors = append(problem.Errors, fe)
+		}
+	}
+
+	return problem
+}
+
+// flattenErrors unfolds (possibly nested) composite errors, like [errors.ServeError] does.
+func flattenErrors(err error) []error {
+	if err == nil {
+		return nil
+	}
+
+	if isNilError(err) {
+		return nil
+	}
+
+	var composite *errors.CompositeError
+	if !stderrors.As(err, &composite) {
+		return []error{err}
+	}
+
+	var flat []error
+	for _, e := range composite.Errors {
+		flat = append(flat, flattenErrors(e)...)
+	}
+
+	return flat
+}
+
+func isNilError(err error) bool {
+	value := reflect.ValueOf(err)
+
+	return value.Kind() == reflect.Ptr && value.IsNil()
+}
+
+func problemStatus(err error) int {
+	var apiErr errors.Error
+	if !stderrors.As(err, &apiErr) || isNilError(apiErr) {
+		return http.StatusInternalServerError
+	}
+
+	code := int(apiErr.Code())
+	if code >= maxHTTPCode {
+		return errors.DefaultHTTPCode
+	}
+
+	return code
+}
+
+func problemFieldError(err error) (runtime.ProblemFieldError, bool) {
+	var validationErr *errors.Validation
+	if stderrors.As(err, &validationErr) {
+		return runtime.ProblemFieldError{
+			Name:   validationErr.Name,
+			In:     validationErr.In,
+			Code:   int(validationErr.Code()),
+			Detail: validationErr.Error(),
+			Value:  validationErr.Value,
+		}, true
+	}
+
+	var parseErr *errors.ParseError
+	if stderrors.As(err, &parseErr) {
+		return runtime.ProblemFieldError{
+			Name:   parseErr.Name,
+			In:     parseErr.In,
+			Code:   int(parseErr.Code()),
+			Detail: parseErr.Error(),
+			Value:  parseErr.Value,
+		}, true
+	}
+
+	return runtime.ProblemFieldError{}, false
+}
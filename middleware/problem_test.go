@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	stdcontext "context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apierrors "github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/internal/testing/petstore"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func decodeProblem(t *testing.T, recorder *httptest.ResponseRecorder) runtime.ProblemError {
+	t.Helper()
+
+	assert.EqualT(t, runtime.ProblemJSONMime, recorder.Header().Get(runtime.HeaderContentType))
+
+	var problem runtime.ProblemError
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+	assert.EqualT(t, recorder.Code, problem.Status)
+	assert.EqualT(t, runtime.ProblemTypeBlank, problem.Type)
+	assert.EqualT(t, http.StatusText(recorder.Code), problem.Title)
+
+	return problem
+}
+
+func TestContextProblemDetails(t *testing.T) {
+	spec, api := petstore.NewAPI(t)
+	handler := NewContext(spec, api, nil).SetProblemDetails(true).APIHandler(nil)
+
+	serve := func(method, path string, body string, headers ...string) *httptest.ResponseRecorder {
+		var request *http.Request
+		if body == "" {
+			request = httptest.NewRequestWithContext(stdcontext.Background(), method, path, nil)
+		} else {
+			request = httptest.NewRequestWithContext(stdcontext.Background(), method, path, strings.NewReader(body))
+		}
+		for i := 0; i+1 < len(headers); i += 2 {
+			request.Header.Set(headers[i], headers[i+1])
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		return recorder
+	}
+
+	t.Run("should render 404 as a problem", func(t *testing.T) {
+		recorder := serve(http.MethodGet, "/api/nowhere", "")
+		require.EqualT(t, http.StatusNotFound, recorder.Code)
+
+		problem := decodeProblem(t, recorder)
+		assert.EqualT(t, "/api/nowhere", problem.Instance)
+		assert.EqualT(t, "path /api/nowhere was not found", problem.Detail)
+		assert.Empty(t, problem.Errors)
+	})
+
+	t.Run("should render 405 as a problem", func(t *testing.T) {
+		recorder := serve(http.MethodPut, "/api/pets", "")
+		require.EqualT(t, http.StatusMethodNotAllowed, recorder.Code)
+
+		decodeProblem(t, recorder)
+		assert.Contains(t, recorder.Header().Get("Allow"), http.MethodGet)
+	})
+
+	t.Run("should render 401 as a problem", func(t *testing.T) {
+		recorder := serve(http.MethodGet, "/api/pets", "")
+		require.EqualT(t, http.StatusUnauthorized, recorder.Code)
+
+		decodeProblem(t, recorder)
+		assert.NotEmpty(t, recorder.Header().Get("WWW-Authenticate"))
+	})
+
+	t.Run("should render 415 as a problem", func(t *testing.T) {
+		recorder := serve(http.MethodPost, "/api/pets", "name: fido",
+			runtime.HeaderContentType, "text/plain",
+			runtime.HeaderAuthorization, "Basic YWRtaW46YWRtaW4=",
+		)
+		require.EqualT(t, http.StatusUnsupportedMediaType, recorder.Code)
+
+		problem := decodeProblem(t, recorder)
+		require.Len(t, problem.Errors, 1)
+		assert.EqualT(t, runtime.HeaderContentType, problem.Errors[0].Name)
+		assert.EqualT(t, "header", problem.Errors[0].In)
+	})
+
+	t.Run("should render validation errors as a problem", func(t *testing.T) {
+		recorder := serve(http.MethodGet, "/api/pets/abc", "")
+		require.EqualT(t, http.StatusUnprocessableEntity, recorder.Code)
+
+		problem := decodeProblem(t, recorder)
+		require.Len(t, problem.Errors, 1)
+		assert.EqualT(t, "id", problem.Errors[0].Name)
+		assert.EqualT(t, "path", problem.Errors[0].In)
+		assert.EqualT(t, int(apierrors.InvalidTypeCode), problem.Errors[0].Code)
+		assert.Equal(t, "abc", problem.Errors[0].Value)
+	})
+
+	t.Run("should not render a body for HEAD requests", func(t *testing.T) {
+		recorder := serve(http.MethodHead, "/api/nowhere", "")
+		require.EqualT(t, http.StatusNotFound, recorder.Code)
+		assert.Empty(t, recorder.Body.Bytes())
+	})
+}
+
+func TestNewProblem(t *testing.T) {
+	request := httptest.NewRequestWithContext(stdcontext.Background(), http.MethodGet, "/api/pets?limit=x", nil)
+
+	t.Run("should map a nil error", func(t *testing.T) {
+		problem := NewProblem(request, nil)
+		assert.EqualT(t, http.StatusInternalServerError, problem.Status)
+		assert.EqualT(t, "/api/pets", problem.Instance)
+	})
+
+	t.Run("should map a nil api error", func(t *testing.T) {
+		var err *apierrors.Validation
+		problem := NewProblem(request, err)
+		assert.EqualT(t, http.StatusInternalServerError, problem.Status)
+	})
+
+	t.Run("should map a plain error", func(t *testing.T) {
+		problem := NewProblem(nil, errors.New("boom"))
+		assert.EqualT(t, http.StatusInternalServerError, problem.Status)
+		assert.EqualT(t, "boom", problem.Detail)
+		assert.Empty(t, problem.Instance)
+	})
+
+	t.Run("should flatten nested composite errors", func(t *testing.T) {
+		err := apierrors.CompositeValidationError(
+			apierrors.Required("name", "body", nil),
+			apierrors.CompositeValidationError(
+				apierrors.InvalidType("limit", "query", "integer", "x"),
+				apierrors.NewParseError("since", "query", "yesterday", errors.New("bad date")),
+			),
+		)
+
+		problem := NewProblem(request, err)
+		assert.EqualT(t, http.StatusUnprocessableEntity, problem.Status)
+		assert.EqualT(t, "validation failure list", problem.Detail)
+		require.Len(t, problem.Errors, 3)
+		assert.EqualT(t, "name", problem.Errors[0].Name)
+		assert.EqualT(t, "limit", problem.Errors[1].Name)
+		assert.EqualT(t, "since", problem.Errors[2].Name)
+		assert.EqualT(t, http.StatusBadRequest, problem.Errors[2].Code)
+	})
+
+	t.Run("should preserve a problem returned by a handler", func(t *testing.T) {
+		custom := &runtime.ProblemError{
+			Type:   "https://example.com/probs/out-of-credit",
+			Title:  "You do not have enough credit.",
+			Status: http.StatusForbidden,
+		}
+
+		problem := NewProblem(request, fmt.Errorf("wrapped: %w", custom))
+		assert.EqualT(t, custom.Type, problem.Type)
+		assert.EqualT(t, custom.Title, problem.Title)
+		assert.EqualT(t, "/api/pets", problem.Instance)
+		assert.Empty(t, custom.Instance)
+
+		problem = NewProblem(request, &runtime.ProblemError{})
+		assert.EqualT(t, http.StatusInternalServerError, problem.Status)
+		assert.EqualT(t, runtime.ProblemTypeBlank, problem.Type)
+		assert.EqualT(t, http.StatusText(http.StatusInternalServerError), problem.Title)
+	})
+}
@@ -0,0 +1,308 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+// Package radix provides a URL router based on a compressed radix tree.
+//
+// It routes the same records as the [denco] router, i.e. keys like "/path/to/:id" and "/path/to/*wildcard",
+// with constraints on their parameters (see [denco.Record]), but is not limited in size.
+// The tree looks paths up without allocating when given a buffer for the parameters (see [Tree.LookupParams]),
+// but the routers using it may still allocate their results.
+//
+// Literal segments take precedence over parameters, which take precedence over wildcards:
+// the tree backtracks to the next candidate when a branch doesn't match.
+package radix
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-openapi/runtime/middleware/denco"
+)
+
+// Tree is a URL router based on a compressed radix tree.
+//
+// A Tree is safe for concurrent lookups once built.
+type Tree struct {
+	root   node
+	static map[string]any
+
+	// maxParams is the largest number of parameters of a record.
+	maxParams int
+
+	// records counts the records added to the tree, to order leaves.
+	records int
+
+	// constrained is true when some records have constraints on their parameters.
+	constrained bool
+}
+
+// New returns an empty Tree.
+func New() *Tree {
+	return &Tree{
+		static: make(map[string]any),
+	}
+}
+
+// Build adds records to the tree.
+func (t *Tree) Build(records []denco.Record) error {
+	for _, r := range records {
+		if !strings.ContainsAny(r.Key, string(denco.ParamCharacter)+string(denco.WildcardCharacter)) {
+			t.static[r.Key] = r.Value
+			continue
+		}
+
+		if err := t.insert(r); err != nil {
+			return err
+		}
+	}
+
+	t.root.sortLeaves()
+
+	return nil
+}
+
+// MaxParams returns the largest number of parameters of the records of the tree,
+// to size the buffers given to [Tree.LookupParams].
+func (t *Tree) MaxParams() int {
+	return t.maxParams
+}
+
+// Lookup returns the value and the path parameters associated to a path.
+func (t *Tree) Lookup(path string) (data any, params denco.Params, found bool) {
+	return t.LookupParams(path, make(denco.Params, 0, t.maxParams))
+}
+
+// LookupParams is like [Tree.Lookup], but appends the path parameters to params,
+// so that lookups don't allocate when params has enough capacity (see [Tree.MaxParams]).
+//
+// When records have constraints, the path is matched by the first record whose parameters satisfy
+// them, backtracking to the next candidate otherwise. When no candidate satisfies its constraints,
+// the path is matched regardless of the constraints, like the [denco.Router].
+func (t *Tree) LookupParams(path string, params denco.Params) (data any, _ denco.Params, found bool) {
+	if data, found = t.static[path]; found {
+		return data, params, true
+	}
+
+	start := len(params)
+	lf, params := t.root.lookup(path, params, t.constrained)
+	if lf == nil && t.constrained {
+		lf, params = t.root.lookup(path, params[:start], false)
+	}
+	if lf == nil {
+		return nil, params[:start], false
+	}
+
+	for i, name := range lf.paramNames {
+		params[start+i].Name = name
+	}
+
+	return lf.data, params, true
+}
+
+func (t *Tree) insert(r denco.Record) error {
+	t.records++
+	lf := &leaf{data: r.Value, order: t.records}
+	key := r.Key
+	n := &t.root
+	for key != "" {
+		switch key[0] {
+		case denco.ParamCharacter:
+			end := denco.NextSeparator(key, 1)
+			lf.paramNames = append(lf.paramNames, key[1:end])
+			if n.param == nil {
+				n.param = &node{}
+			}
+			n, key = n.param, key[end:]
+		case denco.WildcardCharacter:
+			lf.paramNames = append(lf.paramNames, key[1:])
+			if n.wildcard == nil {
+				n.wildcard = &node{}
+			}
+			n, key = n.wildcard, ""
+		default:
+			end := strings.IndexAny(key, string(denco.ParamCharacter)+string(denco.WildcardCharacter))
+			if end < 0 {
+				end = len(key)
+			}
+			var consumed int
+			n, consumed = n.staticChild(key[:end])
+			key = key[consumed:]
+		}
+	}
+
+	seen := make(map[string]bool, len(lf.paramNames))
+	for _, name := range lf.paramNames {
+		if seen[name] {
+			return fmt.Errorf("radix: path parameter `%v' is duplicated in the key `%v'", name, r.Key)
+		}
+		seen[name] = true
+	}
+
+	if constraints := r.Constraints; len(constraints) > 0 {
+		lf.constraints = make([]denco.Constraint, len(lf.paramNames))
+		for i, name := range lf.paramNames {
+			lf.constraints[i] = constraints[name]
+		}
+		t.constrained = true
+	}
+
+	t.maxParams = max(t.maxParams, len(lf.paramNames))
+	n.leaves = append(n.leaves, lf)
+
+	return nil
+}
+
+// node is a node of the tree: its static children are indexed by the first byte of their prefix.
+type node struct {
+	prefix   string
+	indices  []byte
+	children []*node
+	param    *node
+	wildcard *node
+	leaves   []*leaf
+}
+
+// leaf is a record ending at a node.
+type leaf struct {
+	data        any
+	paramNames  []string
+	constraints []denco.Constraint
+	order       int
+}
+
+// staticChild returns the child of n matching the longest prefix of static, splitting or adding children
+// as needed, and the length of the matched prefix.
+func (n *node) staticChild(static string) (*node, int) {
+	i := slices.Index(n.indices, static[0])
+	if i < 0 {
+		child := &node{prefix: static}
+		n.indices = append(n.indices, static[0])
+		n.children = append(n.children, child)
+
+		return child, len(static)
+	}
+
+	child := n.children[i]
+	common := commonPrefix(child.prefix, static)
+	if common < len(child.prefix) {
+		// split the child at the end of the common prefix
+		split := *child
+		split.prefix = child.prefix[common:]
+		*child = node{
+			prefix:   child.prefix[:common],
+			indices:  []byte{split.prefix[0]},
+			children: []*node{&split},
+		}
+	}
+
+	return child, common
+}
+
+func commonPrefix(a, b string) int {
+	n := min(len(a), len(b))
+	for i := range n {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+
+	return n
+}
+
+// lookup matches path below n, whose prefix is already matched: literals first, then parameters, then wildcards.
+func (n *node) lookup(path string, params denco.Params, strict bool) (*leaf, denco.Params) {
+	if path == "" {
+		return n.match(params, strict), params
+	}
+
+	if i := slices.Index(n.indices, path[0]); i >= 0 {
+		child := n.children[i]
+		if strings.HasPrefix(path, child.prefix) {
+			if lf, matched := child.lookup(path[len(child.prefix):], params, strict); lf != nil {
+				return lf, matched
+			}
+		}
+	}
+
+	if n.param != nil {
+		end := denco.NextSeparator(path, 0)
+		if end > 0 {
+			if lf, matched := n.param.lookup(path[end:], append(params, denco.Param{Value: path[:end]}), strict); lf != nil {
+				return lf, matched
+			}
+		}
+	}
+
+	if n.wildcard != nil {
+		matched := append(params, denco.Param{Value: path})
+		if lf := n.wildcard.match(matched, strict); lf != nil {
+			return lf, matched
+		}
+	}
+
+	return nil, params
+}
+
+// match returns the first leaf of n whose constraints are satisfied by params.
+//
+// Without strict, the constraints are ignored.
+func (n *node) match(params denco.Params, strict bool) *leaf {
+	if len(n.leaves) == 0 {
+		return nil
+	}
+	if !strict {
+		return n.leaves[0]
+	}
+
+	for _, lf := range n.leaves {
+		if lf.accepts(params[len(params)-len(lf.paramNames):]) {
+			return lf
+		}
+	}
+
+	return nil
+}
+
+func (lf *leaf) accepts(params denco.Params) bool {
+	for i, constraint := range lf.constraints {
+		if constraint != nil && !constraint(params[i].Value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sortLeaves orders the leaves of each node: the most constrained first, and the last record added first
+// among leaves with as many constraints, like the [denco.Router].
+func (n *node) sortLeaves() {
+	slices.SortStableFunc(n.leaves, func(a, b *leaf) int {
+		if byConstraints := countConstraints(b) - countConstraints(a); byConstraints != 0 {
+			return byConstraints
+		}
+
+		return b.order - a.order
+	})
+
+	for _, child := range n.children {
+		child.sortLeaves()
+	}
+	if n.param != nil {
+		n.param.sortLeaves()
+	}
+	if n.wildcard != nil {
+		n.wildcard.sortLeaves()
+	}
+}
+
+func countConstraints(lf *leaf) int {
+	count := 0
+	for _, constraint := range lf.constraints {
+		if constraint != nil {
+			count++
+		}
+	}
+
+	return count
+}
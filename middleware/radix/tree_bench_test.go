@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package radix_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-openapi/runtime/middleware/denco"
+	"github.com/go-openapi/runtime/middleware/radix"
+)
+
+func BenchmarkLookupStatic1000(b *testing.B) {
+	benchmarkLookup(b, makeBenchRecords(1000), "/api/v1/resource500")
+}
+
+func BenchmarkLookupParam1000(b *testing.B) {
+	benchmarkLookup(b, makeBenchRecords(1000), "/api/v1/resource500/42")
+}
+
+func BenchmarkLookup2Params1000(b *testing.B) {
+	benchmarkLookup(b, makeBenchRecords(1000), "/api/v1/resource500/42/children/7")
+}
+
+func BenchmarkLookup2Params10000(b *testing.B) {
+	benchmarkLookup(b, makeBenchRecords(10000), "/api/v1/resource5000/42/children/7")
+}
+
+func BenchmarkBuild1000(b *testing.B) {
+	records := makeBenchRecords(1000)
+
+	b.Run("denco", func(b *testing.B) {
+		for range b.N {
+			if err := denco.New().Build(records); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("radix", func(b *testing.B) {
+		for range b.N {
+			if err := radix.New().Build(records); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func benchmarkLookup(b *testing.B, records []denco.Record, path string) {
+	b.Run("denco", func(b *testing.B) {
+		router := denco.New()
+		if err := router.Build(records); err != nil {
+			b.Fatal(err)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for range b.N {
+			if _, _, found := router.Lookup(path); !found {
+				b.Fail()
+			}
+		}
+	})
+
+	b.Run("radix", func(b *testing.B) {
+		tree := radix.New()
+		if err := tree.Build(records); err != nil {
+			b.Fatal(err)
+		}
+		params := make(denco.Params, 0, tree.MaxParams())
+		b.ReportAllocs()
+		b.ResetTimer()
+		for range b.N {
+			if _, _, found := tree.LookupParams(path, params[:0]); !found {
+				b.Fail()
+			}
+		}
+	})
+}
+
+func makeBenchRecords(n int) []denco.Record {
+	records := make([]denco.Record, 0, 3*n)
+	for i := range n {
+		records = append(records,
+			denco.NewRecord(fmt.Sprintf("/api/v1/resource%d", i), i),
+			denco.NewRecord(fmt.Sprintf("/api/v1/resource%d/:id", i), i),
+			denco.NewRecord(fmt.Sprintf("/api/v1/resource%d/:id/children/:child", i), i),
+		)
+	}
+
+	return records
+}
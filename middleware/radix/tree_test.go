@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package radix_test
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/go-openapi/runtime/middleware/denco"
+	"github.com/go-openapi/runtime/middleware/radix"
+)
+
+type testcase struct {
+	path   string
+	value  any
+	params denco.Params
+	found  bool
+}
+
+func runLookupTest(t *testing.T, records []denco.Record, testcases []testcase) {
+	t.Helper()
+
+	tree := radix.New()
+	require.NoError(t, tree.Build(records))
+
+	for _, tc := range testcases {
+		data, params, found := tree.Lookup(tc.path)
+		assert.EqualT(t, tc.found, found, tc.path)
+		assert.Equal(t, tc.value, data, tc.path)
+		if len(tc.params) == 0 {
+			assert.Empty(t, params, tc.path)
+		} else {
+			assert.Equal(t, tc.params, params, tc.path)
+		}
+	}
+}
+
+func TestTree_Lookup(t *testing.T) {
+	runLookupTest(t, []denco.Record{
+		{Key: "/", Value: "root"},
+		{Key: "/path/to/route", Value: "static"},
+		{Key: "/path/to/:param", Value: "param"},
+		{Key: "/path/to/wildcard/*routepath", Value: "wildcard"},
+		{Key: "/path/to/:param1/:param2", Value: "params"},
+		{Key: "/path/to/:param1/sep/:param2", Value: "separated"},
+		{Key: "/:year/:month/:day", Value: "date"},
+		{Key: "/user/:id", Value: "user"},
+		{Key: "/a/to/b/:param/*routepath", Value: "param-wildcard"},
+		{Key: "/path/with/key=:value", Value: "restconf"},
+	}, []testcase{
+		{"/", "root", nil, true},
+		{"/path/to/route", "static", nil, true},
+		{"/path/to/hoge", "param", denco.Params{{Name: "param", Value: "hoge"}}, true},
+		{"/path/to/wildcard/some/params", "wildcard", denco.Params{{Name: "routepath", Value: "some/params"}}, true},
+		{"/path/to/o1/o2", "params", denco.Params{{Name: "param1", Value: "o1"}, {Name: "param2", Value: "o2"}}, true},
+		{"/path/to/p1/sep/p2", "separated", denco.Params{{Name: "param1", Value: "p1"}, {Name: "param2", Value: "p2"}}, true},
+		{"/2014/01/06", "date", denco.Params{{Name: "year", Value: "2014"}, {Name: "month", Value: "01"}, {Name: "day", Value: "06"}}, true},
+		{"/user/777", "user", denco.Params{{Name: "id", Value: "777"}}, true},
+		{"/a/to/b/p1/some/wildcard/params", "param-wildcard", denco.Params{{Name: "param", Value: "p1"}, {Name: "routepath", Value: "some/wildcard/params"}}, true},
+		{"/path/with/key=value", "restconf", denco.Params{{Name: "value", Value: "value"}}, true},
+		{"/missing", nil, nil, false},
+		{"/user/", nil, nil, false},
+	})
+
+	t.Run("should prefer literals over parameters over wildcards", func(t *testing.T) {
+		runLookupTest(t, []denco.Record{
+			{Key: "/items/mine/tags", Value: "literal"},
+			{Key: "/items/:id/tags", Value: "param"},
+			{Key: "/items/*rest", Value: "wildcard"},
+		}, []testcase{
+			{"/items/mine/tags", "literal", nil, true},
+			{"/items/42/tags", "param", denco.Params{{Name: "id", Value: "42"}}, true},
+			// backtracking from the literal, then from the parameter
+			{"/items/mine/labels", "wildcard", denco.Params{{Name: "rest", Value: "mine/labels"}}, true},
+		})
+	})
+
+	t.Run("should backtrack on constraints", func(t *testing.T) {
+		isNumber := func(value string) bool {
+			_, err := strconv.Atoi(value)
+			return err == nil
+		}
+		isName := regexp.MustCompile(`^[a-z]+$`).MatchString
+
+		runLookupTest(t, []denco.Record{
+			denco.NewConstrainedRecord("/items/:id", "id", map[string]denco.Constraint{"id": isNumber}),
+			denco.NewConstrainedRecord("/items/:name", "name", map[string]denco.Constraint{"name": isName}),
+			{Key: "/items/:other", Value: "other"},
+		}, []testcase{
+			{"/items/42", "id", denco.Params{{Name: "id", Value: "42"}}, true},
+			{"/items/bolts", "name", denco.Params{{Name: "name", Value: "bolts"}}, true},
+			{"/items/Bolts", "other", denco.Params{{Name: "other", Value: "Bolts"}}, true},
+		})
+	})
+
+	t.Run("should reject duplicated parameters", func(t *testing.T) {
+		require.Error(t, radix.New().Build([]denco.Record{{Key: "/:id/:id", Value: "dup"}}))
+	})
+}
+
+func TestTree_LookupParams(t *testing.T) {
+	tree := radix.New()
+	require.NoError(t, tree.Build([]denco.Record{
+		{Key: "/users/:user/repos/:repo", Value: "repo"},
+	}))
+	require.EqualT(t, 2, tree.MaxParams())
+
+	buf := make(denco.Params, 0, tree.MaxParams())
+	allocs := testing.AllocsPerRun(100, func() {
+		data, params, found := tree.LookupParams("/users/alice/repos/runtime", buf[:0])
+		if !found || data != "repo" || params.Get("repo") != "runtime" {
+			t.Fail()
+		}
+	})
+	assert.EqualT(t, 0.0, allocs)
+}
+
+func TestTree_MatchesDenco(t *testing.T) {
+	records := make([]denco.Record, 0, 3000)
+	for i := range 1000 {
+		records = append(records,
+			denco.NewRecord(fmt.Sprintf("/api/v1/resource%d", i), fmt.Sprintf("list%d", i)),
+			denco.NewRecord(fmt.Sprintf("/api/v1/resource%d/:id", i), fmt.Sprintf("get%d", i)),
+			denco.NewRecord(fmt.Sprintf("/api/v1/resource%d/:id/children/:child", i), fmt.Sprintf("child%d", i)),
+		)
+	}
+
+	tree := radix.New()
+	require.NoError(t, tree.Build(records))
+	router := denco.New()
+	require.NoError(t, router.Build(records))
+
+	for _, path := range []string{
+		"/api/v1/resource0",
+		"/api/v1/resource999/42",
+		"/api/v1/resource500/42/children/7",
+		"/api/v1/resource500/42/children",
+		"/api/v1/resource1000/42",
+		"/api/v2/resource1",
+	} {
+		expectedData, expectedParams, expectedFound := router.Lookup(path)
+		data, params, found := tree.Lookup(path)
+		assert.EqualT(t, expectedFound, found, path)
+		assert.Equal(t, expectedData, data, path)
+		assert.ElementsMatch(t, expectedParams, params, path)
+	}
+}
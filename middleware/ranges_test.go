@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"bytes"
+	stdcontext "context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/internal/testing/petstore"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestRangeContent(t *testing.T) {
+	const content = "abcdefghijklmnopqrstuvwxyz"
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	spec, api := petstore.NewAPI(t)
+	ctx := NewContext(spec, api, nil)
+	ctx.router = DefaultRouter(spec, ctx.api)
+
+	serve := func(t *testing.T, headers ...string) *httptest.ResponseRecorder {
+		t.Helper()
+
+		request := httptest.NewRequestWithContext(stdcontext.Background(), http.MethodGet, "/api/pets/1", nil)
+		request.Header.Set(runtime.HeaderAccept, runtime.JSONMime)
+		for i := 0; i+1 < len(headers); i += 2 {
+			request.Header.Set(headers[i], headers[i+1])
+		}
+
+		route, rCtx, ok := ctx.RouteInfo(request)
+		require.TrueT(t, ok)
+		recorder := httptest.NewRecorder()
+		ctx.Respond(recorder, rCtx, route.Produces, route, RangeContent(strings.NewReader(content), `"v1"`, modified))
+
+		return recorder
+	}
+
+	t.Run("should serve the full content", func(t *testing.T) {
+		recorder := serve(t)
+
+		assert.EqualT(t, http.StatusOK, recorder.Code)
+		assert.EqualT(t, "bytes", recorder.Header().Get(headerAcceptRanges))
+		assert.EqualT(t, `"v1"`, recorder.Header().Get(headerETag))
+		assert.EqualT(t, runtime.JSONMime, recorder.Header().Get(runtime.HeaderContentType))
+		assert.EqualT(t, content, recorder.Body.String())
+	})
+
+	t.Run("should serve a single range", func(t *testing.T) {
+		recorder := serve(t, "Range", "bytes=2-5")
+
+		assert.EqualT(t, http.StatusPartialContent, recorder.Code)
+		assert.EqualT(t, "bytes 2-5/26", recorder.Header().Get("Content-Range"))
+		assert.EqualT(t, "cdef", recorder.Body.String())
+	})
+
+	t.Run("should serve a suffix range", func(t *testing.T) {
+		recorder := serve(t, "Range", "bytes=-3")
+
+		assert.EqualT(t, http.StatusPartialContent, recorder.Code)
+		assert.EqualT(t, "xyz", recorder.Body.String())
+	})
+
+	t.Run("should serve multiple ranges as multipart/byteranges", func(t *testing.T) {
+		recorder := serve(t, "Range", "bytes=0-1,24-")
+
+		assert.EqualT(t, http.StatusPartialContent, recorder.Code)
+		mediaType, params, err := mime.ParseMediaType(recorder.Header().Get(runtime.HeaderContentType))
+		require.NoError(t, err)
+		assert.EqualT(t, "multipart/byteranges", mediaType)
+
+		reader := multipart.NewReader(recorder.Body, params["boundary"])
+		var parts []string
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+
+			assert.EqualT(t, runtime.JSONMime, part.Header.Get(runtime.HeaderContentType))
+			b, err := io.ReadAll(part)
+			require.NoError(t, err)
+			parts = append(parts, part.Header.Get("Content-Range")+":"+string(b))
+		}
+		assert.Equal(t, []string{"bytes 0-1/26:ab", "bytes 24-25/26:yz"}, parts)
+	})
+
+	t.Run("should reply 416 to an unsatisfiable range", func(t *testing.T) {
+		recorder := serve(t, "Range", "bytes=30-40")
+
+		assert.EqualT(t, http.StatusRequestedRangeNotSatisfiable, recorder.Code)
+		assert.EqualT(t, "bytes */26", recorder.Header().Get("Content-Range"))
+	})
+
+	t.Run("should honor If-Range", func(t *testing.T) {
+		recorder := serve(t, "Range", "bytes=2-5", "If-Range", `"v1"`)
+		assert.EqualT(t, http.StatusPartialContent, recorder.Code)
+
+		recorder = serve(t, "Range", "bytes=2-5", "If-Range", `"v0"`)
+		assert.EqualT(t, http.StatusOK, recorder.Code)
+		assert.EqualT(t, content, recorder.Body.String())
+	})
+
+	t.Run("should honor If-None-Match", func(t *testing.T) {
+		recorder := serve(t, headerIfNoneMatch, `"v1"`)
+
+		assert.EqualT(t, http.StatusNotModified, recorder.Code)
+		assert.Empty(t, recorder.Body.Bytes())
+	})
+
+	t.Run("should write the full content outside of a Context", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		RangeContent(bytes.NewReader([]byte(content)), "", time.Time{}).WriteResponse(recorder, runtime.JSONProducer())
+
+		assert.EqualT(t, http.StatusOK, recorder.Code)
+		assert.EqualT(t, "bytes", recorder.Header().Get(headerAcceptRanges))
+		assert.EqualT(t, "26", recorder.Header().Get("Content-Length"))
+		assert.Empty(t, recorder.Header().Get(headerETag))
+		assert.EqualT(t, content, recorder.Body.String())
+	})
+}
@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-openapi/loads"
+
+	"github.com/go-openapi/runtime/middleware/untyped"
+)
+
+// ErrNoSpec is returned when reloading an API without a spec.
+var ErrNoSpec = errors.New("an API requires a spec")
+
+// ReloadOption configures a [Reloadable] API.
+type ReloadOption func(*reloadOpts)
+
+type reloadOpts struct {
+	setup   func(*Context)
+	handler func(*Context) http.Handler
+}
+
+// WithReloadSetup sets a function which configures the [Context] of each version of the API,
+// before its routes are built, e.g. to set router options or an audit sink.
+func WithReloadSetup(fn func(*Context)) ReloadOption {
+	return func(o *reloadOpts) {
+		o.setup = fn
+	}
+}
+
+// WithReloadHandler sets how the handler of each version of the API is built from its [Context]
+// (default: [Context.APIHandler], without builder).
+func WithReloadHandler(fn func(*Context) http.Handler) ReloadOption {
+	return func(o *reloadOpts) {
+		if fn != nil {
+			o.handler = fn
+		}
+	}
+}
+
+// Reloadable serves an API whose spec and handlers may be replaced at runtime, without restarting the server.
+//
+// Each reload builds a new [Context] with its route table, which is validated before being activated.
+// The swap is atomic: requests in flight finish with the version of the API they started with,
+// and new requests are served by the new version. When a reload fails, the current version is kept.
+//
+// Until a first version is loaded, requests are answered with 503 Service Unavailable.
+type Reloadable struct {
+	opts    reloadOpts
+	mu      sync.Mutex // serializes reloads
+	current atomic.Pointer[reloadedAPI]
+}
+
+type reloadedAPI struct {
+	context *Context
+	handler http.Handler
+}
+
+// NewReloadable creates an API which is loaded with [Reloadable.Reload] or [Reloadable.ReloadRoutable].
+func NewReloadable(opts ...ReloadOption) *Reloadable {
+	r := &Reloadable{
+		opts: reloadOpts{
+			handler: func(c *Context) http.Handler { return c.APIHandler(nil) },
+		},
+	}
+	for _, apply := range opts {
+		apply(&r.opts)
+	}
+
+	return r
+}
+
+// Reload activates a new version of an untyped API.
+//
+// The registrations of the API are validated against its spec with [untyped.API.Validate].
+func (r *Reloadable) Reload(spec *loads.Document, api *untyped.API) error {
+	if spec == nil || api == nil {
+		return ErrNoSpec
+	}
+
+	if err := api.Validate(); err != nil {
+		return err
+	}
+
+	return r.activate(NewContext(spec, api, nil))
+}
+
+// ReloadRoutable activates a new version of a routable API, e.g. a generated API.
+//
+// When the API has a Validate() error method, as generated APIs do, the API is validated first.
+func (r *Reloadable) ReloadRoutable(spec *loads.Document, api RoutableAPI) error {
+	if spec == nil || api == nil {
+		return ErrNoSpec
+	}
+
+	if validator, ok := api.(interface{ Validate() error }); ok {
+		if err := validator.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return r.activate(NewRoutableContext(spec, api, nil))
+}
+
+// Context returns the [Context] of the current version of the API, or nil when no API is loaded.
+func (r *Reloadable) Context() *Context {
+	if current := r.current.Load(); current != nil {
+		return current.context
+	}
+
+	return nil
+}
+
+func (r *Reloadable) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	current := r.current.Load()
+	if current == nil {
+		http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+
+		return
+	}
+
+	current.handler.ServeHTTP(rw, req)
+}
+
+// activate builds the routes of a new version of the API, and swaps it in.
+func (r *Reloadable) activate(ctx *Context) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("could not build the routes of the API: %v", recovered)
+		}
+	}()
+
+	if r.opts.setup != nil {
+		r.opts.setup(ctx)
+	}
+
+	// the route table is built along with the handler
+	handler := r.opts.handler(ctx)
+	r.current.Store(&reloadedAPI{context: ctx, handler: handler})
+
+	return nil
+}
@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/middleware/untyped"
+)
+
+func reloadTestAPI(t *testing.T, path string, handler runtime.OperationHandler) (*loads.Document, *untyped.API) {
+	t.Helper()
+
+	doc := testSpec(t, `{
+  "swagger": "2.0",
+  "info": {"title": "reload", "version": "1.0"},
+  "basePath": "/api",
+  "consumes": ["application/json"],
+  "produces": ["application/json"],
+  "paths": {
+    "`+path+`": {
+      "get": {"operationId": "get", "responses": {"200": {"description": "ok"}}}
+    }
+  }
+}`)
+
+	api := untyped.NewAPI(doc)
+	if handler != nil {
+		api.RegisterOperation("get", path, handler)
+	}
+
+	return doc, api
+}
+
+func TestReloadable(t *testing.T) {
+	version := func(v string) runtime.OperationHandler {
+		return runtime.OperationHandlerFunc(func(any) (any, error) {
+			return v, nil
+		})
+	}
+
+	t.Run("should be unavailable until loaded", func(t *testing.T) {
+		reloadable := NewReloadable()
+		assert.Nil(t, reloadable.Context())
+		assert.EqualT(t, http.StatusServiceUnavailable, serveTest(reloadable, http.MethodGet, "/api/a", nil).Code)
+	})
+
+	t.Run("should swap the routes", func(t *testing.T) {
+		var setups int
+		reloadable := NewReloadable(WithReloadSetup(func(*Context) { setups++ }))
+		require.NoError(t, reloadable.Reload(reloadTestAPI(t, "/a", version("v1"))))
+
+		rec := serveTest(reloadable, http.MethodGet, "/api/a", nil)
+		require.EqualT(t, http.StatusOK, rec.Code)
+		assert.JSONEqT(t, `"v1"`, rec.Body.String())
+
+		first := reloadable.Context()
+		require.NoError(t, reloadable.Reload(reloadTestAPI(t, "/b", version("v2"))))
+		assert.NotEqual(t, first, reloadable.Context())
+		assert.EqualT(t, 2, setups)
+
+		assert.EqualT(t, http.StatusNotFound, serveTest(reloadable, http.MethodGet, "/api/a", nil).Code)
+		rec = serveTest(reloadable, http.MethodGet, "/api/b", nil)
+		require.EqualT(t, http.StatusOK, rec.Code)
+		assert.JSONEqT(t, `"v2"`, rec.Body.String())
+	})
+
+	t.Run("should keep the current routes when the API is invalid", func(t *testing.T) {
+		reloadable := NewReloadable()
+		require.NoError(t, reloadable.Reload(reloadTestAPI(t, "/a", version("v1"))))
+
+		// no handler for the operation of the new spec
+		require.Error(t, reloadable.Reload(reloadTestAPI(t, "/b", nil)))
+		require.ErrorIs(t, reloadable.Reload(nil, nil), ErrNoSpec)
+
+		assert.EqualT(t, http.StatusOK, serveTest(reloadable, http.MethodGet, "/api/a", nil).Code)
+		assert.EqualT(t, http.StatusNotFound, serveTest(reloadable, http.MethodGet, "/api/b", nil).Code)
+	})
+
+	t.Run("should finish requests in flight with their version", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		slow := runtime.OperationHandlerFunc(func(any) (any, error) {
+			close(started)
+			<-release
+
+			return "v1", nil
+		})
+
+		reloadable := NewReloadable()
+		require.NoError(t, reloadable.Reload(reloadTestAPI(t, "/a", slow)))
+
+		inFlight := make(chan *httptest.ResponseRecorder)
+		go func() {
+			inFlight <- serveTest(reloadable, http.MethodGet, "/api/a", nil)
+		}()
+		<-started
+
+		require.NoError(t, reloadable.Reload(reloadTestAPI(t, "/a", version("v2"))))
+		rec := serveTest(reloadable, http.MethodGet, "/api/a", nil)
+		assert.JSONEqT(t, `"v2"`, rec.Body.String())
+
+		close(release)
+		rec = <-inFlight
+		require.EqualT(t, http.StatusOK, rec.Code)
+		assert.JSONEqT(t, `"v1"`, rec.Body.String())
+	})
+
+	t.Run("should reload routable APIs", func(t *testing.T) {
+		reloadable := NewReloadable(WithReloadHandler(func(c *Context) http.Handler {
+			return c.RoutesHandler(nil)
+		}))
+		doc, api := reloadTestAPI(t, "/a", version("v1"))
+		ctx := NewContext(doc, api, nil)
+		require.NoError(t, reloadable.ReloadRoutable(doc, ctx.api))
+
+		assert.EqualT(t, http.StatusOK, serveTest(reloadable, http.MethodGet, "/api/a", nil).Code)
+		// no UI without the API handler
+		assert.EqualT(t, http.StatusNotFound, serveTest(reloadable, http.MethodGet, "/api/docs", nil).Code)
+	})
+}
@@ -21,6 +21,9 @@ type UntypedRequestBinder struct {
 	Formats      strfmt.Registry
 	paramBinders map[string]*untypedParamBinder
 	debugLogf    func(string, ...any) // a logging function to debug context and all components using it
+	streamUpload bool                 // see StreamUploadExtension
+	streamOpts   []runtime.MultipartFormStreamOption
+	inspector    *runtime.UploadInspector // see WithUploadInspection
 }
 
 // NewUntypedRequestBinder creates a new binder for reading a request.
@@ -61,6 +64,15 @@ func (o *UntypedRequestBinder) bind(request *http.Request, routeParams RoutePara
 
 	var result []error
 	o.debugLogf("binding %d parameters for %s %s", len(o.Parameters), request.Method, request.URL.EscapedPath())
+
+	var stream *runtime.MultipartFormStream
+	if o.streamsUpload(request) {
+		var err error
+		if stream, err = o.openStream(request); err != nil {
+			return errors.CompositeValidationError(err)
+		}
+	}
+
 	for fieldName, param := range o.Parameters {
 		binder := o.paramBinders[fieldName]
 		o.debugLogf("binding parameter %s for %s %s", fieldName, request.Method, request.URL.EscapedPath())
@@ -70,8 +82,12 @@ func (o *UntypedRequestBinder) bind(request *http.Request, routeParams RoutePara
 			target = val.FieldByName(fieldName)
 		}
 
+		streamed := stream != nil && param.In == "formData"
 		if isMap {
 			tpe := binder.Type()
+			if streamed && param.Type == "file" {
+				tpe = multipartStreamType
+			}
 			if tpe == nil {
 				if param.Schema != nil && param.Schema.Type.Contains(typeArray) {
 					tpe = reflect.TypeFor[[]any]()
@@ -87,12 +103,18 @@ func (o *UntypedRequestBinder) bind(request *http.Request, routeParams RoutePara
 			continue
 		}
 
-		if err := binder.Bind(request, routeParams, consumer, target); err != nil {
+		var err error
+		if streamed {
+			err = binder.bindStreamed(request, stream, target)
+		} else {
+			err = binder.Bind(request, routeParams, consumer, target)
+		}
+		if err != nil {
 			result = append(result, err)
 			continue
 		}
 
-		if binder.validator != nil {
+		if binder.validator != nil && (!streamed || param.Type != "file") {
 			rr := binder.validator.Validate(target.Interface())
 			if rr != nil && rr.HasErrors() {
 				result = append(result, rr.AsError())
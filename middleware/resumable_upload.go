@@ -0,0 +1,729 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/spec"
+)
+
+// Headers of the tus resumable upload protocol.
+const (
+	HeaderTusResumable = "Tus-Resumable"
+	HeaderTusVersion   = "Tus-Version"
+	HeaderTusExtension = "Tus-Extension"
+	HeaderTusMaxSize   = "Tus-Max-Size"
+	HeaderUploadOffset = "Upload-Offset"
+	HeaderUploadLength = "Upload-Length"
+	HeaderUploadExpire = "Upload-Expires"
+
+	// TusVersion is the version of the tus protocol supported by [ResumableUploads].
+	TusVersion = "1.0.0"
+
+	// OffsetOctetStreamMime is the media type of the chunks of a resumable upload.
+	OffsetOctetStreamMime = "application/offset+octet-stream"
+)
+
+const (
+	defaultResumableUploadsPath = "/uploads/"
+	resumableUploadIDBytes      = 16
+	resumableDataExt            = ".bin"
+	resumableInfoExt            = ".json"
+
+	// defaultResumableMaxSize is the maximum size of an upload, unless set with WithResumableMaxSize.
+	defaultResumableMaxSize = 1 << 30
+
+	// resumableSweepInterval is the minimum interval between two sweeps of the expired uploads.
+	resumableSweepInterval = time.Minute
+)
+
+// requestOnlyHeaders are not recorded with uploads.
+var requestOnlyHeaders = []string{
+	"Content-Length", "Transfer-Encoding", "Connection",
+	"Expect", HeaderTusResumable, HeaderUploadLength, HeaderUploadOffset,
+}
+
+// defaultCredentialHeaders are never recorded with uploads: credentials come with the last chunk.
+var defaultCredentialHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+
+// ResumableUploadOption configures [ResumableUploads].
+type ResumableUploadOption func(*resumableUploads)
+
+// WithResumableUploadsPath sets the path under which uploads are served (default: "/uploads/").
+func WithResumableUploadsPath(uploadsPath string) ResumableUploadOption {
+	return func(u *resumableUploads) {
+		u.path = strings.TrimSuffix(uploadsPath, "/") + "/"
+	}
+}
+
+// WithResumableCredentialHeaders declares headers carrying credentials, in addition to
+// the Authorization, Proxy-Authorization and Cookie headers.
+//
+// Credentials are never stored with uploads: they are taken from the last chunk.
+func WithResumableCredentialHeaders(names ...string) ResumableUploadOption {
+	return func(u *resumableUploads) {
+		for _, name := range names {
+			u.credentialHeaders = append(u.credentialHeaders, http.CanonicalHeaderKey(name))
+		}
+	}
+}
+
+// WithResumableSecurityDefinitions declares the headers and query parameters of the API keys
+// of a spec as credentials (see [WithResumableCredentialHeaders]).
+func WithResumableSecurityDefinitions(definitions spec.SecurityDefinitions) ResumableUploadOption {
+	return func(u *resumableUploads) {
+		for _, scheme := range definitions {
+			if scheme == nil || scheme.Type != "apiKey" || scheme.Name == "" {
+				continue
+			}
+			switch scheme.In {
+			case "header":
+				u.credentialHeaders = append(u.credentialHeaders, http.CanonicalHeaderKey(scheme.Name))
+			case "query":
+				u.credentialParams = append(u.credentialParams, scheme.Name)
+			}
+		}
+	}
+}
+
+// WithResumableMaxSize sets the maximum size of an upload, in bytes (default: 1 GiB).
+//
+// A size of 0 or less removes the limit.
+func WithResumableMaxSize(size int64) ResumableUploadOption {
+	return func(u *resumableUploads) {
+		u.maxSize = size
+	}
+}
+
+// WithResumableExpiration sets the duration without progress after which incomplete uploads expire (default: 24h).
+//
+// Each chunk received extends the expiration of its upload. Expired uploads are removed when new uploads
+// are created, at most once a minute.
+func WithResumableExpiration(expiration time.Duration) ResumableUploadOption {
+	return func(u *resumableUploads) {
+		u.expiration = expiration
+	}
+}
+
+// WithResumableQuota sets the total size of the incomplete uploads, in bytes.
+//
+// The length of an upload is reserved when it is created, and released once the upload is complete,
+// terminated or expired. An upload which would exceed the quota is rejected with a 507 (Insufficient Storage).
+func WithResumableQuota(size int64) ResumableUploadOption {
+	return func(u *resumableUploads) {
+		u.quota = size
+	}
+}
+
+// WithResumableRoutes sets the API of which operations accept resumable uploads: an upload is created only
+// for a request routed to an operation with a body or formData parameter.
+func WithResumableRoutes(ctx *Context) ResumableUploadOption {
+	return func(u *resumableUploads) {
+		u.routes = ctx
+	}
+}
+
+// WithResumableAuthorizer sets a function authorizing the requests which create an upload,
+// append to it, query its offset or terminate it.
+//
+// A request is rejected with the status code of the returned error when it is an [errors.Error],
+// with a 401 otherwise.
+func WithResumableAuthorizer(authorize func(*http.Request) error) ResumableUploadOption {
+	return func(u *resumableUploads) {
+		u.authorize = authorize
+	}
+}
+
+// ResumableUploads serves resumable uploads of request bodies with the tus protocol
+// (https://tus.io/protocols/resumable-upload), and hands completed uploads over to next.
+//
+// Like with the IETF resumable uploads draft, an upload is created by sending the request of an operation
+// (e.g. a large multipart/form-data body) without its body, but with the Tus-Resumable and Upload-Length headers.
+// The response is 201 (Created), with the Location of the upload, under the uploads path. The body is then appended
+// in chunks with PATCH requests, and an interrupted upload is resumed from the offset returned by a HEAD request,
+// as specified by tus. DELETE terminates an upload.
+//
+// Once the last chunk has been received, the original request is replayed to next, with the assembled body and the
+// headers of the request which created the upload. The response of next is the response to the last chunk.
+//
+// Credentials (the Authorization, Proxy-Authorization and Cookie headers, and those declared with
+// [WithResumableCredentialHeaders] or [WithResumableSecurityDefinitions]) are never stored: they are taken from
+// the last chunk, which is rejected with a 401 when it lacks credentials sent by the request which created the upload.
+// Credentials sent as query parameters are likewise taken from the URL of the last chunk.
+//
+// Uploads are stored in dir, up to 1 GiB each unless set otherwise with [WithResumableMaxSize].
+// Uploads are created only for the operations of the API set with [WithResumableRoutes], and only when
+// an authorizer is set with [WithResumableAuthorizer], or a total size with [WithResumableQuota]:
+// other requests are handed over to next as they are. Appending to an upload doesn't require authentication
+// unless an authorizer is set: the upload ID is random, and the request is authenticated once complete.
+//
+// [client.Runtime.Upload] implements the client side.
+func ResumableUploads(dir string, next http.Handler, opts ...ResumableUploadOption) http.Handler {
+	u := &resumableUploads{
+		dir:               dir,
+		next:              next,
+		path:              defaultResumableUploadsPath,
+		expiration:        24 * time.Hour, //nolint:mnd // one day
+		now:               time.Now,
+		maxSize:           defaultResumableMaxSize,
+		credentialHeaders: slices.Clone(defaultCredentialHeaders),
+	}
+	for _, apply := range opts {
+		apply(u)
+	}
+	if u.routes != nil {
+		u.router = u.routes.ensureRouter()
+	}
+
+	return u
+}
+
+type resumableUploads struct {
+	dir        string
+	next       http.Handler
+	path       string
+	maxSize    int64
+	expiration time.Duration
+	now        func() time.Time
+
+	credentialHeaders []string // canonical names of the headers carrying credentials
+	credentialParams  []string // names of the query parameters carrying credentials
+
+	authorize func(*http.Request) error
+	quota     int64
+	routes    *Context
+	router    Router
+
+	mu         sync.Mutex
+	active     map[string]struct{} // uploads being appended to
+	lastSweep  time.Time
+	reserved   map[string]int64 // lengths of the stored uploads, by ID, when a quota is set
+	storedSize int64
+}
+
+// resumableUpload is the information recorded when an upload is created.
+type resumableUpload struct {
+	Method  string      `json:"method"`
+	URI     string      `json:"uri"`
+	Header  http.Header `json:"header"`
+	Length  int64       `json:"length"`
+	Expires time.Time   `json:"expires"`
+
+	// Credentials lists the credential headers and query parameters sent by the request which created the upload,
+	// without their values: the last chunk must send them again.
+	Credentials []string `json:"credentials,omitempty"`
+}
+
+func (u *resumableUploads) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, isUpload := strings.CutPrefix(r.URL.Path, u.path)
+	switch {
+	case isUpload:
+		if r.Method != http.MethodOptions && !u.authorized(w, r) {
+			return
+		}
+		u.serveUpload(w, r, id)
+	case r.Header.Get(HeaderTusResumable) != "" && r.Header.Get(HeaderUploadLength) != "" && u.creates(r):
+		if !u.authorized(w, r) {
+			return
+		}
+		u.create(w, r)
+	default:
+		u.next.ServeHTTP(w, r)
+	}
+}
+
+// creates tells if a request creates an upload: it must be routed to an operation accepting a body,
+// and creation must be either authorized or limited by a quota.
+func (u *resumableUploads) creates(r *http.Request) bool {
+	if u.router == nil || (u.authorize == nil && u.quota <= 0) {
+		return false
+	}
+
+	route, ok := u.router.Lookup(r.Method, r.URL.EscapedPath())
+	if !ok {
+		return false
+	}
+
+	for _, param := range route.Parameters {
+		if param.In == "body" || param.In == "formData" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// authorized tells if a request of the protocol is authorized, and rejects it otherwise.
+func (u *resumableUploads) authorized(w http.ResponseWriter, r *http.Request) bool {
+	if u.authorize == nil {
+		return true
+	}
+
+	err := u.authorize(r)
+	if err == nil {
+		return true
+	}
+
+	code := http.StatusUnauthorized
+	var apiErr errors.Error
+	if stderrors.As(err, &apiErr) {
+		code = int(apiErr.Code())
+	}
+	w.Header().Set(HeaderTusResumable, TusVersion)
+	http.Error(w, err.Error(), code)
+
+	return false
+}
+
+func (u *resumableUploads) serveUpload(w http.ResponseWriter, r *http.Request, id string) {
+	w.Header().Set(HeaderTusResumable, TusVersion)
+
+	if r.Method == http.MethodOptions {
+		w.Header().Set(HeaderTusVersion, TusVersion)
+		w.Header().Set(HeaderTusExtension, "creation,expiration,termination")
+		if u.maxSize > 0 {
+			w.Header().Set(HeaderTusMaxSize, strconv.FormatInt(u.maxSize, 10))
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+		return
+	}
+
+	if r.Header.Get(HeaderTusResumable) != TusVersion {
+		w.Header().Set(HeaderTusVersion, TusVersion)
+		w.WriteHeader(http.StatusPreconditionFailed)
+
+		return
+	}
+
+	if !validUploadID(id) {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	if !u.acquire(id) {
+		http.Error(w, "the upload is in use", http.StatusConflict)
+
+		return
+	}
+	defer u.release(id)
+
+	upload, offset, err := u.load(id)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	switch r.Method {
+	case http.MethodHead:
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set(HeaderUploadOffset, strconv.FormatInt(offset, 10))
+		w.Header().Set(HeaderUploadLength, strconv.FormatInt(upload.Length, 10))
+		w.Header().Set(HeaderUploadExpire, upload.Expires.UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+	case http.MethodPatch:
+		u.appendChunk(w, r, id, upload, offset)
+	case http.MethodDelete:
+		u.remove(id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.Header().Set("Allow", "OPTIONS, HEAD, PATCH, DELETE")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (u *resumableUploads) create(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(HeaderTusResumable, TusVersion)
+
+	if r.Header.Get(HeaderTusResumable) != TusVersion {
+		w.Header().Set(HeaderTusVersion, TusVersion)
+		w.WriteHeader(http.StatusPreconditionFailed)
+
+		return
+	}
+
+	length, err := strconv.ParseInt(r.Header.Get(HeaderUploadLength), 10, 64)
+	if err != nil || length < 0 {
+		http.Error(w, "invalid Upload-Length", http.StatusBadRequest)
+
+		return
+	}
+
+	if u.maxSize > 0 && length > u.maxSize {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+
+		return
+	}
+
+	u.sweep()
+
+	upload := resumableUpload{
+		Method:  r.Method,
+		Header:  r.Header.Clone(),
+		Length:  length,
+		Expires: u.now().Add(u.expiration),
+	}
+	for _, name := range requestOnlyHeaders {
+		upload.Header.Del(name)
+	}
+	for _, name := range u.credentialHeaders {
+		if _, ok := upload.Header[name]; ok {
+			upload.Credentials = append(upload.Credentials, name)
+			upload.Header.Del(name)
+		}
+	}
+
+	target := *r.URL
+	if query := target.Query(); len(u.credentialParams) > 0 {
+		for _, name := range u.credentialParams {
+			if query.Has(name) {
+				upload.Credentials = append(upload.Credentials, "?"+name)
+				query.Del(name)
+			}
+		}
+		target.RawQuery = query.Encode()
+	}
+	upload.URI = target.RequestURI()
+
+	id := newUploadID()
+	if !u.reserve(id, length) {
+		http.Error(w, "the uploads exceed the storage quota", http.StatusInsufficientStorage)
+
+		return
+	}
+
+	if err := u.store(id, upload); err != nil {
+		u.remove(id)
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Location", path.Join(u.path, id))
+	w.Header().Set(HeaderUploadOffset, "0")
+	w.Header().Set(HeaderUploadExpire, upload.Expires.UTC().Format(http.TimeFormat))
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (u *resumableUploads) appendChunk(w http.ResponseWriter, r *http.Request, id string, upload resumableUpload, offset int64) {
+	if r.Header.Get("Content-Type") != OffsetOctetStreamMime {
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+
+		return
+	}
+
+	if r.Header.Get(HeaderUploadOffset) != strconv.FormatInt(offset, 10) {
+		w.Header().Set(HeaderUploadOffset, strconv.FormatInt(offset, 10))
+		w.WriteHeader(http.StatusConflict)
+
+		return
+	}
+
+	data, err := os.OpenFile(u.dataPath(id), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	// bytes received before an interruption are kept: the client resumes after them
+	written, copyErr := io.Copy(data, io.LimitReader(r.Body, upload.Length-offset))
+	if err := stderrors.Join(copyErr, data.Close()); err != nil {
+		w.Header().Set(HeaderUploadOffset, strconv.FormatInt(offset+written, 10))
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	offset += written
+	if offset < upload.Length {
+		// the upload expires after some inactivity, not while it makes progress
+		if written > 0 {
+			upload.Expires = u.now().Add(u.expiration)
+			if err := u.store(id, upload); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+
+				return
+			}
+		}
+		w.Header().Set(HeaderUploadOffset, strconv.FormatInt(offset, 10))
+		w.Header().Set(HeaderUploadExpire, upload.Expires.UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusNoContent)
+
+		return
+	}
+
+	if n, _ := r.Body.Read(make([]byte, 1)); n > 0 {
+		u.remove(id)
+		http.Error(w, "the chunk exceeds the Upload-Length", http.StatusRequestEntityTooLarge)
+
+		return
+	}
+
+	if !hasCredentials(r, upload.Credentials) {
+		// the upload is kept: the last chunk may be sent again, empty, with credentials
+		w.Header().Set(HeaderUploadOffset, strconv.FormatInt(offset, 10))
+		http.Error(w, "the last chunk must carry the credentials of the upload", http.StatusUnauthorized)
+
+		return
+	}
+
+	u.complete(w, r, id, upload)
+}
+
+// hasCredentials tells if a request sends all the credential headers and query parameters of an upload.
+func hasCredentials(r *http.Request, credentials []string) bool {
+	var query url.Values
+	for _, name := range credentials {
+		if param, isParam := strings.CutPrefix(name, "?"); isParam {
+			if query == nil {
+				query = r.URL.Query()
+			}
+			if !query.Has(param) {
+				return false
+			}
+
+			continue
+		}
+
+		if len(r.Header.Values(name)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// complete replays the request which created the upload, with the assembled body.
+func (u *resumableUploads) complete(w http.ResponseWriter, r *http.Request, id string, upload resumableUpload) {
+	defer u.remove(id)
+
+	body, err := os.Open(u.dataPath(id))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+	defer body.Close()
+
+	target, err := url.ParseRequestURI(upload.URI)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	replay := r.Clone(r.Context())
+	replay.Method = upload.Method
+	replay.URL = target
+	replay.RequestURI = upload.URI
+	replay.Header = upload.Header.Clone()
+	for _, name := range u.credentialHeaders {
+		if values := r.Header.Values(name); len(values) > 0 {
+			replay.Header[name] = values
+		}
+	}
+	if len(u.credentialParams) > 0 {
+		query, chunkQuery := target.Query(), r.URL.Query()
+		for _, name := range u.credentialParams {
+			if values, ok := chunkQuery[name]; ok {
+				query[name] = values
+			}
+		}
+		target.RawQuery = query.Encode()
+		replay.RequestURI = target.RequestURI()
+	}
+	replay.Body = body
+	replay.ContentLength = upload.Length
+	replay.Header.Set("Content-Length", strconv.FormatInt(upload.Length, 10))
+
+	w.Header().Set(HeaderUploadOffset, strconv.FormatInt(upload.Length, 10))
+	u.next.ServeHTTP(w, replay)
+}
+
+func newUploadID() string {
+	var buf [resumableUploadIDBytes]byte
+	_, _ = rand.Read(buf[:])
+
+	return hex.EncodeToString(buf[:])
+}
+
+// store records the information of an upload, and creates its empty data file when it doesn't exist yet.
+func (u *resumableUploads) store(id string, upload resumableUpload) error {
+	info, err := json.Marshal(upload)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.OpenFile(u.dataPath(id), os.O_WRONLY|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	if err := data.Close(); err != nil {
+		return err
+	}
+
+	return os.WriteFile(u.infoPath(id), info, 0o600)
+}
+
+// load reads the information of an upload, and its current offset.
+func (u *resumableUploads) load(id string) (resumableUpload, int64, error) {
+	var upload resumableUpload
+
+	info, err := os.ReadFile(u.infoPath(id))
+	if err != nil {
+		return upload, 0, err
+	}
+
+	if err := json.Unmarshal(info, &upload); err != nil {
+		return upload, 0, err
+	}
+
+	if u.now().After(upload.Expires) {
+		u.remove(id)
+
+		return upload, 0, os.ErrNotExist
+	}
+
+	stat, err := os.Stat(u.dataPath(id))
+	if err != nil {
+		return upload, 0, err
+	}
+
+	return upload, stat.Size(), nil
+}
+
+func (u *resumableUploads) remove(id string) {
+	_ = os.Remove(u.infoPath(id))
+	_ = os.Remove(u.dataPath(id))
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if length, ok := u.reserved[id]; ok {
+		delete(u.reserved, id)
+		u.storedSize -= length
+	}
+}
+
+// reserve reserves the length of a new upload, within the quota.
+//
+// The uploads stored by a previous process are accounted for on the first reservation.
+func (u *resumableUploads) reserve(id string, length int64) bool {
+	if u.quota <= 0 {
+		return true
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.reserved == nil {
+		u.reserved = make(map[string]int64)
+		infos, _ := filepath.Glob(filepath.Join(u.dir, "*"+resumableInfoExt))
+		for _, info := range infos {
+			var stored resumableUpload
+			content, err := os.ReadFile(info)
+			if err != nil || json.Unmarshal(content, &stored) != nil {
+				continue
+			}
+			u.reserved[strings.TrimSuffix(filepath.Base(info), resumableInfoExt)] = stored.Length
+			u.storedSize += stored.Length
+		}
+	}
+
+	if u.storedSize+length > u.quota {
+		return false
+	}
+	u.reserved[id] = length
+	u.storedSize += length
+
+	return true
+}
+
+// sweep removes expired uploads, at most once per resumableSweepInterval.
+func (u *resumableUploads) sweep() {
+	u.mu.Lock()
+	now := u.now()
+	due := now.Sub(u.lastSweep) >= resumableSweepInterval
+	if due {
+		u.lastSweep = now
+	}
+	u.mu.Unlock()
+	if !due {
+		return
+	}
+
+	infos, err := filepath.Glob(filepath.Join(u.dir, "*"+resumableInfoExt))
+	if err != nil {
+		return
+	}
+
+	for _, info := range infos {
+		id := strings.TrimSuffix(filepath.Base(info), resumableInfoExt)
+		if !validUploadID(id) || !u.acquire(id) {
+			continue
+		}
+		_, _, _ = u.load(id) // removes the upload when expired
+		u.release(id)
+	}
+}
+
+func (u *resumableUploads) acquire(id string) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if _, busy := u.active[id]; busy {
+		return false
+	}
+
+	if u.active == nil {
+		u.active = make(map[string]struct{})
+	}
+	u.active[id] = struct{}{}
+
+	return true
+}
+
+func (u *resumableUploads) release(id string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	delete(u.active, id)
+}
+
+func (u *resumableUploads) dataPath(id string) string {
+	return filepath.Join(u.dir, id+resumableDataExt)
+}
+
+func (u *resumableUploads) infoPath(id string) string {
+	return filepath.Join(u.dir, id+resumableInfoExt)
+}
+
+func validUploadID(id string) bool {
+	if len(id) != 2*resumableUploadIDBytes {
+		return false
+	}
+
+	_, err := hex.DecodeString(id)
+
+	return err == nil
+}
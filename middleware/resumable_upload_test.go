@@ -0,0 +1,484 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	stdcontext "context"
+	stderrors "errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+const testResumableBody = "the whole request body"
+
+const resumableSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "resumable uploads", "version": "1.0"},
+  "basePath": "/api",
+  "paths": {
+    "/documents": {
+      "get": {"responses": {"200": {"description": "documents"}}},
+      "post": {
+        "consumes": ["multipart/form-data"],
+        "parameters": [{"name": "document", "in": "formData", "type": "file"}],
+        "responses": {"201": {"description": "created"}}
+      }
+    }
+  }
+}`
+
+type resumableReplay struct {
+	method string
+	uri    string
+	header http.Header
+	body   string
+}
+
+func newResumableUploads(t *testing.T, opts ...ResumableUploadOption) (http.Handler, string, *[]resumableReplay) {
+	t.Helper()
+
+	dir := t.TempDir()
+	var replays []resumableReplay
+	next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		replays = append(replays, resumableReplay{method: r.Method, uri: r.RequestURI, header: r.Header, body: string(body)})
+		rw.WriteHeader(http.StatusAccepted)
+	})
+
+	opts = append([]ResumableUploadOption{
+		WithResumableRoutes(testContext(t, resumableSpec)),
+		WithResumableQuota(1 << 40),
+	}, opts...)
+
+	return ResumableUploads(dir, next, opts...), dir, &replays
+}
+
+func resumableRequest(method, target string, header map[string]string, body string) *http.Request {
+	r := httptest.NewRequestWithContext(stdcontext.Background(), method, target, strings.NewReader(body))
+	r.Header.Set(HeaderTusResumable, TusVersion)
+	for name, value := range header {
+		r.Header.Set(name, value)
+	}
+
+	return r
+}
+
+func createResumableUpload(t *testing.T, handler http.Handler, length int) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, resumableRequest(http.MethodPost, "/api/documents?draft=true", map[string]string{
+		HeaderUploadLength: strconv.Itoa(length),
+		"Content-Type":     "text/plain",
+		"Authorization":    "Bearer creation",
+	}, ""))
+	require.EqualT(t, http.StatusCreated, rec.Code)
+	assert.EqualT(t, "0", rec.Header().Get(HeaderUploadOffset))
+	assert.EqualT(t, TusVersion, rec.Header().Get(HeaderTusResumable))
+	assert.NotEmpty(t, rec.Header().Get(HeaderUploadExpire))
+
+	location := rec.Header().Get("Location")
+	require.TrueT(t, strings.HasPrefix(location, defaultResumableUploadsPath))
+
+	return location
+}
+
+func appendChunk(handler http.Handler, location string, offset int, chunk string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, resumableRequest(http.MethodPatch, location, map[string]string{
+		HeaderUploadOffset: strconv.Itoa(offset),
+		"Content-Type":     OffsetOctetStreamMime,
+		"Authorization":    "Bearer chunk",
+	}, chunk))
+
+	return rec
+}
+
+func TestResumableUploads(t *testing.T) {
+	t.Run("should replay the request once the upload is complete", func(t *testing.T) {
+		handler, dir, replays := newResumableUploads(t)
+		location := createResumableUpload(t, handler, len(testResumableBody))
+
+		// credentials are not recorded
+		info, err := os.ReadFile(filepath.Join(dir, strings.TrimPrefix(location, defaultResumableUploadsPath)+resumableInfoExt))
+		require.NoError(t, err)
+		assert.StringNotContainsT(t, string(info), "Bearer")
+
+		rec := appendChunk(handler, location, 0, testResumableBody[:10])
+		require.EqualT(t, http.StatusNoContent, rec.Code)
+		assert.EqualT(t, "10", rec.Header().Get(HeaderUploadOffset))
+
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, resumableRequest(http.MethodHead, location, nil, ""))
+		require.EqualT(t, http.StatusOK, rec.Code)
+		assert.EqualT(t, "10", rec.Header().Get(HeaderUploadOffset))
+		assert.EqualT(t, strconv.Itoa(len(testResumableBody)), rec.Header().Get(HeaderUploadLength))
+		assert.EqualT(t, "no-store", rec.Header().Get("Cache-Control"))
+		assert.Empty(t, *replays)
+
+		rec = appendChunk(handler, location, 10, testResumableBody[10:])
+		require.EqualT(t, http.StatusAccepted, rec.Code)
+		assert.EqualT(t, strconv.Itoa(len(testResumableBody)), rec.Header().Get(HeaderUploadOffset))
+
+		require.Len(t, *replays, 1)
+		replay := (*replays)[0]
+		assert.EqualT(t, http.MethodPost, replay.method)
+		assert.EqualT(t, "/api/documents?draft=true", replay.uri)
+		assert.EqualT(t, testResumableBody, replay.body)
+		assert.EqualT(t, "text/plain", replay.header.Get("Content-Type"))
+		assert.EqualT(t, "Bearer chunk", replay.header.Get("Authorization"))
+		assert.Empty(t, replay.header.Get(HeaderTusResumable))
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("should take credentials from the last chunk only", func(t *testing.T) {
+		handler, dir, replays := newResumableUploads(t, WithResumableSecurityDefinitions(spec.SecurityDefinitions{
+			"key":      spec.APIKeyAuth("X-Api-Key", "header"),
+			"token":    spec.APIKeyAuth("token", "query"),
+			"basic":    spec.BasicAuth(),
+			"implicit": spec.OAuth2Implicit("https://example.com/authorize"),
+		}))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, resumableRequest(http.MethodPost, "/api/documents?draft=true&token=secret-token", map[string]string{
+			HeaderUploadLength: strconv.Itoa(len(testResumableBody)),
+			"Content-Type":     "text/plain",
+			"X-Api-Key":        "secret-key",
+			"Cookie":           "session=secret-session",
+		}, ""))
+		require.EqualT(t, http.StatusCreated, rec.Code)
+		location := rec.Header().Get("Location")
+
+		info, err := os.ReadFile(filepath.Join(dir, strings.TrimPrefix(location, defaultResumableUploadsPath)+resumableInfoExt))
+		require.NoError(t, err)
+		assert.StringNotContainsT(t, string(info), "secret")
+
+		// the last chunk is rejected without credentials, but the upload is kept
+		rec = appendChunk(handler, location, 0, testResumableBody)
+		require.EqualT(t, http.StatusUnauthorized, rec.Code)
+		assert.EqualT(t, strconv.Itoa(len(testResumableBody)), rec.Header().Get(HeaderUploadOffset))
+		assert.Empty(t, *replays)
+
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, resumableRequest(http.MethodPatch, location+"?token=chunk-token", map[string]string{
+			HeaderUploadOffset: strconv.Itoa(len(testResumableBody)),
+			"Content-Type":     OffsetOctetStreamMime,
+			"X-Api-Key":        "chunk-key",
+			"Cookie":           "session=chunk-session",
+		}, ""))
+		require.EqualT(t, http.StatusAccepted, rec.Code)
+
+		require.Len(t, *replays, 1)
+		replay := (*replays)[0]
+		assert.EqualT(t, "/api/documents?draft=true&token=chunk-token", replay.uri)
+		assert.EqualT(t, testResumableBody, replay.body)
+		assert.EqualT(t, "chunk-key", replay.header.Get("X-Api-Key"))
+		assert.EqualT(t, "session=chunk-session", replay.header.Get("Cookie"))
+	})
+
+	t.Run("should pass other requests through", func(t *testing.T) {
+		handler, _, replays := newResumableUploads(t)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequestWithContext(stdcontext.Background(), http.MethodPost, "/api/documents", strings.NewReader("body")))
+		assert.EqualT(t, http.StatusAccepted, rec.Code)
+		require.Len(t, *replays, 1)
+		assert.EqualT(t, "body", (*replays)[0].body)
+	})
+
+	t.Run("should create uploads for the routed upload operations only", func(t *testing.T) {
+		handler, dir, replays := newResumableUploads(t)
+
+		for _, r := range []*http.Request{
+			resumableRequest(http.MethodPost, "/api/unknown", map[string]string{HeaderUploadLength: "10"}, ""),
+			resumableRequest(http.MethodPost, "/documents", map[string]string{HeaderUploadLength: "10"}, ""),
+			resumableRequest(http.MethodGet, "/api/documents", map[string]string{HeaderUploadLength: "10"}, ""),
+		} {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, r)
+			assert.EqualT(t, http.StatusAccepted, rec.Code)
+		}
+		assert.Len(t, *replays, 3)
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("should require an authorizer or a quota to create uploads", func(t *testing.T) {
+		handler, dir, replays := newResumableUploads(t, WithResumableQuota(0))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, resumableRequest(http.MethodPost, "/api/documents", map[string]string{
+			HeaderUploadLength: strconv.Itoa(len(testResumableBody)),
+		}, ""))
+		assert.EqualT(t, http.StatusAccepted, rec.Code)
+		assert.Len(t, *replays, 1)
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+
+		authorized, _, _ := newResumableUploads(t, WithResumableQuota(0), WithResumableAuthorizer(func(*http.Request) error { return nil }))
+		createResumableUpload(t, authorized, len(testResumableBody))
+	})
+
+	t.Run("should limit the total size of uploads", func(t *testing.T) {
+		handler, dir, _ := newResumableUploads(t, WithResumableQuota(int64(2*len(testResumableBody))))
+		createResumableUpload(t, handler, len(testResumableBody))
+		location := createResumableUpload(t, handler, len(testResumableBody))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, resumableRequest(http.MethodPost, "/api/documents", map[string]string{
+			HeaderUploadLength: "1",
+		}, ""))
+		assert.EqualT(t, http.StatusInsufficientStorage, rec.Code)
+
+		// completed uploads release their size
+		rec = appendChunk(handler, location, 0, testResumableBody)
+		require.EqualT(t, http.StatusAccepted, rec.Code)
+		createResumableUpload(t, handler, len(testResumableBody))
+
+		// uploads stored by a previous process are accounted for
+		restarted := ResumableUploads(dir, handler.(*resumableUploads).next,
+			WithResumableRoutes(testContext(t, resumableSpec)),
+			WithResumableQuota(int64(2*len(testResumableBody))),
+		)
+		rec = httptest.NewRecorder()
+		restarted.ServeHTTP(rec, resumableRequest(http.MethodPost, "/api/documents", map[string]string{
+			HeaderUploadLength: "1",
+		}, ""))
+		assert.EqualT(t, http.StatusInsufficientStorage, rec.Code)
+	})
+
+	t.Run("should advertise the protocol", func(t *testing.T) {
+		handler, _, _ := newResumableUploads(t, WithResumableUploadsPath("/files"), WithResumableMaxSize(1024))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequestWithContext(stdcontext.Background(), http.MethodOptions, "/files/", nil))
+		assert.EqualT(t, http.StatusNoContent, rec.Code)
+		assert.EqualT(t, TusVersion, rec.Header().Get(HeaderTusVersion))
+		assert.EqualT(t, "creation,expiration,termination", rec.Header().Get(HeaderTusExtension))
+		assert.EqualT(t, "1024", rec.Header().Get(HeaderTusMaxSize))
+	})
+
+	t.Run("should reject invalid requests", func(t *testing.T) {
+		handler, _, _ := newResumableUploads(t, WithResumableMaxSize(100))
+		location := createResumableUpload(t, handler, len(testResumableBody))
+
+		for name, test := range map[string]struct {
+			request *http.Request
+			status  int
+		}{
+			"unsupported version": {
+				request: func() *http.Request {
+					r := resumableRequest(http.MethodHead, location, nil, "")
+					r.Header.Set(HeaderTusResumable, "0.2.2")
+					return r
+				}(),
+				status: http.StatusPreconditionFailed,
+			},
+			"invalid length": {
+				request: resumableRequest(http.MethodPost, "/api/documents", map[string]string{HeaderUploadLength: "-1"}, ""),
+				status:  http.StatusBadRequest,
+			},
+			"too large": {
+				request: resumableRequest(http.MethodPost, "/api/documents", map[string]string{HeaderUploadLength: "101"}, ""),
+				status:  http.StatusRequestEntityTooLarge,
+			},
+			"unknown upload": {
+				request: resumableRequest(http.MethodHead, defaultResumableUploadsPath+strings.Repeat("0", 32), nil, ""),
+				status:  http.StatusNotFound,
+			},
+			"invalid upload ID": {
+				request: resumableRequest(http.MethodHead, defaultResumableUploadsPath+"../secret", nil, ""),
+				status:  http.StatusNotFound,
+			},
+			"wrong content type": {
+				request: resumableRequest(http.MethodPatch, location, map[string]string{
+					HeaderUploadOffset: "0",
+					"Content-Type":     "text/plain",
+				}, "chunk"),
+				status: http.StatusUnsupportedMediaType,
+			},
+			"wrong offset": {
+				request: resumableRequest(http.MethodPatch, location, map[string]string{
+					HeaderUploadOffset: "3",
+					"Content-Type":     OffsetOctetStreamMime,
+				}, "chunk"),
+				status: http.StatusConflict,
+			},
+			"unsupported method": {
+				request: resumableRequest(http.MethodGet, location, nil, ""),
+				status:  http.StatusMethodNotAllowed,
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, test.request)
+				assert.EqualT(t, test.status, rec.Code)
+			})
+		}
+
+		rec := appendChunk(handler, location, 0, testResumableBody+" and more")
+		assert.EqualT(t, http.StatusRequestEntityTooLarge, rec.Code)
+	})
+
+	t.Run("should limit the size of uploads by default", func(t *testing.T) {
+		handler, _, _ := newResumableUploads(t)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, resumableRequest(http.MethodPost, "/api/documents", map[string]string{
+			HeaderUploadLength: strconv.Itoa(defaultResumableMaxSize + 1),
+		}, ""))
+		assert.EqualT(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequestWithContext(stdcontext.Background(), http.MethodOptions, defaultResumableUploadsPath, nil))
+		assert.EqualT(t, strconv.Itoa(defaultResumableMaxSize), rec.Header().Get(HeaderTusMaxSize))
+
+		unlimited, _, _ := newResumableUploads(t, WithResumableMaxSize(0))
+		rec = httptest.NewRecorder()
+		unlimited.ServeHTTP(rec, resumableRequest(http.MethodPost, "/api/documents", map[string]string{
+			HeaderUploadLength: strconv.Itoa(defaultResumableMaxSize + 1),
+		}, ""))
+		assert.EqualT(t, http.StatusCreated, rec.Code)
+	})
+
+	t.Run("should authorize the requests of the protocol", func(t *testing.T) {
+		authorizer := WithResumableAuthorizer(func(r *http.Request) error {
+			switch r.Header.Get("X-Uploader") {
+			case "allowed":
+				return nil
+			case "banned":
+				return errors.New(http.StatusForbidden, "banned")
+			default:
+				return stderrors.New("who are you?")
+			}
+		})
+		handler, dir, _ := newResumableUploads(t, authorizer)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, resumableRequest(http.MethodPost, "/api/documents", map[string]string{
+			HeaderUploadLength: strconv.Itoa(len(testResumableBody)),
+		}, ""))
+		assert.EqualT(t, http.StatusUnauthorized, rec.Code)
+
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, resumableRequest(http.MethodPost, "/api/documents", map[string]string{
+			HeaderUploadLength: strconv.Itoa(len(testResumableBody)),
+			"X-Uploader":       "allowed",
+		}, ""))
+		require.EqualT(t, http.StatusCreated, rec.Code)
+		location := rec.Header().Get("Location")
+
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, resumableRequest(http.MethodPatch, location, map[string]string{
+			HeaderUploadOffset: "0",
+			"Content-Type":     OffsetOctetStreamMime,
+			"X-Uploader":       "banned",
+		}, testResumableBody))
+		assert.EqualT(t, http.StatusForbidden, rec.Code)
+
+		info, err := os.Stat(filepath.Join(dir, strings.TrimPrefix(location, defaultResumableUploadsPath)+resumableDataExt))
+		require.NoError(t, err)
+		assert.EqualT(t, int64(0), info.Size())
+
+		// the protocol is still advertised
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequestWithContext(stdcontext.Background(), http.MethodOptions, defaultResumableUploadsPath, nil))
+		assert.EqualT(t, http.StatusNoContent, rec.Code)
+	})
+
+	t.Run("should sweep expired uploads at most once a minute", func(t *testing.T) {
+		handler, dir, _ := newResumableUploads(t, WithResumableExpiration(time.Second))
+		uploads := handler.(*resumableUploads)
+		start := time.Now()
+		uploads.now = func() time.Time { return start }
+		createResumableUpload(t, handler, len(testResumableBody))
+
+		uploads.now = func() time.Time { return start.Add(30 * time.Second) }
+		createResumableUpload(t, handler, len(testResumableBody))
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 4)
+
+		uploads.now = func() time.Time { return start.Add(time.Minute) }
+		createResumableUpload(t, handler, len(testResumableBody))
+
+		entries, err = os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 2)
+	})
+
+	t.Run("should terminate uploads", func(t *testing.T) {
+		handler, dir, _ := newResumableUploads(t)
+		location := createResumableUpload(t, handler, len(testResumableBody))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, resumableRequest(http.MethodDelete, location, nil, ""))
+		assert.EqualT(t, http.StatusNoContent, rec.Code)
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+
+		rec = appendChunk(handler, location, 0, testResumableBody)
+		assert.EqualT(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("should expire uploads", func(t *testing.T) {
+		handler, dir, _ := newResumableUploads(t, WithResumableExpiration(time.Hour))
+		uploads := handler.(*resumableUploads)
+		createResumableUpload(t, handler, len(testResumableBody))
+
+		uploads.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+		location := createResumableUpload(t, handler, len(testResumableBody))
+
+		// the first upload has been swept
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 2)
+
+		uploads.now = func() time.Time { return time.Now().Add(4 * time.Hour) }
+		rec := appendChunk(handler, location, 0, testResumableBody)
+		assert.EqualT(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("should extend the expiration of uploads making progress", func(t *testing.T) {
+		handler, _, _ := newResumableUploads(t, WithResumableExpiration(time.Hour))
+		uploads := handler.(*resumableUploads)
+		start := time.Now()
+		uploads.now = func() time.Time { return start }
+		location := createResumableUpload(t, handler, len(testResumableBody))
+
+		uploads.now = func() time.Time { return start.Add(50 * time.Minute) }
+		rec := appendChunk(handler, location, 0, testResumableBody[:10])
+		require.EqualT(t, http.StatusNoContent, rec.Code)
+		assert.EqualT(t, start.Add(110*time.Minute).UTC().Format(http.TimeFormat), rec.Header().Get(HeaderUploadExpire))
+
+		uploads.now = func() time.Time { return start.Add(100 * time.Minute) }
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, resumableRequest(http.MethodHead, location, nil, ""))
+		require.EqualT(t, http.StatusOK, rec.Code)
+		assert.EqualT(t, "10", rec.Header().Get(HeaderUploadOffset))
+	})
+}
@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag/conv"
+
+	"github.com/go-openapi/runtime/middleware/denco"
+)
+
+// pathConstraints derives the routing constraints of the path parameters of a route from their type,
+// format, pattern and enum, so that routes with the same structure (e.g. /items/{id} and /items/{name})
+// may coexist.
+//
+// Parameters sharing a path segment with other parameters or literals (e.g. /files/{name}.{ext})
+// are not constrained.
+func pathConstraints(path string, parameters map[string]spec.Parameter, formats strfmt.Registry) map[string]denco.Constraint {
+	var constraints map[string]denco.Constraint
+	for _, match := range pathConverter.FindAllStringSubmatch(path, -1) {
+		name, rest := match[1], match[2]
+		if rest != "" {
+			continue
+		}
+
+		for _, param := range parameters {
+			if param.In != "path" || param.Name != name {
+				continue
+			}
+
+			if constraint := paramConstraint(param, formats); constraint != nil {
+				if constraints == nil {
+					constraints = make(map[string]denco.Constraint)
+				}
+				constraints[name] = constraint
+			}
+		}
+	}
+
+	return constraints
+}
+
+// paramConstraint builds the constraint of a path parameter, or nil when any value is acceptable.
+func paramConstraint(param spec.Parameter, formats strfmt.Registry) denco.Constraint {
+	var checks []func(string) bool
+
+	switch param.Type {
+	case "integer":
+		bitSize := 64
+		if param.Format == "int32" {
+			bitSize = 32
+		}
+		checks = append(checks, func(value string) bool {
+			_, err := strconv.ParseInt(value, 10, bitSize)
+			return err == nil
+		})
+	case "number":
+		checks = append(checks, func(value string) bool {
+			_, err := strconv.ParseFloat(value, 64)
+			return err == nil
+		})
+	case "boolean":
+		checks = append(checks, func(value string) bool {
+			_, err := conv.ConvertBool(value)
+			return err == nil
+		})
+	case "string":
+		if param.Format != "" && formats != nil && formats.ContainsName(param.Format) {
+			format := param.Format
+			checks = append(checks, func(value string) bool {
+				return formats.Validates(format, value)
+			})
+		}
+	default:
+		// arrays and files are not constrained
+		return nil
+	}
+
+	if param.Pattern != "" {
+		if pattern, err := regexp.Compile(param.Pattern); err == nil {
+			checks = append(checks, pattern.MatchString)
+		}
+	}
+
+	if len(param.Enum) > 0 {
+		values := make([]string, 0, len(param.Enum))
+		for _, value := range param.Enum {
+			values = append(values, fmt.Sprint(value))
+		}
+		checks = append(checks, func(value string) bool {
+			return slices.Contains(values, value)
+		})
+	}
+
+	if len(checks) == 0 {
+		return nil
+	}
+
+	return func(value string) bool {
+		if unescaped, err := url.PathUnescape(value); err == nil {
+			value = unescaped
+		}
+
+		for _, check := range checks {
+			if !check(value) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
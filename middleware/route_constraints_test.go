@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/middleware/untyped"
+)
+
+const constrainedRoutesSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "constraints", "version": "1.0"},
+  "consumes": ["application/json"],
+  "produces": ["application/json"],
+  "paths": {
+    "/items/{id}": {
+      "get": {
+        "operationId": "getItemByID",
+        "parameters": [{"name": "id", "in": "path", "required": true, "type": "integer", "format": "int32"}],
+        "responses": {"200": {"description": "ok"}}
+      }
+    },
+    "/items/{name}": {
+      "get": {
+        "operationId": "getItemByName",
+        "parameters": [{"name": "name", "in": "path", "required": true, "type": "string", "pattern": "^[a-z]+$"}],
+        "responses": {"200": {"description": "ok"}}
+      }
+    },
+    "/items/{name}/{status}": {
+      "get": {
+        "operationId": "getItemStatus",
+        "parameters": [
+          {"name": "name", "in": "path", "required": true, "type": "string"},
+          {"name": "status", "in": "path", "required": true, "type": "string", "enum": ["open", "closed"]}
+        ],
+        "responses": {"200": {"description": "ok"}}
+      }
+    },
+    "/items/{name}/{uuid}": {
+      "get": {
+        "operationId": "getItemVersion",
+        "parameters": [
+          {"name": "name", "in": "path", "required": true, "type": "string"},
+          {"name": "uuid", "in": "path", "required": true, "type": "string", "format": "uuid"}
+        ],
+        "responses": {"200": {"description": "ok"}}
+      }
+    }
+  }
+}`
+
+func TestRouter_PathConstraints(t *testing.T) {
+	doc, err := loads.Analyzed(json.RawMessage(constrainedRoutesSpec), "")
+	require.NoError(t, err)
+
+	api := untyped.NewAPI(doc)
+	for path, operationID := range map[string]string{
+		"/items/{id}":            "getItemByID",
+		"/items/{name}":          "getItemByName",
+		"/items/{name}/{status}": "getItemStatus",
+		"/items/{name}/{uuid}":   "getItemVersion",
+	} {
+		api.RegisterOperation("get", path, runtime.OperationHandlerFunc(func(any) (any, error) {
+			return operationID, nil
+		}))
+	}
+	ctx := NewContext(doc, api, nil)
+
+	for path, operationID := range map[string]string{
+		"/items/42":         "getItemByID",
+		"/items/bolts":      "getItemByName",
+		"/items/bolts/open": "getItemStatus",
+		"/items/bolts/0b8d7b58-0f2a-4a3a-9c3e-2e5b8f3f3a11": "getItemVersion",
+	} {
+		explanation := ctx.Explain(http.MethodGet, path)
+		require.EqualT(t, RouteMatched, explanation.Outcome, path)
+		assert.EqualT(t, operationID, explanation.Route.OperationID, path)
+	}
+
+	t.Run("should report invalid parameters when no route satisfies its constraints", func(t *testing.T) {
+		// 99999999999 overflows int32, and doesn't match the pattern of names
+		rec := serveTest(ctx.RoutesHandler(nil), http.MethodGet, "/items/99999999999", nil)
+		assert.EqualT(t, http.StatusUnprocessableEntity, rec.Code)
+	})
+}
@@ -4,12 +4,15 @@
 package middleware
 
 import (
-	"fmt"
+	"mime"
 	"net/http"
 	"net/url"
 	fpath "path"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-openapi/analysis"
 	"github.com/go-openapi/errors"
@@ -17,6 +20,7 @@ import (
 	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/runtime/logger"
 	"github.com/go-openapi/runtime/middleware/denco"
+	"github.com/go-openapi/runtime/middleware/radix"
 	"github.com/go-openapi/runtime/security"
 	"github.com/go-openapi/spec"
 	"github.com/go-openapi/strfmt"
@@ -59,16 +63,40 @@ func (r RouteParams) GetOK(name string) ([]string, bool, bool) {
 
 // NewRouter creates a new context-aware router [middleware].
 func NewRouter(ctx *Context, next http.Handler) http.Handler {
-	if ctx.router == nil {
-		ctx.router = DefaultRouter(ctx.spec, ctx.api, WithDefaultRouterLoggerFunc(ctx.debugLogf))
-	}
+	ctx.ensureRouter()
+	cors := ctx.corsPolicy()
 
 	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
-		if _, rCtx, ok := ctx.RouteInfo(r); ok {
+		if cors != nil && ctx.serveCORS(rw, r, cors) {
+			return
+		}
+
+		if ctx.servePathPolicy(rw, r) {
+			return
+		}
+
+		if route, rCtx, ok := ctx.RouteInfo(r); ok {
+			if cors != nil {
+				ctx.exposeCORSHeaders(rw, cors, route)
+			}
+			if route.deprecation != nil {
+				ctx.announceDeprecation(rw, rCtx, route)
+			}
+			if route.implicitHead {
+				serveHead(next, rw, rCtx)
+				return
+			}
 			next.ServeHTTP(rw, rCtx)
 			return
 		}
 
+		if r.Method == http.MethodOptions && !(cors != nil && isPreflight(r)) && answersOptions(ctx.router) {
+			if allowed := ctx.AllowedMethods(r); len(allowed) > 0 {
+				serveOptions(rw, allowed)
+				return
+			}
+		}
+
 		// Always use the default producer Content-Type for Method not
 		// allowed and Not found responses
 		produces := []string{ctx.api.DefaultProduces()}
@@ -108,13 +136,34 @@ type defaultRouteBuilder struct {
 	analyzer  *analysis.Spec
 	api       RoutableAPI
 	records   map[string][]denco.Record
+	routes    []RouteDescription
 	debugLogf func(string, ...any) // a logging function to debug context and all components using it
+
+	streamOpts  []runtime.MultipartFormStreamOption
+	inspectOpts []runtime.UploadInspectOption
+
+	implicitHead    bool
+	implicitOptions bool
+	radix           bool
+	caseInsensitive bool
+	encodedSlashes  bool
+	deprecationDate time.Time // see Context.SetDeprecationDate
+
+	operationMiddlewares *OperationMiddlewares
 }
 
 type defaultRouter struct {
 	spec      *loads.Document
-	routers   map[string]*denco.Router
+	routers   map[string]pathMatcher
+	routes    []RouteDescription
 	debugLogf func(string, ...any) // a logging function to debug context and all components using it
+
+	implicitHead    bool // see WithImplicitHead
+	implicitOptions bool // see WithImplicitOptions
+	caseInsensitive bool // see WithCaseInsensitivePaths
+	encodedSlashes  bool // see WithEncodedSlashes
+
+	folded *foldedRoutes // the case-insensitive lookup of the routes, see foldCase
 }
 
 func newDefaultRouteBuilder(spec *loads.Document, api RoutableAPI, opts ...DefaultRouterOpt) *defaultRouteBuilder {
@@ -125,13 +174,26 @@ func newDefaultRouteBuilder(spec *loads.Document, api RoutableAPI, opts ...Defau
 	if o.debugLogf == nil {
 		o.debugLogf = debugLogfFunc(nil) // defaults to standard logger
 	}
+	if o.deprecationDate.IsZero() {
+		o.deprecationDate = time.Now()
+	}
 
 	return &defaultRouteBuilder{
-		spec:      spec,
-		analyzer:  analysis.New(spec.Spec()),
-		api:       api,
-		records:   make(map[string][]denco.Record),
-		debugLogf: o.debugLogf,
+		spec:            spec,
+		analyzer:        analysis.New(spec.Spec()),
+		api:             api,
+		records:         make(map[string][]denco.Record),
+		debugLogf:       o.debugLogf,
+		streamOpts:      o.streamOpts,
+		inspectOpts:     o.inspectOpts,
+		implicitHead:    o.implicitHead,
+		implicitOptions: o.implicitOptions,
+		radix:           o.radix,
+		caseInsensitive: o.caseInsensitive,
+		encodedSlashes:  o.encodedSlashes,
+		deprecationDate: o.deprecationDate,
+
+		operationMiddlewares: o.operationMiddlewares,
 	}
 }
 
@@ -139,7 +201,17 @@ func newDefaultRouteBuilder(spec *loads.Document, api RoutableAPI, opts ...Defau
 type DefaultRouterOpt func(*defaultRouterOpts)
 
 type defaultRouterOpts struct {
-	debugLogf func(string, ...any)
+	debugLogf       func(string, ...any)
+	streamOpts      []runtime.MultipartFormStreamOption
+	inspectOpts     []runtime.UploadInspectOption
+	implicitHead    bool
+	implicitOptions bool
+	radix           bool
+	caseInsensitive bool
+	encodedSlashes  bool
+	deprecationDate time.Time
+
+	operationMiddlewares *OperationMiddlewares
 }
 
 // WithDefaultRouterLogger sets the debug logger for the default router.
@@ -158,6 +230,61 @@ func WithDefaultRouterLoggerFunc(fn func(string, ...any)) DefaultRouterOpt {
 	}
 }
 
+// WithMultipartStreamOptions sets the options of the [runtime.MultipartFormStream] handed to
+// operations which stream their uploads (see [StreamUploadExtension]), e.g. to raise the body size cap.
+func WithMultipartStreamOptions(opts ...runtime.MultipartFormStreamOption) DefaultRouterOpt {
+	return func(o *defaultRouterOpts) {
+		o.streamOpts = opts
+	}
+}
+
+// WithUploadInspection inspects the files uploaded to operations with formData file parameters,
+// whether they are streamed or not (see [runtime.UploadInspector]).
+//
+// The media types declared by the consumes of an operation, other than form media types,
+// are added to the allowed content types for its files. For instance, an operation which consumes
+// "multipart/form-data" and "image/png" only accepts PNG files.
+//
+// Files are rejected with a 415 [*runtime.UploadTypeError] or a 422 [*runtime.UploadScanError].
+// Files which could not be scanned fail with a 503 [*runtime.UploadScanFailure].
+func WithUploadInspection(opts ...runtime.UploadInspectOption) DefaultRouterOpt {
+	return func(o *defaultRouterOpts) {
+		o.inspectOpts = opts
+	}
+}
+
+// WithImplicitHead routes HEAD requests to the GET operation of a path, when no HEAD operation is declared for it.
+//
+// The body of the response is discarded, but its Content-Length is preserved.
+func WithImplicitHead() DefaultRouterOpt {
+	return func(o *defaultRouterOpts) {
+		o.implicitHead = true
+	}
+}
+
+// WithImplicitOptions answers OPTIONS requests, when no OPTIONS operation is declared for a path,
+// with a 204 and an Allow header listing the methods of the path (RFC 9110, section 9.3.7).
+//
+// CORS preflight requests are answered by the CORS policy of the [Context] instead, if any (see [Context.SetCORS]).
+func WithImplicitOptions() DefaultRouterOpt {
+	return func(o *defaultRouterOpts) {
+		o.implicitOptions = true
+	}
+}
+
+// WithRadixRouter matches paths with a compressed radix tree (see [radix.Tree]), instead of the
+// double array of the [denco.Router].
+//
+// The radix tree is not limited in size, which suits large specs. Both route the same paths.
+//
+// Notice that the router still allocates the [MatchedRoute] of each request and its [RouteParams],
+// whichever the tree: the radix tree is not noticeably faster than the double array once used by the router.
+func WithRadixRouter() DefaultRouterOpt {
+	return func(o *defaultRouterOpts) {
+		o.radix = true
+	}
+}
+
 // DefaultRouter creates a default implementation of the router.
 func DefaultRouter(spec *loads.Document, api RoutableAPI, opts ...DefaultRouterOpt) Router {
 	builder := newDefaultRouteBuilder(spec, api, opts...)
@@ -323,6 +450,8 @@ type routeEntry struct {
 	Binder         *UntypedRequestBinder
 	Authenticators RouteAuthenticators
 	Authorizer     runtime.Authorizer
+
+	deprecation http.Header // see deprecationHeaders
 }
 
 // MatchedRoute represents the route that was matched in this request.
@@ -333,6 +462,8 @@ type MatchedRoute struct {
 	Consumer      runtime.Consumer
 	Producer      runtime.Producer
 	Authenticator *RouteAuthenticator
+
+	implicitHead bool // a HEAD request routed to the GET operation, see WithImplicitHead
 }
 
 // HasAuth returns true when the route has a security requirement defined.
@@ -347,8 +478,24 @@ func (m *MatchedRoute) NeedsAuth() bool {
 }
 
 func (d *defaultRouter) Lookup(method, path string) (*MatchedRoute, bool) {
+	route, ok := d.lookup(method, path)
+	if ok || !d.implicitHead || !strings.EqualFold(method, http.MethodHead) {
+		return route, ok
+	}
+
+	route, ok = d.lookup(http.MethodGet, path)
+	if ok {
+		route.implicitHead = true
+	}
+
+	return route, ok
+}
+
+func (d *defaultRouter) lookup(method, path string) (*MatchedRoute, bool) {
 	mth := strings.ToUpper(method)
-	d.debugLogf("looking up route for %s %s", method, path)
+	if Debug {
+		d.debugLogf("looking up route for %s %s", method, path)
+	}
 	if len(d.routers) == 0 {
 		if Debug {
 			d.debugLogf("there are no known routers")
@@ -364,34 +511,67 @@ func (d *defaultRouter) Lookup(method, path string) (*MatchedRoute, bool) {
 
 	router, ok := d.routers[mth]
 	if !ok {
-		d.debugLogf("couldn't find a route by method for %s %s", method, path)
+		if Debug {
+			d.debugLogf("couldn't find a route by method for %s %s", method, path)
+		}
 		return nil, false
 	}
 
-	m, rp, ok := router.Lookup(fpath.Clean(escapeLiteralColons(path)))
-	if !ok || m == nil {
-		d.debugLogf("couldn't find a route by path for %s %s", method, path)
+	entry, params, ok := d.matchEntry(router, path)
+	if !ok && d.caseInsensitive {
+		if folded, found := d.foldCase(path); found {
+			entry, params, ok = d.matchEntry(router, folded)
+		}
+	}
+	if !ok {
+		if Debug {
+			d.debugLogf("couldn't find a route by path for %s %s", method, path)
+		}
 		return nil, false
 	}
 
+	if Debug {
+		d.debugLogf("found a route for %s %s with %d parameters", method, path, len(entry.Parameters))
+	}
+
+	return &MatchedRoute{routeEntry: *entry, Params: params}, true
+}
+
+// matchEntry matches an (escaped) path with the router of a method, and decodes its parameters.
+func (d *defaultRouter) matchEntry(router pathMatcher, path string) (*routeEntry, RouteParams, bool) {
+	var (
+		m  any
+		rp denco.Params
+		ok bool
+	)
+	cleanPath := fpath.Clean(escapeLiteralColons(path))
+	if pooled, isPooled := router.(pooledPathMatcher); isPooled {
+		buf, _ := routeParamsPool.Get().(*denco.Params)
+		m, rp, ok = pooled.LookupParams(cleanPath, (*buf)[:0])
+		defer func() {
+			*buf = rp[:0]
+			routeParamsPool.Put(buf)
+		}()
+	} else {
+		m, rp, ok = router.Lookup(cleanPath)
+	}
+	if !ok || m == nil {
+		return nil, nil, false
+	}
+
 	entry, ok := m.(*routeEntry)
 	if !ok {
-		return nil, false
+		return nil, nil, false
 	}
 
-	d.debugLogf("found a route for %s %s with %d parameters", method, path, len(entry.Parameters))
-	var params RouteParams
+	params := make(RouteParams, 0, len(rp))
 	for _, p := range rp {
-		v, err := url.PathUnescape(p.Value)
-		if err != nil {
-			d.debugLogf("failed to escape %q: %v", p.Value, err)
-			v = p.Value
-		}
+		v := d.unescapeParam(p.Value)
 
 		// a workaround to handle fragment/composing parameters until they are supported in denco router
 		// check if this parameter is a fragment within a path segment
 		const enclosureSize = 2
-		if xpos := strings.Index(entry.PathPattern, fmt.Sprintf("{%s}", p.Name)) + len(p.Name) + enclosureSize; xpos < len(entry.PathPattern) && entry.PathPattern[xpos] != '/' {
+		if xpos := strings.Index(entry.PathPattern, "{"+p.Name+"}") + len(p.Name) + enclosureSize; xpos < len(entry.PathPattern) && entry.PathPattern[xpos] != '/' {
 			// extract fragment parameters
 			ep := strings.Split(entry.PathPattern[xpos:], "/")[0]
 			pnames, pvalues := decodeCompositParams(p.Name, v, ep, nil, nil)
@@ -404,27 +584,86 @@ func (d *defaultRouter) Lookup(method, path string) (*MatchedRoute, bool) {
 		}
 	}
 
-	return &MatchedRoute{routeEntry: *entry, Params: params}, true
+	return entry, params, true
+}
+
+// unescapeParam decodes the value of a path parameter, keeping encoded slashes with [WithEncodedSlashes].
+func (d *defaultRouter) unescapeParam(value string) string {
+	if d.encodedSlashes && strings.Contains(strings.ToUpper(value), "%2F") {
+		parts := encodedSlash.Split(value, -1)
+		for i, part := range parts {
+			parts[i] = d.unescapeParam(part)
+		}
+
+		return strings.Join(parts, "%2F")
+	}
+
+	v, err := url.PathUnescape(value)
+	if err != nil {
+		d.debugLogf("failed to escape %q: %v", value, err)
+		return value
+	}
+
+	return v
 }
 
 func (d *defaultRouter) OtherMethods(method, path string) []string {
 	mn := strings.ToUpper(method)
 	var methods []string
+	cleanPath := fpath.Clean(escapeLiteralColons(path))
+	var foldedPath string
+	if d.caseInsensitive {
+		if folded, found := d.foldCase(path); found {
+			foldedPath = fpath.Clean(escapeLiteralColons(folded))
+		}
+	}
 	for k, v := range d.routers {
 		if k != mn {
-			if _, _, ok := v.Lookup(fpath.Clean(escapeLiteralColons(path))); ok {
+			if _, _, ok := v.Lookup(cleanPath); ok {
 				methods = append(methods, k)
 				continue
 			}
+			if foldedPath != "" {
+				if _, _, ok := v.Lookup(foldedPath); ok {
+					methods = append(methods, k)
+				}
+			}
 		}
 	}
+
+	if d.implicitHead && mn != http.MethodHead && slices.Contains(methods, http.MethodGet) && !slices.Contains(methods, http.MethodHead) {
+		methods = append(methods, http.MethodHead)
+	}
+	if d.implicitOptions && mn != http.MethodOptions && len(methods) > 0 && !slices.Contains(methods, http.MethodOptions) {
+		methods = append(methods, http.MethodOptions)
+	}
+
 	return methods
 }
 
+func (d *defaultRouter) answersOptions() bool {
+	return d.implicitOptions
+}
+
 func (d *defaultRouter) SetLogger(lg logger.Logger) {
 	d.debugLogf = debugLogfFunc(lg)
 }
 
+// pathMatcher matches the paths of the routes of a method, e.g. a [denco.Router] or a [radix.Tree].
+type pathMatcher interface {
+	Lookup(path string) (data any, params denco.Params, found bool)
+}
+
+// pooledPathMatcher is a pathMatcher which appends the path parameters to a buffer, e.g. a [radix.Tree].
+type pooledPathMatcher interface {
+	LookupParams(path string, params denco.Params) (data any, _ denco.Params, found bool)
+}
+
+// routeParamsPool holds the buffers of the path parameters given to a pooledPathMatcher.
+var routeParamsPool = sync.Pool{
+	New: func() any { return new(denco.Params) },
+}
+
 // convert swagger parameters per path segment into a denco parameter as multiple parameters per segment are not supported in denco.
 var pathConverter = regexp.MustCompile(`{(.+?)}([^/]*)`)
 
@@ -474,6 +713,7 @@ func (d *defaultRouteBuilder) AddRoute(method, path string, operation *spec.Oper
 	d.debugLogf("operation: %#v", *operation)
 	if handler, ok := d.api.HandlerFor(method, strings.TrimPrefix(path, bp)); ok {
 		consumes := d.analyzer.ConsumesFor(operation)
+		specConsumes := slices.Clone(consumes)
 		produces := d.analyzer.ProducesFor(operation)
 		parameters := d.analyzer.ParamsFor(method, strings.TrimPrefix(path, bp))
 
@@ -488,7 +728,13 @@ func (d *defaultRouteBuilder) AddRoute(method, path string, operation *spec.Oper
 
 		requestBinder := NewUntypedRequestBinder(parameters, d.spec.Spec(), d.api.Formats())
 		requestBinder.setDebugLogf(d.debugLogf)
-		record := denco.NewRecord(pathConverter.ReplaceAllString(escapeLiteralColons(path), ":$1"), &routeEntry{
+		if streamUpload, _ := operation.Extensions.GetBool(StreamUploadExtension); streamUpload {
+			requestBinder.setStreamUpload(d.streamOpts)
+		}
+		if inspector := d.uploadInspector(parameters, specConsumes); inspector != nil {
+			requestBinder.setUploadInspector(inspector)
+		}
+		entry := &routeEntry{
 			BasePath:       bp,
 			PathPattern:    path,
 			Operation:      operation,
@@ -502,22 +748,86 @@ func (d *defaultRouteBuilder) AddRoute(method, path string, operation *spec.Oper
 			Binder:         requestBinder,
 			Authenticators: d.buildAuthenticators(operation),
 			Authorizer:     d.api.Authorizer(),
-		})
+			deprecation:    deprecationHeaders(operation, d.deprecationDate, d.debugLogf),
+		}
+		record := denco.NewConstrainedRecord(
+			pathConverter.ReplaceAllString(escapeLiteralColons(path), ":$1"), entry,
+			pathConstraints(path, parameters, d.api.Formats()),
+		)
 		d.records[mn] = append(d.records[mn], record)
+		d.routes = append(d.routes, describeRoute(mn, entry, d.api, strings.TrimPrefix(path, bp)))
+
+		// the route is described with the handler of the operation, before it is wrapped
+		entry.Handler = d.operationMiddlewares.wrap(operation, entry.Handler)
 	}
 }
 
+// uploadInspector builds the inspector of the files uploaded to an operation, if any.
+func (d *defaultRouteBuilder) uploadInspector(parameters map[string]spec.Parameter, consumes []string) *runtime.UploadInspector {
+	if len(d.inspectOpts) == 0 {
+		return nil
+	}
+
+	hasFiles := false
+	for _, param := range parameters {
+		if param.In == "formData" && param.Type == "file" {
+			hasFiles = true
+
+			break
+		}
+	}
+	if !hasFiles {
+		return nil
+	}
+
+	allowed := slices.DeleteFunc(consumes, func(mediaType string) bool {
+		mt, _, err := mime.ParseMediaType(mediaType)
+
+		return err != nil || mt == runtime.MultipartFormMime || mt == runtime.URLencodedFormMime
+	})
+	if len(allowed) == 0 {
+		return runtime.NewUploadInspector(d.inspectOpts...)
+	}
+
+	return runtime.NewUploadInspector(append(slices.Clone(d.inspectOpts), runtime.InspectAllowedTypes(allowed...))...)
+}
+
 func (d *defaultRouteBuilder) Build() *defaultRouter {
-	routers := make(map[string]*denco.Router)
+	routers := make(map[string]pathMatcher)
 	for method, records := range d.records {
+		// records are sorted so that routes with the same structure are tried in a stable order.
+		// Duplicate routes keep the order they were added in: the last one wins, as when they were not sorted.
+		slices.SortStableFunc(records, func(a, b denco.Record) int { return strings.Compare(a.Key, b.Key) })
+		if d.radix {
+			tree := radix.New()
+			_ = tree.Build(records)
+			routers[method] = tree
+
+			continue
+		}
+
 		router := denco.New()
 		_ = router.Build(records)
 		routers[method] = router
 	}
+	sortRoutes(d.routes)
+
+	var folded *foldedRoutes
+	if d.caseInsensitive {
+		folded = newFoldedRoutes(d.routes)
+	}
+
 	return &defaultRouter{
 		spec:      d.spec,
 		routers:   routers,
+		routes:    d.routes,
 		debugLogf: d.debugLogf,
+
+		implicitHead:    d.implicitHead,
+		implicitOptions: d.implicitOptions,
+		caseInsensitive: d.caseInsensitive,
+		encodedSlashes:  d.encodedSlashes,
+		folded:          folded,
 	}
 }
 
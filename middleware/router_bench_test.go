@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/internal/testing/petstore"
+	"github.com/go-openapi/runtime/middleware/untyped"
+)
+
+func BenchmarkDefaultRouter_Petstore(b *testing.B) {
+	spec, api := petstore.NewAPI(b)
+
+	benchmarkRouters(b, spec, api, []string{"/api/pets", "/api/pets/42"})
+}
+
+func BenchmarkDefaultRouter_LargeSpec(b *testing.B) {
+	spec, api := largeSpecAPI(b, 2000)
+
+	benchmarkRouters(b, spec, api, []string{"/api/resource1000", "/api/resource1000/42/children/7"})
+}
+
+func benchmarkRouters(b *testing.B, spec *loads.Document, api *untyped.API, paths []string) {
+	b.Helper()
+	routable := NewContext(spec, api, nil).api
+
+	for _, router := range []struct {
+		name string
+		opts []DefaultRouterOpt
+	}{
+		{name: "denco"},
+		{name: "radix", opts: []DefaultRouterOpt{WithRadixRouter()}},
+	} {
+		opts := append([]DefaultRouterOpt{WithDefaultRouterLoggerFunc(func(string, ...any) {})}, router.opts...)
+		r := DefaultRouter(spec, routable, opts...)
+
+		for _, path := range paths {
+			b.Run(router.name+path, func(b *testing.B) {
+				b.ReportAllocs()
+				for range b.N {
+					if _, ok := r.Lookup(http.MethodGet, path); !ok {
+						b.Fail()
+					}
+				}
+			})
+		}
+	}
+}
+
+// largeSpecAPI builds an API with n resources, each with a list, a get and a nested get operation.
+func largeSpecAPI(tb testing.TB, n int) (*loads.Document, *untyped.API) {
+	tb.Helper()
+
+	responses := map[string]any{"200": map[string]any{"description": "ok"}}
+	pathParam := func(name string) map[string]any {
+		return map[string]any{"name": name, "in": "path", "required": true, "type": "integer"}
+	}
+	paths := make(map[string]any, 3*n)
+	for i := range n {
+		resource := fmt.Sprintf("/resource%d", i)
+		paths[resource] = map[string]any{"get": map[string]any{
+			"operationId": fmt.Sprintf("list%d", i), "responses": responses,
+		}}
+		paths[resource+"/{id}"] = map[string]any{"get": map[string]any{
+			"operationId": fmt.Sprintf("get%d", i), "responses": responses,
+			"parameters": []any{pathParam("id")},
+		}}
+		paths[resource+"/{id}/children/{child}"] = map[string]any{"get": map[string]any{
+			"operationId": fmt.Sprintf("getChild%d", i), "responses": responses,
+			"parameters": []any{pathParam("id"), pathParam("child")},
+		}}
+	}
+
+	raw, err := json.Marshal(map[string]any{
+		"swagger":  "2.0",
+		"info":     map[string]any{"title": "large", "version": "1.0"},
+		"basePath": "/api",
+		"produces": []string{runtime.JSONMime},
+		"paths":    paths,
+	})
+	require.NoError(tb, err)
+
+	doc, err := loads.Analyzed(raw, "")
+	require.NoError(tb, err)
+
+	api := untyped.NewAPI(doc)
+	handler := runtime.OperationHandlerFunc(func(any) (any, error) { return nil, nil })
+	for path := range paths {
+		api.RegisterOperation("get", path, handler)
+	}
+
+	return doc, api
+}
+
+func TestRadixRouter(t *testing.T) {
+	spec, api := largeSpecAPI(t, 50)
+	routable := NewContext(spec, api, nil).api
+	dencoRouter := DefaultRouter(spec, routable)
+	radixRouter := DefaultRouter(spec, routable, WithRadixRouter())
+
+	for _, path := range []string{"/api/resource10", "/api/resource10/42", "/api/resource49/42/children/7", "/api/resource50/42", "/api/resource10/42/children"} {
+		expected, expectedOK := dencoRouter.Lookup(http.MethodGet, path)
+		route, ok := radixRouter.Lookup(http.MethodGet, path)
+		require.EqualT(t, expectedOK, ok, path)
+		if !ok {
+			continue
+		}
+		require.EqualT(t, expected.Operation.ID, route.Operation.ID, path)
+		require.Equal(t, expected.Params, route.Params, path)
+	}
+
+	require.ElementsMatch(t,
+		dencoRouter.OtherMethods(http.MethodPost, "/api/resource10/42"),
+		radixRouter.OtherMethods(http.MethodPost, "/api/resource10/42"),
+	)
+}
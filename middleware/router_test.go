@@ -203,6 +203,29 @@ func TestRouterStruct(t *testing.T) {
 	assert.FalseT(t, ok)
 }
 
+func TestRouterBuilder_DuplicateRoutes(t *testing.T) {
+	spec, api := petstore.NewAPI(t)
+	analyzed := analysis.New(spec.Spec())
+
+	for _, radix := range []bool{false, true} {
+		builder := petAPIRouterBuilder(spec, api, analyzed)
+		builder.radix = radix
+		// the same route is added again, for another operation
+		builder.AddRoute(http.MethodGet, "/pets", analyzed.AllPaths()["/pets/{id}"].Get)
+		builder.AddRoute(http.MethodGet, "/pets/{id}", analyzed.AllPaths()["/pets"].Get)
+		router := builder.Build()
+
+		// the last route added wins
+		route, ok := router.Lookup(http.MethodGet, "/pets")
+		require.TrueT(t, ok)
+		assert.EqualT(t, analyzed.AllPaths()["/pets/{id}"].Get.ID, route.Operation.ID)
+
+		route, ok = router.Lookup(http.MethodGet, "/pets/1")
+		require.TrueT(t, ok)
+		assert.EqualT(t, analyzed.AllPaths()["/pets"].Get.ID, route.Operation.ID)
+	}
+}
+
 func petAPIRouterBuilder(spec *loads.Document, api *untyped.API, analyzed *analysis.Spec) *defaultRouteBuilder {
 	builder := newDefaultRouteBuilder(spec, newRoutableUntypedAPI(spec, api, new(Context)))
 	builder.AddRoute(http.MethodGet, "/pets", analyzed.AllPaths()["/pets"].Get)
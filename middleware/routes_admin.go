@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/server-middleware/negotiate"
+)
+
+const routesAdminTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Routes</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; vertical-align: top; }
+code { white-space: nowrap; }
+</style>
+</head>
+<body>
+<form method="get">
+<input name="method" value="{{ with .Explanation }}{{ .Method }}{{ else }}GET{{ end }}" size="8">
+<input name="path" value="{{ with .Explanation }}{{ .Path }}{{ end }}" size="60">
+<button type="submit">Explain</button>
+</form>
+{{ with .Explanation }}
+<h2>{{ .Method }} {{ .Path }}: {{ .Outcome }}</h2>
+<p>{{ .Reason }}</p>
+{{ with .Params }}<p>Parameters: {{ range . }}<code>{{ .Name }}={{ .Value }}</code> {{ end }}</p>{{ end }}
+{{ with .AllowedMethods }}<p>Allowed methods: {{ range . }}<code>{{ . }}</code> {{ end }}</p>{{ end }}
+{{ with .Candidates }}<p>Candidates: {{ range . }}<code>{{ . }}</code> {{ end }}</p>{{ end }}
+{{ end }}
+<h2>Routes</h2>
+<table>
+<tr><th>Method</th><th>Path</th><th>Operation</th><th>Consumes</th><th>Produces</th><th>Security</th><th>Handler</th></tr>
+{{ range .Routes }}<tr>
+<td>{{ .Method }}</td>
+<td><code>{{ .PathPattern }}</code></td>
+<td>{{ .OperationID }}</td>
+<td>{{ range .Consumes }}{{ . }}<br>{{ end }}</td>
+<td>{{ range .Produces }}{{ . }}<br>{{ end }}</td>
+<td>{{ range .Security }}{{ range $scheme, $scopes := . }}{{ $scheme }}{{ with $scopes }} {{ . }}{{ end }} {{ else }}anonymous{{ end }}<br>{{ end }}</td>
+<td><code>{{ .Handler }}</code></td>
+</tr>{{ end }}
+</table>
+</body>
+</html>
+`
+
+var routesAdminPage = template.Must(template.New("routes").Parse(routesAdminTemplate))
+
+type routesAdminData struct {
+	Routes      []RouteDescription `json:"routes,omitempty"`
+	Explanation *RouteExplanation  `json:"explanation,omitempty"`
+}
+
+// RoutesAdminHandler returns a handler which lists the routes served by an API (e.g. a [Context]),
+// as JSON or HTML depending on the Accept header of requests.
+//
+// With the method and path query parameters (e.g. ?method=GET&path=/api/pets/1), it explains
+// how such a request is routed instead.
+//
+// The handler exposes the internals of the API: mount it on an admin endpoint, not on the public one.
+func RoutesAdminHandler(routes RouteExplainer) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		var data routesAdminData
+		query := r.URL.Query()
+		if path := query.Get("path"); path != "" {
+			method := query.Get("method")
+			if method == "" {
+				method = http.MethodGet
+			}
+			explanation := routes.Explain(method, path)
+			data.Explanation = &explanation
+		}
+
+		format := negotiate.ContentType(r, []string{runtime.JSONMime, runtime.HTMLMime}, runtime.JSONMime)
+		rw.Header().Set("Cache-Control", "no-store")
+
+		if format == runtime.HTMLMime {
+			data.Routes = routes.Routes()
+			rw.Header().Set(runtime.HeaderContentType, runtime.HTMLMime+"; charset=utf-8")
+			rw.WriteHeader(http.StatusOK)
+			_ = routesAdminPage.Execute(rw, data)
+
+			return
+		}
+
+		rw.Header().Set(runtime.HeaderContentType, runtime.JSONMime)
+		rw.WriteHeader(http.StatusOK)
+		if data.Explanation != nil {
+			_ = json.NewEncoder(rw).Encode(data.Explanation)
+
+			return
+		}
+
+		_ = json.NewEncoder(rw).Encode(routes.Routes())
+	})
+}
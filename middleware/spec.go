@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	yaml "go.yaml.in/yaml/v3"
+
+	"github.com/go-openapi/runtime/server-middleware/docui"
+)
+
+// SetSpecOptions sets extra options for the spec document served by [Context.APIHandler]
+// and [Context.APIHandlerWithUI], e.g. a filtered view with [docui.WithSpecFilter].
+//
+// The spec document is served as JSON. With [docui.WithSpecYAML] and [JSONToYAML], it is also
+// served as YAML when negotiated by the Accept header or requested with a ".yaml" extension
+// (e.g. "/swagger.yaml").
+//
+// Returns the receiver for fluent configuration:
+//
+//	ctx := middleware.NewContext(spec, api, nil).SetSpecOptions(
+//		docui.WithSpecFilter(func(*http.Request) docui.SpecFilter {
+//			return docui.SpecFilter{ExcludeInternal: true}
+//		}),
+//	)
+func (c *Context) SetSpecOptions(opts ...docui.SpecOption) *Context {
+	c.specOptions = opts
+
+	return c
+}
+
+// JSONToYAML converts a JSON spec document to YAML, retaining the order of keys.
+// Strings keep their quotes when they would otherwise read back as other types.
+//
+// This may be used to serve a spec as YAML with [docui.WithSpecYAML].
+func JSONToYAML(doc []byte) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(doc, &node); err != nil {
+		return nil, fmt.Errorf("invalid JSON document: %w", err)
+	}
+	blockStyle(&node)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2) //nolint:mnd // conventional indentation for OpenAPI documents
+	if err := enc.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// yaml11Literals are plain scalars which YAML 1.1 parsers don't read as strings.
+var yaml11Literals = []string{"y", "yes", "n", "no", "on", "off", "true", "false", "null", "~"}
+
+// yaml11Scalars matches the plain numbers and timestamps of YAML 1.1, which YAML 1.2 reads as strings.
+var yaml11Scalars = regexp.MustCompile(
+	`^[-+]?(0b[01_]+|0[0-7_]+|0x[0-9a-fA-F_]+|[0-9][0-9_]*(:[0-5]?[0-9])*(\.[0-9_]*)?([eE][-+]?[0-9]+)?|\.[0-9_]+([eE][-+]?[0-9]+)?)$` +
+		`|^[0-9]{4}-[0-9]{1,2}-[0-9]{1,2}`,
+)
+
+// blockStyle resets the flow style and quotes inherited from JSON, so the encoder renders idiomatic YAML.
+//
+// Strings which would not read back as the same strings without their quotes keep them,
+// including with YAML 1.1 parsers (e.g. "yes", "on" or "1.0").
+func blockStyle(node *yaml.Node) {
+	if node.Kind != yaml.ScalarNode || !ambiguousString(node) {
+		node.Style = 0
+	}
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// ambiguousString tells if a string scalar must be quoted.
+func ambiguousString(node *yaml.Node) bool {
+	if node.ShortTag() != "!!str" {
+		return false
+	}
+
+	value := node.Value
+	if value == "" || slices.Contains(yaml11Literals, strings.ToLower(value)) || yaml11Scalars.MatchString(value) {
+		return true
+	}
+
+	var plain any
+	if err := yaml.Unmarshal([]byte(value), &plain); err != nil {
+		return true
+	}
+	decoded, isString := plain.(string)
+
+	return !isString || decoded != value
+}
@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	stdcontext "context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/runtime/internal/testing/petstore"
+	"github.com/go-openapi/runtime/server-middleware/docui"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestJSONToYAML(t *testing.T) {
+	doc, err := JSONToYAML([]byte(`{"swagger":"2.0","info":{"version":"1.0","title":"T"},"tags":["b","a"],"x-count":3}`))
+	require.NoError(t, err)
+
+	assert.EqualT(t, `swagger: "2.0"
+info:
+  version: "1.0"
+  title: T
+tags:
+  - b
+  - a
+x-count: 3
+`, string(doc))
+
+	// strings which would not read back as strings keep their quotes
+	doc, err = JSONToYAML([]byte(`{"enum":["yes","On","n","null","","1e3","-1","true","0x1F","1:20","1_000","2026-01-31","3.0.3","plain text"],"yes":false,"x-ratio":1.5}`))
+	require.NoError(t, err)
+
+	assert.EqualT(t, `enum:
+  - "yes"
+  - "On"
+  - "n"
+  - "null"
+  - ""
+  - "1e3"
+  - "-1"
+  - "true"
+  - "0x1F"
+  - "1:20"
+  - "1_000"
+  - "2026-01-31"
+  - 3.0.3
+  - plain text
+"yes": false
+x-ratio: 1.5
+`, string(doc))
+
+	_, err = JSONToYAML([]byte(`{`))
+	require.Error(t, err)
+}
+
+func TestContextSpecDocument(t *testing.T) {
+	spec, api := petstore.NewAPI(t)
+
+	serve := func(handler http.Handler, pth, accept string) *httptest.ResponseRecorder {
+		request := httptest.NewRequestWithContext(stdcontext.Background(), http.MethodGet, pth, nil)
+		if accept != "" {
+			request.Header.Set("Accept", accept)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		return recorder
+	}
+
+	t.Run("should serve the spec as JSON only by default", func(t *testing.T) {
+		handler := NewContext(spec, api, nil).APIHandler(nil)
+
+		recorder := serve(handler, "/swagger.json", "application/yaml")
+		require.EqualT(t, http.StatusOK, recorder.Code)
+		assert.JSONEqT(t, string(spec.Raw()), recorder.Body.String())
+		assert.NotEqual(t, http.StatusOK, serve(handler, "/swagger.yaml", "").Code)
+	})
+
+	t.Run("should serve the spec as YAML", func(t *testing.T) {
+		handler := NewContext(spec, api, nil).SetSpecOptions(docui.WithSpecYAML(JSONToYAML)).APIHandler(nil)
+
+		for _, recorder := range []*httptest.ResponseRecorder{
+			serve(handler, "/swagger.yaml", ""),
+			serve(handler, "/swagger.json", "application/yaml"),
+		} {
+			require.EqualT(t, http.StatusOK, recorder.Code)
+			assert.EqualT(t, "application/yaml", recorder.Header().Get("Content-Type"))
+			assert.StringContainsT(t, recorder.Body.String(), "swagger: \"2.0\"\n")
+			assert.NotEmpty(t, recorder.Header().Get("ETag"))
+		}
+
+		recorder := serve(handler, "/swagger.json", "")
+		require.EqualT(t, http.StatusOK, recorder.Code)
+		assert.JSONEqT(t, string(spec.Raw()), recorder.Body.String())
+	})
+
+	t.Run("should serve a filtered view of the spec", func(t *testing.T) {
+		handler := NewContext(spec, api, nil).SetSpecOptions(
+			docui.WithSpecFilter(func(*http.Request) docui.SpecFilter {
+				return docui.SpecFilter{Tags: []string{}}
+			}),
+		).APIHandler(nil)
+
+		recorder := serve(handler, "/swagger.json", "")
+		require.EqualT(t, http.StatusOK, recorder.Code)
+		assert.StringNotContainsT(t, recorder.Body.String(), "/pets")
+
+		// the API itself is not affected
+		assert.NotEqual(t, http.StatusNotFound, serve(handler, "/api/pets", "application/json").Code)
+	})
+	t.Run("should serve an OpenAPI 3 rendering of the spec", func(t *testing.T) {
+		handler := NewContext(spec, api, nil).SetSpecOptions(
+			docui.WithSpecOpenAPI3(true),
+			docui.WithSpecYAML(JSONToYAML),
+		).APIHandler(nil)
+
+		recorder := serve(handler, "/openapi.json", "")
+		require.EqualT(t, http.StatusOK, recorder.Code)
+		assert.StringContainsT(t, recorder.Body.String(), `"openapi":"3.0.3"`)
+		assert.StringContainsT(t, recorder.Body.String(), `"#/components/schemas/`)
+
+		recorder = serve(handler, "/openapi.yaml", "")
+		require.EqualT(t, http.StatusOK, recorder.Code)
+		assert.StringContainsT(t, recorder.Body.String(), "openapi: 3.0.3\n")
+	})
+}
@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	stderrors "errors"
+	"net/http"
+	"reflect"
+	"slices"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+)
+
+// StreamUploadExtension is the vendor extension by which an operation opts into streaming
+// its multipart/form-data uploads:
+//
+//	consumes:
+//	  - multipart/form-data
+//	x-stream-upload: true
+//
+// Instead of parsing the whole form into memory or temporary files, the binder reads the
+// ordinary form fields which precede the first file, binds and validates them against the spec,
+// then hands the operation a [*runtime.MultipartFormStream] in place of each file parameter.
+//
+// The stream only accepts the file parameters declared by the operation, and reports missing
+// required files as parts arrive. Form fields sent after a file are not bound: they are
+// available from the request once the handler has consumed the preceding files.
+//
+// The stream is closed after the operation is handled. Its options are set with
+// [WithMultipartStreamOptions].
+const StreamUploadExtension = "x-stream-upload"
+
+var multipartStreamType = reflect.TypeFor[*runtime.MultipartFormStream]()
+
+func (o *UntypedRequestBinder) setStreamUpload(opts []runtime.MultipartFormStreamOption) {
+	o.streamUpload = true
+	o.streamOpts = opts
+}
+
+// setUploadInspector inspects the files uploaded to the operation (see WithUploadInspection).
+func (o *UntypedRequestBinder) setUploadInspector(inspector *runtime.UploadInspector) {
+	o.inspector = inspector
+	for _, binder := range o.paramBinders {
+		binder.inspector = inspector
+	}
+}
+
+// streamsUpload tells if the request is a multipart upload to be streamed.
+func (o *UntypedRequestBinder) streamsUpload(request *http.Request) bool {
+	if !o.streamUpload || !runtime.HasBody(request) {
+		return false
+	}
+
+	mt, _, err := runtime.ContentType(request.Header)
+
+	return err == nil && mt == runtime.MultipartFormMime
+}
+
+// openStream opens the multipart stream of an upload, and reads the form fields which precede the first file.
+func (o *UntypedRequestBinder) openStream(request *http.Request) (*runtime.MultipartFormStream, error) {
+	opts := slices.Clone(o.streamOpts)
+	if o.inspector != nil {
+		opts = append(opts, runtime.MultipartFormStreamInspect(o.inspector))
+	}
+	for _, param := range o.Parameters {
+		if param.In == "formData" && param.Type == "file" {
+			opts = append(opts, runtime.MultipartFormStreamFile(param.Name, param.Required))
+		}
+	}
+
+	stream, err := runtime.NewMultipartFormStream(request, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := stream.ReadFields(); err != nil {
+		var parseErr *errors.ParseError
+		if stderrors.As(err, &parseErr) && stderrors.Is(parseErr.Reason, http.ErrMissingFile) {
+			return nil, errors.Required(parseErr.Name, parseErr.In, nil)
+		}
+
+		return nil, err
+	}
+
+	return stream, nil
+}
+
+// bindStreamed binds a formData parameter of a streamed upload: file parameters get the stream,
+// other parameters get the form fields read so far.
+func (p *untypedParamBinder) bindStreamed(request *http.Request, stream *runtime.MultipartFormStream, target reflect.Value) error {
+	if p.parameter.Type == "file" {
+		if !multipartStreamType.AssignableTo(target.Type()) {
+			return errors.New(http.StatusInternalServerError, "cannot bind a multipart stream to parameter %q of type %v", p.Name, target.Type())
+		}
+		target.Set(reflect.ValueOf(stream))
+
+		return nil
+	}
+
+	data, custom, hasKey, err := p.readValue(runtime.Values(request.PostForm), target)
+	if err != nil {
+		return err
+	}
+	if custom {
+		return nil
+	}
+
+	return p.bindValue(data, hasKey, target)
+}
+
+// closeStreams closes the multipart stream bound for an operation, if any.
+func closeStreams(bound any) {
+	params, ok := bound.(map[string]any)
+	if !ok {
+		return
+	}
+
+	for _, value := range params {
+		if stream, isStream := value.(*runtime.MultipartFormStream); isStream {
+			_ = stream.Close()
+
+			return
+		}
+	}
+}
@@ -0,0 +1,336 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"bytes"
+	stdcontext "context"
+	"crypto"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/middleware/untyped"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+const streamUploadTestSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "uploads", "version": "1.0"},
+  "produces": ["application/json"],
+  "paths": {
+    "/uploads": {
+      "post": {
+        "operationId": "upload",
+        "consumes": ["multipart/form-data"],
+        "x-stream-upload": true,
+        "parameters": [
+          {"name": "title", "in": "formData", "type": "string", "required": true, "maxLength": 10},
+          {"name": "count", "in": "formData", "type": "integer"},
+          {"name": "document", "in": "formData", "type": "file", "required": true},
+          {"name": "attachment", "in": "formData", "type": "file"}
+        ],
+        "responses": {"200": {"description": "uploaded"}}
+      }
+    },
+    "/forms": {
+      "post": {
+        "operationId": "submitForm",
+        "consumes": ["multipart/form-data"],
+        "parameters": [
+          {"name": "title", "in": "formData", "type": "string", "required": true, "maxLength": 10},
+          {"name": "document", "in": "formData", "type": "file", "required": true}
+        ],
+        "responses": {"200": {"description": "submitted"}}
+      }
+    }
+  }
+}`
+
+type streamUploadPart struct {
+	name, filename, content string
+}
+
+func streamUploadRequest(t *testing.T, parts ...streamUploadPart) *http.Request {
+	t.Helper()
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	for _, part := range parts {
+		if part.filename == "" {
+			require.NoError(t, writer.WriteField(part.name, part.content))
+
+			continue
+		}
+
+		file, err := writer.CreateFormFile(part.name, part.filename)
+		require.NoError(t, err)
+		_, err = io.WriteString(file, part.content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	request := httptest.NewRequestWithContext(stdcontext.Background(), http.MethodPost, "/uploads", body)
+	request.Header.Set(runtime.HeaderContentType, writer.FormDataContentType())
+
+	return request
+}
+
+func TestStreamUpload(t *testing.T) {
+	doc, err := loads.Analyzed(json.RawMessage(streamUploadTestSpec), "")
+	require.NoError(t, err)
+
+	api := untyped.NewAPI(doc)
+	api.RegisterConsumer(runtime.MultipartFormMime, runtime.DiscardConsumer)
+	api.RegisterOperation("post", "/uploads", runtime.OperationHandlerFunc(func(params any) (any, error) {
+		bound, _ := params.(map[string]any)
+		stream, ok := bound["document"].(*runtime.MultipartFormStream)
+		if !ok {
+			return nil, io.ErrUnexpectedEOF
+		}
+		assert.True(t, bound["attachment"] == stream)
+
+		result := map[string]any{"title": bound["title"], "count": bound["count"]}
+		for {
+			file, err := stream.NextFile()
+			if stderrors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			content, err := io.ReadAll(file)
+			if err != nil {
+				return nil, err
+			}
+			result[file.FieldName] = string(content)
+		}
+
+		return result, nil
+	}))
+	api.RegisterOperation("post", "/forms", runtime.OperationHandlerFunc(func(params any) (any, error) {
+		bound, _ := params.(map[string]any)
+
+		return map[string]any{"title": bound["title"]}, nil
+	}))
+	handler := NewContext(doc, api, nil).APIHandler(nil)
+
+	serve := func(request *http.Request) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		return recorder
+	}
+
+	t.Run("should bind the fields preceding files and stream the files", func(t *testing.T) {
+		recorder := serve(streamUploadRequest(t,
+			streamUploadPart{name: "title", content: "report"},
+			streamUploadPart{name: "count", content: "2"},
+			streamUploadPart{name: "document", filename: "report.pdf", content: "PDF"},
+			streamUploadPart{name: "attachment", filename: "notes.txt", content: "notes"},
+		))
+
+		require.EqualT(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		assert.JSONEqT(t, `{"title":"report","count":2,"document":"PDF","attachment":"notes"}`, recorder.Body.String())
+	})
+
+	t.Run("should validate the fields preceding files", func(t *testing.T) {
+		recorder := serve(streamUploadRequest(t,
+			streamUploadPart{name: "title", content: "a title which is too long"},
+			streamUploadPart{name: "document", filename: "report.pdf", content: "PDF"},
+		))
+
+		assert.EqualT(t, http.StatusUnprocessableEntity, recorder.Code)
+		assert.StringContainsT(t, recorder.Body.String(), "title")
+	})
+
+	t.Run("should not bind fields sent after files", func(t *testing.T) {
+		recorder := serve(streamUploadRequest(t,
+			streamUploadPart{name: "document", filename: "report.pdf", content: "PDF"},
+			streamUploadPart{name: "title", content: "report"},
+		))
+
+		assert.EqualT(t, http.StatusUnprocessableEntity, recorder.Code)
+		assert.StringContainsT(t, recorder.Body.String(), "title")
+	})
+
+	t.Run("should require declared files", func(t *testing.T) {
+		recorder := serve(streamUploadRequest(t,
+			streamUploadPart{name: "title", content: "report"},
+		))
+
+		assert.EqualT(t, http.StatusUnprocessableEntity, recorder.Code)
+		assert.StringContainsT(t, recorder.Body.String(), "document")
+	})
+
+	t.Run("should reject undeclared files", func(t *testing.T) {
+		recorder := serve(streamUploadRequest(t,
+			streamUploadPart{name: "title", content: "report"},
+			streamUploadPart{name: "other", filename: "other.txt", content: "other"},
+		))
+
+		assert.EqualT(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("should report required files missing after the first one", func(t *testing.T) {
+		recorder := serve(streamUploadRequest(t,
+			streamUploadPart{name: "title", content: "report"},
+			streamUploadPart{name: "attachment", filename: "notes.txt", content: "notes"},
+		))
+
+		assert.EqualT(t, http.StatusBadRequest, recorder.Code)
+		assert.StringContainsT(t, recorder.Body.String(), "document")
+	})
+
+	t.Run("should not change the errors of operations which are not streamed", func(t *testing.T) {
+		form := func(parts ...streamUploadPart) *http.Request {
+			request := streamUploadRequest(t, parts...)
+			request.URL.Path = "/forms"
+
+			return request
+		}
+
+		recorder := serve(form(
+			streamUploadPart{name: "title", content: "a title which is too long"},
+			streamUploadPart{name: "document", filename: "report.pdf", content: "PDF"},
+		))
+		assert.EqualT(t, http.StatusUnprocessableEntity, recorder.Code)
+
+		// only validation errors are reported: parse errors, e.g. of files, are left to the handler
+		for _, parts := range [][]streamUploadPart{
+			{{name: "title", content: "report"}},
+			{{name: "title", content: "report"}, {name: "other", filename: "other.txt", content: "other"}},
+		} {
+			recorder = serve(form(parts...))
+			assert.EqualT(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		}
+	})
+}
+
+const uploadInspectionTestSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "uploads", "version": "1.0"},
+  "produces": ["application/json"],
+  "paths": {
+    "/photos": {
+      "post": {
+        "operationId": "uploadPhoto",
+        "consumes": ["multipart/form-data", "image/png"],
+        "parameters": [
+          {"name": "document", "in": "formData", "type": "file", "required": true}
+        ],
+        "responses": {"200": {"description": "uploaded"}}
+      }
+    },
+    "/uploads": {
+      "post": {
+        "operationId": "upload",
+        "consumes": ["multipart/form-data"],
+        "x-stream-upload": true,
+        "parameters": [
+          {"name": "document", "in": "formData", "type": "file", "required": true}
+        ],
+        "responses": {"200": {"description": "uploaded"}}
+      }
+    }
+  }
+}`
+
+func TestUploadInspection(t *testing.T) {
+	doc, err := loads.Analyzed(json.RawMessage(uploadInspectionTestSpec), "")
+	require.NoError(t, err)
+
+	api := untyped.NewAPI(doc)
+	api.RegisterConsumer(runtime.MultipartFormMime, runtime.DiscardConsumer)
+	api.RegisterOperation("post", "/photos", runtime.OperationHandlerFunc(func(params any) (any, error) {
+		bound, _ := params.(map[string]any)
+		file, _ := bound["document"].(runtime.File)
+
+		return map[string]any{
+			"type":   file.Header.Header.Get(runtime.HeaderContentType),
+			"digest": file.Header.Header.Get(runtime.HeaderContentDigest),
+		}, nil
+	}))
+	api.RegisterOperation("post", "/uploads", runtime.OperationHandlerFunc(func(params any) (any, error) {
+		bound, _ := params.(map[string]any)
+		stream, _ := bound["document"].(*runtime.MultipartFormStream)
+
+		file, err := stream.NextFile()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.Copy(io.Discard, file); err != nil {
+			return nil, err
+		}
+		inspection, _ := file.Inspection()
+
+		return map[string]any{"type": inspection.ContentType}, nil
+	}))
+
+	handler := NewContext(doc, api, nil).SetDefaultRouterOptions(
+		WithUploadInspection(
+			runtime.InspectDigest(crypto.SHA256),
+			runtime.InspectScanner(runtime.UploadScannerFunc(func(_ stdcontext.Context, r io.Reader) error {
+				content, err := io.ReadAll(r)
+				switch {
+				case err != nil:
+					return err
+				case bytes.Contains(content, []byte("EICAR")):
+					return runtime.ErrUploadInfected
+				case bytes.Contains(content, []byte("UNAVAILABLE")):
+					return fmt.Errorf("%w: antivirus unreachable", runtime.ErrUploadScanFailed)
+				}
+
+				return err
+			})),
+		),
+	).APIHandler(nil)
+
+	serve := func(pth, content string) *httptest.ResponseRecorder {
+		request := streamUploadRequest(t, streamUploadPart{name: "document", filename: "upload.bin", content: content})
+		request.URL.Path = pth
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		return recorder
+	}
+
+	const png = "\x89PNG\r\n\x1a\nimage"
+
+	t.Run("should inspect bound files", func(t *testing.T) {
+		recorder := serve("/photos", png)
+		require.EqualT(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		assert.StringContainsT(t, recorder.Body.String(), `"type":"image/png"`)
+		assert.StringContainsT(t, recorder.Body.String(), `"digest":"sha-256=:`)
+	})
+
+	t.Run("should restrict the types of files to the consumes of the operation", func(t *testing.T) {
+		assert.EqualT(t, http.StatusUnsupportedMediaType, serve("/photos", "plain text").Code)
+	})
+
+	t.Run("should inspect streamed files", func(t *testing.T) {
+		recorder := serve("/uploads", "plain text")
+		require.EqualT(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		assert.StringContainsT(t, recorder.Body.String(), `"type":"text/plain; charset=utf-8"`)
+	})
+
+	t.Run("should reject files refused by the scanner", func(t *testing.T) {
+		assert.EqualT(t, http.StatusUnprocessableEntity, serve("/uploads", "EICAR").Code)
+		assert.EqualT(t, http.StatusUnprocessableEntity, serve("/photos", png+"EICAR").Code)
+	})
+
+	t.Run("should fail when the scanner fails", func(t *testing.T) {
+		assert.EqualT(t, http.StatusServiceUnavailable, serve("/uploads", "UNAVAILABLE").Code)
+		assert.EqualT(t, http.StatusServiceUnavailable, serve("/photos", png+"UNAVAILABLE").Code)
+	})
+}
@@ -79,14 +79,40 @@ func (v *validation) parameters() {
 		return
 	}
 
+	streamed := v.route.Binder.streamsUpload(v.request)
 	for _, e := range result.Errors {
 		var validationErr *errors.Validation
 		if stderrors.As(e, &validationErr) {
 			v.result = append(v.result, validationErr)
+
+			continue
+		}
+
+		if isUploadInspectionError(e) {
+			v.result = append(v.result, e)
+
+			continue
+		}
+
+		// errors of the multipart stream, e.g. undeclared files
+		var apiErr errors.Error
+		if streamed && stderrors.As(e, &apiErr) && apiErr.Code() < http.StatusInternalServerError {
+			v.result = append(v.result, e)
 		}
 	}
 }
 
+// isUploadInspectionError tells if an uploaded file was rejected by its inspection, or could not be scanned.
+func isUploadInspectionError(err error) bool {
+	var (
+		typeErr     *runtime.UploadTypeError
+		scanErr     *runtime.UploadScanError
+		scanFailure *runtime.UploadScanFailure
+	)
+
+	return stderrors.As(err, &typeErr) || stderrors.As(err, &scanErr) || stderrors.As(err, &scanFailure)
+}
+
 func (v *validation) contentType() {
 	if len(v.result) > 0 || !runtime.HasBody(v.request) {
 		return
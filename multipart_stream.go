@@ -13,6 +13,7 @@ import (
 	"net/http"
 	"net/textproto"
 	"net/url"
+	"slices"
 
 	"github.com/go-openapi/errors"
 )
@@ -22,10 +23,14 @@ type MultipartFormStreamOption func(*multipartFormStreamConfig)
 
 const defaultMultipartFormStreamMaxParts = 1000
 
+var errUnexpectedFileField = stderrors.New("unexpected file field")
+
 type multipartFormStreamConfig struct {
 	multipartFormLimits
 
-	maxParts int
+	maxParts  int
+	files     []formFileSpec
+	inspector *UploadInspector
 }
 
 // MultipartFormStreamMaxBody caps the total number of request-body bytes read
@@ -57,6 +62,32 @@ func MultipartFormStreamMaxFilenameLen(n int) MultipartFormStreamOption {
 	return func(c *multipartFormStreamConfig) { c.maxFilenameLen = n }
 }
 
+// MultipartFormStreamFile declares a file field expected in the stream under the given form name.
+//
+// Once at least one file field is declared, file parts with an undeclared form name are rejected
+// as they arrive. If required is true and no part with this name has arrived when the stream
+// reaches its end, the stream reports the error:
+//
+//	errors.NewParseError(name, "formData", "", http.ErrMissingFile)
+func MultipartFormStreamFile(name string, required bool) MultipartFormStreamOption {
+	return func(c *multipartFormStreamConfig) {
+		c.files = append(c.files, formFileSpec{
+			name:     name,
+			required: required,
+		})
+	}
+}
+
+// MultipartFormStreamInspect inspects the file parts of the stream while they are read
+// (see [UploadInspector]).
+//
+// Inspection failures are returned by [StreamedFile.Read]: an [*UploadTypeError] as soon as
+// the first bytes are read, an [*UploadScanError] or an [*UploadScanFailure] at the latest when reaching
+// the end of the file.
+func MultipartFormStreamInspect(inspector *UploadInspector) MultipartFormStreamOption {
+	return func(c *multipartFormStreamConfig) { c.inspector = inspector }
+}
+
 // StreamedFile exposes a file part directly from the multipart request body.
 //
 // Reads block until bytes arrive from the client. StreamedFile is not seekable
@@ -74,6 +105,7 @@ type StreamedFile struct {
 	Header    textproto.MIMEHeader
 	part      *multipart.Part
 	closeErr  error
+	inspected *inspectedReader
 }
 
 // MultipartFileInfo describes a file part discovered by [MultipartFormStream].
@@ -93,7 +125,28 @@ func (f *StreamedFile) Read(p []byte) (int, error) {
 		return 0, io.ErrClosedPipe
 	}
 
-	return f.part.Read(p)
+	if f.inspected == nil {
+		return f.part.Read(p)
+	}
+
+	n, err := f.inspected.Read(p)
+	if inspection, ok := f.inspected.inspection(); ok {
+		inspection.setHeader(f.Header)
+	}
+
+	return n, err
+}
+
+// Inspection returns the outcome of the inspection of the file, once it has been read to its end
+// without any inspection failure. See [MultipartFormStreamInspect].
+//
+// The Content-Type and Content-Digest of the file header are then set from the inspection.
+func (f *StreamedFile) Inspection() (UploadInspection, bool) {
+	if f == nil || f.inspected == nil {
+		return UploadInspection{}, false
+	}
+
+	return f.inspected.inspection()
 }
 
 // Close discards the unread remainder of this file part.
@@ -113,6 +166,9 @@ func (f *StreamedFile) Close() error {
 
 	part := f.part
 	f.part = nil
+	if f.inspected != nil {
+		f.inspected.abandon()
+	}
 	// multipart.Part.Close drains with io.Copy but intentionally discards the
 	// resulting error, so drain explicitly to preserve error propagation.
 	_, f.closeErr = io.Copy(io.Discard, part)
@@ -148,6 +204,7 @@ type MultipartFormStream struct {
 	request *http.Request
 	reader  *multipart.Reader
 	current *StreamedFile
+	pending *multipart.Part // a file part found by ReadFields, not opened yet
 
 	fields    url.Values
 	fileInfos []MultipartFileInfo
@@ -308,6 +365,35 @@ func (s *MultipartFormStream) NextFile() (*StreamedFile, error) {
 	return s.readNextFile()
 }
 
+// ReadFields advances through the ordinary form fields which precede the next file part,
+// without opening that file. The next call to [MultipartFormStream.NextFile] returns it.
+//
+// Fields are added to request.Form and request.PostForm, so that they may be bound
+// and validated before any file payload is consumed.
+//
+// If the previously returned file is still open, ReadFields closes and drains it first.
+// ReadFields returns nil when no file parts remain.
+func (s *MultipartFormStream) ReadFields() error {
+	if err := s.prepareNextFile(); err != nil {
+		if stderrors.Is(err, io.EOF) {
+			return nil
+		}
+
+		return err
+	}
+
+	part, err := s.nextFilePart()
+	if stderrors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	s.pending = part
+
+	return nil
+}
+
 // Drain consumes the rest of the multipart body.
 //
 // Unread file payloads are discarded. Non-file form fields encountered while
@@ -348,7 +434,11 @@ func (s *MultipartFormStream) Close() error {
 	}
 
 	s.closed = true
+	s.pending = nil
 	if s.current != nil {
+		if s.current.inspected != nil {
+			s.current.inspected.abandon()
+		}
 		s.current.part = nil
 		s.current = nil
 	}
@@ -455,6 +545,23 @@ func (s *MultipartFormStream) prepareNextFile() error {
 }
 
 func (s *MultipartFormStream) readNextFile() (*StreamedFile, error) {
+	part, err := s.nextFilePart()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.openFile(part, part.FormName(), part.FileName())
+}
+
+// nextFilePart binds the ordinary form fields up to the next file part.
+func (s *MultipartFormStream) nextFilePart() (*multipart.Part, error) {
+	if s.pending != nil {
+		part := s.pending
+		s.pending = nil
+
+		return part, nil
+	}
+
 	for {
 		part, err := s.reader.NextPart()
 		if err != nil {
@@ -486,8 +593,7 @@ func (s *MultipartFormStream) readNextFile() (*StreamedFile, error) {
 			continue
 		}
 
-		filename := part.FileName()
-		if filename == "" {
+		if part.FileName() == "" {
 			if err := s.bindValue(part, fieldName); err != nil {
 				return nil, s.abort(err)
 			}
@@ -495,7 +601,15 @@ func (s *MultipartFormStream) readNextFile() (*StreamedFile, error) {
 			continue
 		}
 
-		return s.openFile(part, fieldName, filename)
+		if len(s.files) > 0 && !slices.ContainsFunc(s.files, func(file formFileSpec) bool {
+			return file.name == fieldName
+		}) {
+			err := errors.NewParseError(fieldName, "formData", "", errUnexpectedFileField)
+
+			return nil, s.abort(err)
+		}
+
+		return part, nil
 	}
 }
 
@@ -503,12 +617,33 @@ func (s *MultipartFormStream) handleNextPartError(err error) error {
 	if stderrors.Is(err, io.EOF) {
 		s.done = true
 
+		if missing := s.missingFile(); missing != "" {
+			return s.abort(errors.NewParseError(missing, "formData", "", http.ErrMissingFile))
+		}
+
 		return io.EOF
 	}
 
 	return s.abort(err)
 }
 
+// missingFile returns the name of a required file field which has not arrived.
+func (s *MultipartFormStream) missingFile() string {
+	for _, file := range s.files {
+		if !file.required {
+			continue
+		}
+
+		if !slices.ContainsFunc(s.fileInfos, func(info MultipartFileInfo) bool {
+			return info.FieldName == file.name
+		}) {
+			return file.name
+		}
+	}
+
+	return ""
+}
+
 func (s *MultipartFormStream) openFile(
 	part *multipart.Part,
 	fieldName string,
@@ -545,6 +680,9 @@ func (s *MultipartFormStream) openFile(
 		Header:    part.Header,
 		part:      part,
 	}
+	if s.inspector != nil {
+		file.inspected = s.inspector.newReader(s.request.Context(), fieldName, filename, part)
+	}
 	s.fileInfos = append(s.fileInfos, MultipartFileInfo{
 		FieldName: fieldName,
 		Filename:  filename,
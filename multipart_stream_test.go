@@ -644,3 +644,99 @@ func (r *observableReadCloser) Closed() bool {
 
 	return r.closed
 }
+
+func TestMultipartFormStreamReadFields(t *testing.T) {
+	body, contentType := orderedMultipartBody(t,
+		orderedField{name: "before", value: "one"},
+		orderedFile{field: testFieldFile1, filename: testFileFieldA, content: "AAA"},
+		orderedField{name: "after", value: "two"},
+	)
+	request := httptest.NewRequestWithContext(t.Context(), http.MethodPost, testUploadPath, body)
+	request.Header.Set(HeaderContentType, contentType)
+
+	stream, err := NewMultipartFormStream(request)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	require.NoError(t, stream.ReadFields())
+	assert.EqualT(t, "one", request.Form.Get("before"))
+	assert.Empty(t, stream.Files())
+
+	// reading fields again does not skip the pending file
+	require.NoError(t, stream.ReadFields())
+
+	file, err := stream.NextFile()
+	require.NoError(t, err)
+	assert.EqualT(t, testFieldFile1, file.FieldName)
+	content, err := io.ReadAll(file)
+	require.NoError(t, err)
+	assert.EqualT(t, "AAA", string(content))
+
+	require.NoError(t, stream.ReadFields())
+	assert.EqualT(t, "two", request.Form.Get("after"))
+
+	_, err = stream.NextFile()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestMultipartFormStreamDeclaredFiles(t *testing.T) {
+	newStream := func(t *testing.T, parts ...orderedMultipartPart) *MultipartFormStream {
+		t.Helper()
+
+		body, contentType := orderedMultipartBody(t, parts...)
+		request := httptest.NewRequestWithContext(t.Context(), http.MethodPost, testUploadPath, body)
+		request.Header.Set(HeaderContentType, contentType)
+		stream, err := NewMultipartFormStream(request,
+			MultipartFormStreamFile(testFieldFile1, true),
+			MultipartFormStreamFile(testFieldFile2, false),
+		)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = stream.Close() })
+
+		return stream
+	}
+
+	t.Run("should accept declared files", func(t *testing.T) {
+		stream := newStream(t,
+			orderedFile{field: testFieldFile1, filename: testFileFieldA, content: "A"},
+		)
+
+		file, err := stream.NextFile()
+		require.NoError(t, err)
+		assert.EqualT(t, testFieldFile1, file.FieldName)
+
+		_, err = stream.NextFile()
+		require.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("should reject undeclared files as they arrive", func(t *testing.T) {
+		stream := newStream(t,
+			orderedFile{field: testFieldFile1, filename: testFileFieldA, content: "A"},
+			orderedFile{field: "other", filename: testFileFieldB, content: "B"},
+		)
+
+		_, err := stream.NextFile()
+		require.NoError(t, err)
+
+		_, err = stream.NextFile()
+		var parseErr *errors.ParseError
+		require.True(t, stderrors.As(err, &parseErr), "expected *errors.ParseError, got %T", err)
+		assert.EqualT(t, "other", parseErr.Name)
+	})
+
+	t.Run("should report missing required files", func(t *testing.T) {
+		stream := newStream(t,
+			orderedField{name: "before", value: "one"},
+			orderedFile{field: testFieldFile2, filename: testFileFieldB, content: "B"},
+		)
+
+		_, err := stream.NextFile()
+		require.NoError(t, err)
+
+		err = stream.Drain()
+		var parseErr *errors.ParseError
+		require.True(t, stderrors.As(err, &parseErr), "expected *errors.ParseError, got %T", err)
+		assert.EqualT(t, testFieldFile1, parseErr.Name)
+		assert.ErrorIs(t, parseErr.Reason, http.ErrMissingFile)
+	})
+}
@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+)
+
+// ProblemTypeBlank is the default problem type, per RFC 9457 §4.2.1.
+//
+// With this type, the title of the problem is the status text of the HTTP status code.
+const ProblemTypeBlank = "about:blank"
+
+// ProblemError is an RFC 9457 (formerly RFC 7807) problem details document.
+//
+// It is served by the middleware when problem details are enabled, and decoded by
+// the client when it receives an application/problem+json response.
+//
+// Members not defined by the RFC are preserved in Extensions, except for the
+// "errors" extension member, which reports per-field validation errors.
+type ProblemError struct {
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	// Errors lists individual validation failures
+	Errors []ProblemFieldError `json:"errors,omitempty"`
+
+	// Extensions holds any other extension member
+	Extensions map[string]any `json:"-"`
+}
+
+// ProblemFieldError describes a validation failure on a single parameter or property.
+type ProblemFieldError struct {
+	Name   string `json:"name,omitempty"`
+	In     string `json:"in,omitempty"`
+	Code   int    `json:"code,omitempty"`
+	Detail string `json:"detail"`
+	Value  any    `json:"value,omitempty"`
+}
+
+// NewProblemError builds a [ProblemError] of type [ProblemTypeBlank] for a given status code.
+func NewProblemError(status int, detail string) *ProblemError {
+	return &ProblemError{
+		Type:   ProblemTypeBlank,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+func (p *ProblemError) Error() string {
+	title := p.Title
+	if title == "" {
+		title = http.StatusText(p.Status)
+	}
+
+	if p.Detail == "" {
+		return fmt.Sprintf("%s (status %d)", title, p.Status)
+	}
+
+	return fmt.Sprintf("%s (status %d): %s", title, p.Status, p.Detail)
+}
+
+// Code returns the HTTP status code of this problem.
+func (p *ProblemError) Code() int32 {
+	return int32(p.Status) //nolint:gosec // HTTP status codes fit in an int32
+}
+
+var problemMembers = []string{"type", "title", "status", "detail", "instance", "errors"}
+
+// MarshalJSON renders the problem with its extension members inlined.
+func (p ProblemError) MarshalJSON() ([]byte, error) {
+	doc := make(map[string]any, len(problemMembers)+len(p.Extensions))
+	maps.Copy(doc, p.Extensions)
+
+	type plain ProblemError
+	buf, err := json.Marshal(plain(p))
+	if err != nil {
+		return nil, err
+	}
+
+	var members map[string]any
+	if err := json.Unmarshal(buf, &members); err != nil {
+		return nil, err
+	}
+	maps.Copy(doc, members)
+
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON decodes a problem document, retaining unknown members as extensions.
+func (p *ProblemError) UnmarshalJSON(data []byte) error {
+	type plain ProblemError
+	var decoded plain
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	for _, known := range problemMembers {
+		delete(members, known)
+	}
+
+	if len(members) > 0 {
+		decoded.Extensions = make(map[string]any, len(members))
+		for k, raw := range members {
+			var v any
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return err
+			}
+			decoded.Extensions[k] = v
+		}
+	}
+
+	*p = ProblemError(decoded)
+
+	return nil
+}
@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestProblemError(t *testing.T) {
+	t.Run("should build a blank problem", func(t *testing.T) {
+		problem := NewProblemError(http.StatusNotFound, "no such pet")
+
+		assert.EqualT(t, ProblemTypeBlank, problem.Type)
+		assert.EqualT(t, "Not Found", problem.Title)
+		assert.EqualT(t, int32(http.StatusNotFound), problem.Code())
+		assert.EqualT(t, "Not Found (status 404): no such pet", problem.Error())
+		assert.EqualT(t, "Conflict (status 409)", (&ProblemError{Status: http.StatusConflict}).Error())
+	})
+
+	t.Run("should inline extension members", func(t *testing.T) {
+		problem := NewProblemError(http.StatusUnprocessableEntity, "validation failure list")
+		problem.Instance = "/pets"
+		problem.Errors = []ProblemFieldError{{Name: "id", In: "path", Code: 602, Detail: "id in path is required"}}
+		problem.Extensions = map[string]any{"traceId": "abc", "status": "ignored"}
+
+		buf, err := json.Marshal(problem)
+		require.NoError(t, err)
+		assert.JSONEqT(t, `{
+			"type": "about:blank",
+			"title": "Unprocessable Entity",
+			"status": 422,
+			"detail": "validation failure list",
+			"instance": "/pets",
+			"errors": [{"name": "id", "in": "path", "code": 602, "detail": "id in path is required"}],
+			"traceId": "abc"
+		}`, string(buf))
+
+		var decoded ProblemError
+		require.NoError(t, json.Unmarshal(buf, &decoded))
+		assert.Equal(t, map[string]any{"traceId": "abc"}, decoded.Extensions)
+		decoded.Extensions = nil
+		problem.Extensions = nil
+		assert.Equal(t, *problem, decoded)
+	})
+
+	t.Run("should be found with errors.As", func(t *testing.T) {
+		err := fmt.Errorf("wrapped: %w", NewProblemError(http.StatusForbidden, ""))
+
+		var problem *ProblemError
+		require.TrueT(t, errors.As(err, &problem))
+		assert.EqualT(t, http.StatusForbidden, problem.Status)
+	})
+
+	t.Run("should reject invalid documents", func(t *testing.T) {
+		var problem ProblemError
+		require.Error(t, json.Unmarshal([]byte(`{"status":"x"}`), &problem))
+		require.Error(t, json.Unmarshal([]byte(`[]`), &problem))
+	})
+}
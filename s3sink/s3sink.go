@@ -0,0 +1,301 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+// Package s3sink stores uploaded files in an S3-compatible object storage.
+//
+// The [Sink] is a [runtime.FileSink], which only depends on the standard library:
+// it supports the PutObject and multipart upload APIs, with path-style addressing and
+// AWS Signature Version 4.
+package s3sink
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/runtime"
+)
+
+const (
+	// DefaultPartSize is the size of the parts of multipart uploads, which is the minimum
+	// size of a part (but the last one) accepted by S3.
+	DefaultPartSize = 5 << 20
+
+	defaultRegion = "us-east-1"
+	maxErrorBody  = 64 << 10
+)
+
+// Error is an error response of the object storage.
+type Error struct {
+	StatusCode int    `xml:"-"`
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("s3: %s (%d): %s", e.Code, e.StatusCode, e.Message)
+}
+
+// Option configures a [Sink].
+type Option func(*Sink)
+
+// WithRegion sets the region used to sign requests. It defaults to "us-east-1".
+func WithRegion(region string) Option {
+	return func(s *Sink) { s.region = region }
+}
+
+// WithCredentials sets the credentials used to sign requests. The session token is optional.
+//
+// Requests are sent unsigned when no credentials are set.
+func WithCredentials(accessKeyID, secretAccessKey, sessionToken string) Option {
+	return func(s *Sink) {
+		s.accessKeyID = accessKeyID
+		s.secretAccessKey = secretAccessKey
+		s.sessionToken = sessionToken
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to send requests. It defaults to [http.DefaultClient].
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *Sink) { s.client = client }
+}
+
+// WithPartSize sets the size of the parts of multipart uploads. It defaults to [DefaultPartSize].
+//
+// Files smaller than a part are stored with a single PutObject request.
+// The part is buffered in memory.
+func WithPartSize(size int64) Option {
+	return func(s *Sink) { s.partSize = size }
+}
+
+// WithKeyFunc sets the function which names the objects. It defaults to [runtime.UploadStorageName].
+func WithKeyFunc(fn func(runtime.MultipartFileInfo) string) Option {
+	return func(s *Sink) { s.keyFunc = fn }
+}
+
+// Sink is a [runtime.FileSink] which stores files as objects of a bucket.
+//
+// The location of stored files is their object key.
+type Sink struct {
+	endpoint        *url.URL
+	bucket          string
+	region          string
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+	client          *http.Client
+	partSize        int64
+	keyFunc         func(runtime.MultipartFileInfo) string
+	now             func() time.Time
+}
+
+// New creates a [Sink] for a bucket, given the base URL of the storage (e.g. "https://s3.eu-west-3.amazonaws.com").
+func New(endpoint, bucket string, opts ...Option) (*Sink, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("s3: invalid endpoint: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("s3: invalid endpoint %q: expected an absolute URL", endpoint)
+	}
+	if bucket == "" {
+		return nil, stderrors.New("s3: empty bucket name")
+	}
+
+	s := &Sink{
+		endpoint: u,
+		bucket:   bucket,
+		region:   defaultRegion,
+		client:   http.DefaultClient,
+		partSize: DefaultPartSize,
+		keyFunc:  runtime.UploadStorageName,
+		now:      time.Now,
+	}
+	for _, apply := range opts {
+		apply(s)
+	}
+
+	if s.partSize <= 0 {
+		return nil, fmt.Errorf("s3: invalid part size %d", s.partSize)
+	}
+
+	return s, nil
+}
+
+// Create starts the upload of an object.
+func (s *Sink) Create(ctx context.Context, file runtime.MultipartFileInfo) (runtime.SinkWriter, error) {
+	return &objectWriter{
+		sink:        s,
+		ctx:         ctx,
+		key:         s.keyFunc(file),
+		contentType: file.Header.Get("Content-Type"),
+	}, nil
+}
+
+// Remove deletes an object.
+func (s *Sink) Remove(ctx context.Context, location string) error {
+	_, err := s.do(ctx, http.MethodDelete, location, nil, nil, nil)
+
+	return err
+}
+
+type objectWriter struct {
+	sink        *Sink
+	ctx         context.Context //nolint:containedctx // Write has no context parameter, so the writer must retain it
+	key         string
+	contentType string
+	buf         bytes.Buffer
+	uploadID    string
+	parts       []completedPart
+}
+
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+func (w *objectWriter) Write(p []byte) (int, error) {
+	n, _ := w.buf.Write(p)
+
+	for int64(w.buf.Len()) >= w.sink.partSize {
+		if err := w.uploadPart(w.buf.Next(int(w.sink.partSize))); err != nil {
+			return n, err
+		}
+	}
+
+	return n, nil
+}
+
+func (w *objectWriter) Commit() (string, error) {
+	if w.uploadID == "" {
+		if _, err := w.sink.do(w.ctx, http.MethodPut, w.key, nil, w.headers(), w.buf.Bytes()); err != nil {
+			return "", err
+		}
+
+		return w.key, nil
+	}
+
+	if w.buf.Len() > 0 {
+		if err := w.uploadPart(w.buf.Bytes()); err != nil {
+			return "", stderrors.Join(err, w.Abort())
+		}
+	}
+
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}{Parts: w.parts})
+	if err != nil {
+		return "", stderrors.Join(err, w.Abort())
+	}
+
+	query := url.Values{"uploadId": {w.uploadID}}
+	if _, err := w.sink.do(w.ctx, http.MethodPost, w.key, query, nil, body); err != nil {
+		return "", stderrors.Join(err, w.Abort())
+	}
+
+	return w.key, nil
+}
+
+func (w *objectWriter) Abort() error {
+	w.buf.Reset()
+	if w.uploadID == "" {
+		return nil
+	}
+
+	// the upload is aborted even though the request was canceled
+	query := url.Values{"uploadId": {w.uploadID}}
+	_, err := w.sink.do(context.WithoutCancel(w.ctx), http.MethodDelete, w.key, query, nil, nil)
+
+	return err
+}
+
+func (w *objectWriter) uploadPart(part []byte) error {
+	if w.uploadID == "" {
+		reply, err := w.sink.do(w.ctx, http.MethodPost, w.key, url.Values{"uploads": {""}}, w.headers(), nil)
+		if err != nil {
+			return err
+		}
+
+		var initiated struct {
+			UploadID string `xml:"UploadId"`
+		}
+		if err := xml.Unmarshal(reply.body, &initiated); err != nil || initiated.UploadID == "" {
+			return fmt.Errorf("s3: invalid multipart upload reply: %w", stderrors.Join(err, stderrors.New("no upload ID")))
+		}
+		w.uploadID = initiated.UploadID
+	}
+
+	number := len(w.parts) + 1
+	query := url.Values{"partNumber": {strconv.Itoa(number)}, "uploadId": {w.uploadID}}
+	reply, err := w.sink.do(w.ctx, http.MethodPut, w.key, query, nil, part)
+	if err != nil {
+		return err
+	}
+
+	w.parts = append(w.parts, completedPart{PartNumber: number, ETag: reply.header.Get("ETag")})
+
+	return nil
+}
+
+func (w *objectWriter) headers() http.Header {
+	if w.contentType == "" {
+		return nil
+	}
+
+	return http.Header{"Content-Type": {w.contentType}}
+}
+
+type reply struct {
+	header http.Header
+	body   []byte
+}
+
+func (s *Sink) do(ctx context.Context, method, key string, query url.Values, header http.Header, body []byte) (reply, error) {
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.bucket + "/" + key
+	u.RawPath = escapePath(u.Path)
+	u.RawQuery = canonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return reply{}, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	sum := sha256.Sum256(body)
+	s.sign(req, hex.EncodeToString(sum[:]))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return reply{}, fmt.Errorf("s3: %w", err)
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	if err != nil {
+		return reply{}, fmt.Errorf("s3: %w", err)
+	}
+
+	// CompleteMultipartUpload may fail with a 200 OK status
+	if resp.StatusCode >= http.StatusMultipleChoices || bytes.Contains(content, []byte("<Error>")) {
+		s3err := &Error{StatusCode: resp.StatusCode, Code: resp.Status}
+		_ = xml.Unmarshal(content, s3err)
+
+		return reply{}, s3err
+	}
+
+	return reply{header: resp.Header, body: content}, nil
+}
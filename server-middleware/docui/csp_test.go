@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package docui
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestContentSecurityPolicy(t *testing.T) {
+	serve := func(t *testing.T, h http.Handler, pth string) *httptest.ResponseRecorder {
+		t.Helper()
+
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, pth, nil)
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, req)
+		require.EqualT(t, http.StatusOK, recorder.Code)
+
+		return recorder
+	}
+
+	t.Run("should not set a policy by default", func(t *testing.T) {
+		recorder := serve(t, SwaggerUI(nil), "/docs")
+		assert.Empty(t, recorder.Header().Get(cspHeader))
+	})
+
+	t.Run("should allow the inline SwaggerUI scriptlet by its hash", func(t *testing.T) {
+		recorder := serve(t, SwaggerUI(nil, WithUIContentSecurityPolicy(true)), "/docs")
+		policy := recorder.Header().Get(cspHeader)
+
+		// the hash covers the exact content of the inline script element
+		inline := regexp.MustCompile(`(?s)<script>(.*?)</script>`).FindStringSubmatch(recorder.Body.String())
+		require.Len(t, inline, 2)
+		sum := sha256.Sum256([]byte(inline[1]))
+		assert.StringContainsT(t, policy, "'sha256-"+base64.StdEncoding.EncodeToString(sum[:])+"'")
+
+		assert.StringContainsT(t, policy, "script-src 'self' https://unpkg.com 'sha256-")
+		assert.StringContainsT(t, policy, "style-src 'self' https://unpkg.com 'unsafe-inline'")
+		assert.StringContainsT(t, policy, "connect-src 'self';")
+		assert.StringNotContainsT(t, policy, "'unsafe-eval'")
+	})
+
+	t.Run("should only allow the same origin for self-hosted assets", func(t *testing.T) {
+		recorder := serve(t, RapiDoc(nil,
+			WithUIContentSecurityPolicy(true),
+			WithUIAssetsURL("/docs/assets/rapidoc-min.js"),
+			WithSpecURL("https://api.example.com/swagger.json"),
+		), "/docs")
+		policy := recorder.Header().Get(cspHeader)
+
+		assert.StringContainsT(t, policy, "script-src 'self';")
+		assert.StringContainsT(t, policy, "connect-src 'self' https://api.example.com;")
+	})
+
+	t.Run("should hash the OAuth2 callback scriptlet", func(t *testing.T) {
+		recorder := serve(t, SwaggerUIOAuth2Callback(nil, WithUIContentSecurityPolicy(true)), "/docs/oauth2-callback")
+		assert.StringContainsT(t, recorder.Header().Get(cspHeader), "'sha256-")
+	})
+}
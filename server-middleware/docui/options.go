@@ -17,6 +17,7 @@ const (
 
 	contentTypeHeader = "Content-Type"
 	applicationJSON   = "application/json"
+	yamlContentType   = "application/yaml"
 )
 
 // UIMiddleware is a function returning a http middleware which accepts UI [Option].
@@ -92,11 +93,21 @@ type (
 
 		// AssetsURL points to the js asset that generates the documentation page.
 		AssetsURL string
+
+		// ContentSecurityPolicy sets a Content-Security-Policy header on the UI page.
+		ContentSecurityPolicy bool
+
+		// PortalUI selects the UI rendered by a documentation portal.
+		PortalUI PortalUI
 	}
 
 	specOptions struct {
 		Path     string
 		Document string
+
+		toYAML   func([]byte) ([]byte, error)
+		filter   func(*http.Request) SpecFilter
+		openAPI3 bool
 	}
 )
 
@@ -0,0 +1,370 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package docui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+const (
+	portalSpecsPath = "specs"
+	portalIndexPath = "apis.json"
+)
+
+// PortalUI selects the documentation UI rendered by a [Portal].
+type PortalUI int
+
+const (
+	// PortalSwaggerUI renders [SwaggerUI], with its native dropdown to select an API.
+	PortalSwaggerUI PortalUI = iota
+	// PortalRedoc renders [Redoc], with a dropdown to select an API.
+	PortalRedoc
+	// PortalRapiDoc renders [RapiDoc], with a dropdown to select an API.
+	PortalRapiDoc
+)
+
+// PortalSpec is a spec document served by a documentation [Portal].
+type PortalSpec struct {
+	// Name identifies the API. It must be unique within a portal.
+	//
+	// The document is served at /{basepath}/{path}/specs/{name}.
+	Name string
+
+	// Title of the API. Defaults to the info.title of a JSON document.
+	Title string
+
+	// Version of the API. Defaults to the info.version of a JSON document.
+	Version string
+
+	// Document is the spec document, either as JSON or YAML.
+	Document []byte
+}
+
+// WithPortalUI selects the UI rendered by a [Portal].
+//
+// Default: [PortalSwaggerUI]
+func WithPortalUI(ui PortalUI) Option {
+	return func(o *options) {
+		o.PortalUI = ui
+	}
+}
+
+// UsePortal creates a middleware to serve a documentation portal for several spec documents.
+//
+// See [Portal].
+func UsePortal(specs []PortalSpec, opts ...Option) func(next http.Handler) http.Handler {
+	p := portalSetup(specs, opts)
+
+	return func(next http.Handler) http.Handler {
+		return p.handler(next)
+	}
+}
+
+// Portal creates a [http.Handler] to serve a documentation portal for several spec documents.
+//
+// By default, the portal is served at route "/docs", and:
+//
+//   - each spec document is served at "/docs/specs/{name}";
+//   - an index of the available APIs is served as JSON at "/docs/apis.json";
+//   - the UI page at "/docs" lets users select the API to browse.
+//
+// The UI is selected with [WithPortalUI], and may be customized with the options common to all UIs.
+// [WithSpecURL] is ignored. Custom templates are provided with the list of APIs as {{ .APIs }},
+// each with its Name, Title, Version, Label and URL.
+//
+// The portal panics if two specs share the same name.
+func Portal(next http.Handler, specs []PortalSpec, opts ...Option) http.Handler {
+	return portalSetup(specs, opts).handler(next)
+}
+
+// PortalAPI describes an API served by a [Portal], as listed by its index.
+type PortalAPI struct {
+	Name    string `json:"name"`
+	Title   string `json:"title,omitempty"`
+	Version string `json:"version,omitempty"`
+	URL     string `json:"url"`
+}
+
+// Label is the text displayed to select an API.
+func (a PortalAPI) Label() string {
+	label := a.Title
+	if label == "" {
+		label = a.Name
+	}
+
+	if a.Version != "" {
+		label += " (" + a.Version + ")"
+	}
+
+	return label
+}
+
+// PortalIndex is the index of the APIs served by a [Portal].
+type PortalIndex struct {
+	APIs []PortalAPI `json:"apis"`
+}
+
+type portalData struct {
+	options
+
+	APIs []PortalAPI
+	URLs []portalURL // the urls setting of SwaggerUI
+}
+
+type portalURL struct {
+	URL  string `json:"url"`
+	Name string `json:"name"`
+}
+
+type portal struct {
+	pth     string
+	specs   map[string]PortalSpec
+	index   []byte
+	page    []byte
+	header  http.Header
+	specsAt string
+}
+
+func portalSetup(specs []PortalSpec, opts []Option) *portal {
+	o := optionsWithDefaults(opts)
+	defaults := portalDefaults[o.PortalUI]
+	o = optionsWithDefaults(opts,
+		WithUITemplate(defaults.template),
+		WithUIAssetsURL(defaults.assets),
+	)
+	o.applySwaggerUIDefaults()
+
+	p := &portal{
+		pth:   path.Join(o.BasePath, o.Path),
+		specs: make(map[string]PortalSpec, len(specs)),
+	}
+	p.specsAt = path.Join(p.pth, portalSpecsPath) + "/"
+
+	data := portalData{options: o, APIs: make([]PortalAPI, 0, len(specs))}
+	for _, spec := range specs {
+		if _, exists := p.specs[spec.Name]; exists || spec.Name == "" {
+			panic(fmt.Errorf("invalid or duplicate portal spec name: %q", spec.Name))
+		}
+
+		spec = withInfo(spec)
+		p.specs[spec.Name] = spec
+		api := PortalAPI{
+			Name:    spec.Name,
+			Title:   spec.Title,
+			Version: spec.Version,
+			URL:     p.specsAt + url.PathEscape(spec.Name),
+		}
+		data.APIs = append(data.APIs, api)
+		data.URLs = append(data.URLs, portalURL{URL: api.URL, Name: api.Label()})
+	}
+
+	index, err := json.Marshal(PortalIndex{APIs: data.APIs})
+	if err != nil {
+		panic(fmt.Errorf("cannot marshal portal index: %w", err))
+	}
+	p.index = index
+
+	tmpl := template.Must(template.New("portal").Parse(o.Template))
+	buf := bytes.NewBuffer(nil)
+	if err := tmpl.Execute(buf, data); err != nil {
+		panic(fmt.Errorf("cannot execute template: %w", err))
+	}
+	p.page = buf.Bytes()
+	p.header = o.pageHeader(p.page)
+
+	return p
+}
+
+// withInfo completes the title and version of a spec from a JSON document.
+func withInfo(spec PortalSpec) PortalSpec {
+	if spec.Title != "" && spec.Version != "" {
+		return spec
+	}
+
+	var doc struct {
+		Info struct {
+			Title   string `json:"title"`
+			Version string `json:"version"`
+		} `json:"info"`
+	}
+	if err := json.Unmarshal(spec.Document, &doc); err != nil {
+		return spec
+	}
+
+	if spec.Title == "" {
+		spec.Title = doc.Info.Title
+	}
+	if spec.Version == "" {
+		spec.Version = doc.Info.Version
+	}
+
+	return spec
+}
+
+func (p *portal) handler(next http.Handler) http.Handler {
+	ui := serveUI(p.pth, p.page, p.header, next)
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		pth := path.Clean(r.URL.Path)
+
+		if pth == path.Join(p.pth, portalIndexPath) {
+			rw.Header().Set(contentTypeHeader, applicationJSON)
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write(p.index)
+
+			return
+		}
+
+		if name, ok := strings.CutPrefix(pth, p.specsAt); ok {
+			if spec, found := p.specs[name]; found {
+				rw.Header().Set(contentTypeHeader, specContentType(spec.Document))
+				rw.WriteHeader(http.StatusOK)
+				_, _ = rw.Write(spec.Document)
+
+				return
+			}
+		}
+
+		ui.ServeHTTP(rw, r)
+	})
+}
+
+// specContentType tells JSON documents from YAML ones.
+func specContentType(doc []byte) string {
+	if trimmed := bytes.TrimSpace(doc); len(trimmed) > 0 && trimmed[0] == '{' {
+		return applicationJSON
+	}
+
+	return yamlContentType
+}
+
+var portalDefaults = map[PortalUI]struct {
+	template string
+	assets   string
+}{
+	PortalSwaggerUI: {template: portalSwaggerUITemplate, assets: swaggerLatest},
+	PortalRedoc:     {template: portalRedocTemplate, assets: redocLatest},
+	PortalRapiDoc:   {template: portalRapiDocTemplate, assets: rapidocLatest},
+}
+
+const (
+	portalSwaggerUITemplate = `<!DOCTYPE html>
+<html lang="en">
+  <head>
+    <meta charset="UTF-8">
+    <title>{{ .Title }}</title>
+	  {{- if .SwaggerStylesURL }}
+    <link rel="stylesheet" type="text/css" href="{{ .SwaggerStylesURL }}" />
+	  {{- end }}
+	  {{- if .Favicon32 }}
+    <link rel="icon" type="image/png" href="{{ .Favicon32 }}" sizes="32x32" />
+	  {{- end }}
+	  {{- if .Favicon16 }}
+    <link rel="icon" type="image/png" href="{{ .Favicon16 }}" sizes="16x16" />
+	  {{- end }}
+    <style>
+      body
+      {
+        margin:0;
+        background: #fafafa;
+      }
+    </style>
+  </head>
+
+  <body>
+    <div id="swagger-ui"></div>
+
+    <script src="{{ .AssetsURL }}"> </script>
+	  {{- if .SwaggerPresetURL }}
+    <script src="{{ .SwaggerPresetURL }}"> </script>
+	  {{- end }}
+    <script>
+    window.onload = function() {
+      window.ui = SwaggerUIBundle({
+        urls: {{ .URLs }},
+        dom_id: '#swagger-ui',
+        deepLinking: true,
+        presets: [
+          SwaggerUIBundle.presets.apis,
+          SwaggerUIStandalonePreset
+        ],
+        plugins: [
+          SwaggerUIBundle.plugins.DownloadUrl
+        ],
+        layout: "StandaloneLayout",
+	      {{- if .OAuth2CallbackURL }}
+        oauth2RedirectUrl: '{{ .OAuth2CallbackURL }}'
+	      {{- end }}
+      })
+    }
+  </script>
+  </body>
+</html>
+`
+
+	portalRedocTemplate = `<!DOCTYPE html>
+<html>
+  <head>
+    <title>{{ .Title }}</title>
+    <meta charset="utf-8"/>
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <style>
+      body {
+        margin: 0;
+        padding: 0;
+      }
+      #api-select {
+        margin: 8px;
+      }
+    </style>
+  </head>
+  <body>
+    <select id="api-select">
+    {{- range .APIs }}
+      <option value="{{ .URL }}">{{ .Label }}</option>
+    {{- end }}
+    </select>
+    <div id="redoc"></div>
+    <script src="{{ .AssetsURL }}"> </script>
+    <script>
+      const select = document.getElementById('api-select');
+      function show() { Redoc.init(select.value, {}, document.getElementById('redoc')); }
+      select.addEventListener('change', show);
+      show();
+    </script>
+  </body>
+</html>
+`
+
+	portalRapiDocTemplate = `<!doctype html>
+<html>
+<head>
+  <title>{{ .Title }}</title>
+  <meta charset="utf-8">
+  <script type="module" src="{{ .AssetsURL }}"></script>
+</head>
+<body>
+  <select id="api-select">
+  {{- range .APIs }}
+    <option value="{{ .URL }}">{{ .Label }}</option>
+  {{- end }}
+  </select>
+  <rapi-doc id="rapi-doc"{{ with .APIs }} spec-url="{{ (index . 0).URL }}"{{ end }}></rapi-doc>
+  <script>
+    const select = document.getElementById('api-select');
+    select.addEventListener('change', function() {
+      document.getElementById('rapi-doc').setAttribute('spec-url', select.value);
+    });
+  </script>
+</body>
+</html>
+`
+)
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package docui
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestPortal(t *testing.T) {
+	specs := []PortalSpec{
+		{Name: "test", Document: testSpec},
+		{Name: "pets", Title: "Pet Store", Version: "2.0", Document: []byte("swagger: '2.0'\ninfo:\n  title: ignored\n")},
+	}
+
+	serve := func(t *testing.T, h http.Handler, pth string) *httptest.ResponseRecorder {
+		t.Helper()
+
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, pth, nil)
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, req)
+
+		return recorder
+	}
+
+	t.Run("with SwaggerUI", func(t *testing.T) {
+		h := Portal(nil, specs, WithUIBasePath("/gateway"))
+
+		t.Run("should serve the index", func(t *testing.T) {
+			recorder := serve(t, h, "/gateway/docs/apis.json")
+			require.EqualT(t, http.StatusOK, recorder.Code)
+			assert.EqualT(t, applicationJSON, recorder.Header().Get(contentTypeHeader))
+			assert.JSONEqT(t, `{"apis":[
+				{"name":"test","title":"Test","version":"1.0.0","url":"/gateway/docs/specs/test"},
+				{"name":"pets","title":"Pet Store","version":"2.0","url":"/gateway/docs/specs/pets"}
+			]}`, recorder.Body.String())
+		})
+
+		t.Run("should serve each spec", func(t *testing.T) {
+			recorder := serve(t, h, "/gateway/docs/specs/test")
+			require.EqualT(t, http.StatusOK, recorder.Code)
+			assert.EqualT(t, applicationJSON, recorder.Header().Get(contentTypeHeader))
+			assert.JSONEqT(t, string(testSpec), recorder.Body.String())
+
+			recorder = serve(t, h, "/gateway/docs/specs/pets")
+			require.EqualT(t, http.StatusOK, recorder.Code)
+			assert.EqualT(t, yamlContentType, recorder.Header().Get(contentTypeHeader))
+		})
+
+		t.Run("should render the urls dropdown", func(t *testing.T) {
+			recorder := serve(t, h, "/gateway/docs")
+			require.EqualT(t, http.StatusOK, recorder.Code)
+
+			body := recorder.Body.String()
+			assert.StringContainsT(t, body, `urls: [{"url":"/gateway/docs/specs/test","name":"Test (1.0.0)"},{"url":"/gateway/docs/specs/pets","name":"Pet Store (2.0)"}]`)
+			assert.StringContainsT(t, body, swaggerLatest)
+		})
+
+		t.Run("should return 404 for unknown specs", func(t *testing.T) {
+			assert.EqualT(t, http.StatusNotFound, serve(t, h, "/gateway/docs/specs/unknown").Code)
+		})
+	})
+
+	t.Run("with Redoc", func(t *testing.T) {
+		h := UsePortal(specs, WithPortalUI(PortalRedoc), WithUIContentSecurityPolicy(true))(nil)
+
+		recorder := serve(t, h, "/docs")
+		require.EqualT(t, http.StatusOK, recorder.Code)
+
+		body := recorder.Body.String()
+		assert.StringContainsT(t, body, `<option value="/docs/specs/pets">Pet Store (2.0)</option>`)
+		assert.StringContainsT(t, body, redocLatest)
+		assert.StringContainsT(t, recorder.Header().Get(cspHeader), "'sha256-")
+	})
+
+	t.Run("with RapiDoc", func(t *testing.T) {
+		h := Portal(nil, specs, WithPortalUI(PortalRapiDoc))
+
+		body := serve(t, h, "/docs").Body.String()
+		assert.StringContainsT(t, body, `<rapi-doc id="rapi-doc" spec-url="/docs/specs/test"></rapi-doc>`)
+		assert.StringContainsT(t, body, `<option value="/docs/specs/test">Test (1.0.0)</option>`)
+		assert.StringContainsT(t, body, rapidocLatest)
+	})
+
+	t.Run("should panic on duplicate names", func(t *testing.T) {
+		assert.Panics(t, func() {
+			_ = Portal(nil, []PortalSpec{{Name: "a"}, {Name: "a"}})
+		})
+	})
+}
@@ -15,9 +15,9 @@ import (
 //
 // [RapiDoc]: https://github.com/rapi-doc/RapiDoc
 func UseRapiDoc(opts ...Option) func(next http.Handler) http.Handler {
-	pth, assets := rapiDocSetup(opts)
+	pth, assets, header := rapiDocSetup(opts)
 	return func(next http.Handler) http.Handler {
-		return serveUI(pth, assets, next)
+		return serveUI(pth, assets, header, next)
 	}
 }
 
@@ -29,12 +29,12 @@ func UseRapiDoc(opts ...Option) func(next http.Handler) http.Handler {
 //
 // [RapiDoc]: https://github.com/rapi-doc/RapiDoc
 func RapiDoc(next http.Handler, opts ...Option) http.Handler {
-	pth, assets := rapiDocSetup(opts)
+	pth, assets, header := rapiDocSetup(opts)
 
-	return serveUI(pth, assets, next)
+	return serveUI(pth, assets, header, next)
 }
 
-func rapiDocSetup(opts []Option) (pth string, assets []byte) {
+func rapiDocSetup(opts []Option) (pth string, assets []byte, header http.Header) {
 	o := optionsWithDefaults(opts,
 		// defaults for rapiDoc
 		WithUITemplate(rapidocTemplate),
@@ -47,7 +47,7 @@ func rapiDocSetup(opts []Option) (pth string, assets []byte) {
 		panic(fmt.Errorf("cannot execute template: %w", err))
 	}
 
-	return pth, buf.Bytes()
+	return pth, buf.Bytes(), o.pageHeader(buf.Bytes())
 }
 
 const (
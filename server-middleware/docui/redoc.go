@@ -15,10 +15,10 @@ import (
 //
 // [Redoc]: https://redocly.com/docs/redoc
 func UseRedoc(opts ...Option) func(next http.Handler) http.Handler {
-	pth, assets := redocSetup(opts)
+	pth, assets, header := redocSetup(opts)
 
 	return func(next http.Handler) http.Handler {
-		return serveUI(pth, assets, next)
+		return serveUI(pth, assets, header, next)
 	}
 }
 
@@ -30,12 +30,12 @@ func UseRedoc(opts ...Option) func(next http.Handler) http.Handler {
 //
 // [Redoc]: https://redocly.com/docs/redoc
 func Redoc(next http.Handler, opts ...Option) http.Handler {
-	pth, assets := redocSetup(opts)
+	pth, assets, header := redocSetup(opts)
 
-	return serveUI(pth, assets, next)
+	return serveUI(pth, assets, header, next)
 }
 
-func redocSetup(opts []Option) (pth string, assets []byte) {
+func redocSetup(opts []Option) (pth string, assets []byte, header http.Header) {
 	o := optionsWithDefaults(opts,
 		// defaults for redoc
 		WithUITemplate(redocTemplate),
@@ -49,7 +49,7 @@ func redocSetup(opts []Option) (pth string, assets []byte) {
 		panic(fmt.Errorf("cannot execute template: %w", err))
 	}
 
-	return pth, buf.Bytes()
+	return pth, buf.Bytes(), o.pageHeader(buf.Bytes())
 }
 
 const (
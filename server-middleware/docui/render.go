@@ -10,9 +10,14 @@ import (
 )
 
 // serveUI creates a [http.Handler] that serves a templated asset as text/html.
-func serveUI(pth string, assets []byte, next http.Handler) http.Handler {
+//
+// Extra headers, such as a Content-Security-Policy, may be sent with the page.
+func serveUI(pth string, assets []byte, header http.Header, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 		if path.Clean(r.URL.Path) == pth {
+			for k, v := range header {
+				rw.Header()[k] = v
+			}
 			rw.Header().Set(contentTypeHeader, "text/html; charset=utf-8")
 			rw.WriteHeader(http.StatusOK)
 			_, _ = rw.Write(assets)
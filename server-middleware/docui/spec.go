@@ -4,16 +4,35 @@
 package docui
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
 	"net/http"
 	"path"
+	"strings"
+	"sync"
+
+	"github.com/go-openapi/runtime/server-middleware/negotiate"
+	"github.com/go-openapi/runtime/server-middleware/negotiate/header"
+)
+
+const (
+	maxSpecViews = 256 // bounds the number of cached filtered views of a spec
+
+	cacheControlHeader = "Cache-Control"
+	etagHeader         = "ETag"
+	ifNoneMatchHeader  = "If-None-Match"
+	varyHeader         = "Vary"
 )
 
 // UseSpec creates a middleware to serve a swagger spec as a JSON document.
+//
+// See [ServeSpec].
 func UseSpec(spec []byte, opts ...SpecOption) func(next http.Handler) http.Handler {
 	o := specOptionsWithDefaults(opts)
 
 	return func(next http.Handler) http.Handler {
-		return handleSpec(o.Path, spec, next)
+		return handleSpec(o, spec, next)
 	}
 }
 
@@ -22,29 +41,262 @@ func UseSpec(spec []byte, opts ...SpecOption) func(next http.Handler) http.Handl
 // This allows for altering the spec before starting the [http] listener.
 //
 // Additional [SpecOption] can be used to change the path and the name of the document (defaults to "/swagger.json").
+//
+// The document is served with a strong ETag, and conditional requests with If-None-Match are
+// answered with 304 (Not Modified).
+//
+// With [WithSpecYAML], the document is also served as YAML, either when negotiated by the Accept
+// header, or when requested with a ".yaml" or ".yml" extension (e.g. "/swagger.yaml").
+//
+// With [WithSpecOpenAPI3], an OpenAPI 3.0 rendering of the document is also served.
+//
+// With [WithSpecFilter], the served document is a view of the spec filtered for each request.
+// Views are then served with "Cache-Control: private", so that shared caches don't serve
+// a view to other callers, and the ETag of a view is specific to its filter.
 func ServeSpec(spec []byte, next http.Handler, opts ...SpecOption) http.Handler {
 	o := specOptionsWithDefaults(opts)
 
-	return handleSpec(o.Path, spec, next)
+	return handleSpec(o, spec, next)
+}
+
+// WithSpecYAML enables serving the spec document as YAML, using a function
+// which converts a JSON document to YAML.
+func WithSpecYAML(toYAML func(jsonDocument []byte) ([]byte, error)) SpecOption {
+	return func(o *specOptions) {
+		o.toYAML = toYAML
+	}
 }
 
-func handleSpec(pth string, spec []byte, next http.Handler) http.Handler {
-	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
-		if path.Clean(r.URL.Path) == pth {
-			rw.Header().Set(contentTypeHeader, applicationJSON)
-			rw.WriteHeader(http.StatusOK)
-			_, _ = rw.Write(spec)
+// WithSpecOpenAPI3 enables serving an OpenAPI 3.0 rendering of a Swagger 2.0 spec document,
+// converted with [ConvertToOpenAPI3].
+//
+// The converted document is served as "openapi.json" next to the spec document (e.g. "/openapi.json"),
+// and as "openapi.yaml" when YAML is enabled with [WithSpecYAML].
+func WithSpecOpenAPI3(enable bool) SpecOption {
+	return func(o *specOptions) {
+		o.openAPI3 = enable
+	}
+}
 
-			return
+// WithSpecFilter serves a filtered view of the spec document, as selected for each request.
+//
+// See [SpecFilter].
+func WithSpecFilter(filter func(*http.Request) SpecFilter) SpecOption {
+	return func(o *specOptions) {
+		o.filter = filter
+	}
+}
+
+// specDocument is a rendition of the spec in some format, with its ETag.
+type specDocument struct {
+	body []byte
+	etag string
+}
+
+// newSpecDocument creates the document of a view of the spec, identified by its filter key when filtered.
+func newSpecDocument(body []byte, view string) specDocument {
+	hash := sha256.New()
+	if view != "" {
+		_, _ = hash.Write([]byte(view))
+		_, _ = hash.Write([]byte{0})
+	}
+	_, _ = hash.Write(body)
+
+	return specDocument{
+		body: body,
+		etag: `"` + base64.RawURLEncoding.EncodeToString(hash.Sum(nil)) + `"`,
+	}
+}
+
+// specTarget is a rendition of the spec requested by a client.
+type specTarget struct {
+	format   string
+	openAPI3 bool
+}
+
+type specHandler struct {
+	paths    map[string]specTarget // the paths of the document, with a fixed rendition
+	jsonPath string                // the path of the document, with a negotiated format
+	oas3Path string                // the path of the OpenAPI 3 document, with a negotiated format
+	spec     []byte
+	toYAML   func([]byte) ([]byte, error)
+	filter   func(*http.Request) SpecFilter
+	next     http.Handler
+
+	mx    sync.Mutex
+	views map[string]specDocument
+}
+
+func handleSpec(o specOptions, spec []byte, next http.Handler) http.Handler {
+	h := &specHandler{
+		jsonPath: o.Path,
+		spec:     spec,
+		toYAML:   o.toYAML,
+		filter:   o.filter,
+		next:     next,
+		paths:    make(map[string]specTarget),
+		views:    make(map[string]specDocument),
+	}
+
+	if o.openAPI3 {
+		h.oas3Path = path.Join(path.Dir(o.Path), openAPI3Document)
+	}
+
+	if h.toYAML != nil {
+		for _, target := range []struct {
+			pth      string
+			openAPI3 bool
+		}{
+			{pth: h.jsonPath},
+			{pth: h.oas3Path, openAPI3: true},
+		} {
+			if target.pth == "" {
+				continue
+			}
+
+			base := strings.TrimSuffix(target.pth, path.Ext(target.pth))
+			for _, ext := range []string{".yaml", ".yml"} {
+				h.paths[base+ext] = specTarget{format: yamlContentType, openAPI3: target.openAPI3}
+			}
 		}
+	}
 
-		if next != nil {
-			next.ServeHTTP(rw, r)
+	return h
+}
+
+func (h *specHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	target, ok := h.target(r)
+	if !ok {
+		if h.next != nil {
+			h.next.ServeHTTP(rw, r)
 
 			return
 		}
 
 		rw.Header().Set(contentTypeHeader, applicationJSON)
 		rw.WriteHeader(http.StatusNotFound)
-	})
+
+		return
+	}
+
+	var filter SpecFilter
+	if h.filter != nil {
+		filter = h.filter(r)
+	}
+
+	doc, err := h.view(filter, target)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if h.toYAML != nil {
+		rw.Header().Add(varyHeader, "Accept")
+	}
+	if h.filter != nil {
+		// the view depends on the caller
+		rw.Header().Set(cacheControlHeader, "private")
+	}
+	rw.Header().Set(etagHeader, doc.etag)
+
+	if matchesETag(header.ParseList(r.Header, ifNoneMatchHeader), doc.etag) {
+		rw.WriteHeader(http.StatusNotModified)
+
+		return
+	}
+
+	rw.Header().Set(contentTypeHeader, target.format)
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(doc.body)
+}
+
+// target determines if the request is for the spec document, and in which rendition.
+func (h *specHandler) target(r *http.Request) (specTarget, bool) {
+	pth := path.Clean(r.URL.Path)
+
+	if target, ok := h.paths[pth]; ok {
+		return target, true
+	}
+
+	var target specTarget
+	switch pth {
+	case h.jsonPath:
+	case h.oas3Path:
+		target.openAPI3 = true
+	default:
+		return target, false
+	}
+
+	target.format = applicationJSON
+	if h.toYAML != nil {
+		target.format = negotiate.ContentType(r, []string{applicationJSON, yamlContentType}, applicationJSON)
+	}
+
+	return target, true
+}
+
+// view returns the document for a filtered view of the spec in some rendition.
+//
+// Views are cached, up to a limit.
+func (h *specHandler) view(filter SpecFilter, target specTarget) (specDocument, error) {
+	key := fmt.Sprintf("%s|%s|%t", filter.key(), target.format, target.openAPI3)
+
+	h.mx.Lock()
+	doc, ok := h.views[key]
+	h.mx.Unlock()
+	if ok {
+		return doc, nil
+	}
+
+	body := h.spec
+	if !filter.isZero() {
+		filtered, err := filter.apply(body)
+		if err != nil {
+			return specDocument{}, err
+		}
+		body = filtered
+	}
+
+	if target.openAPI3 {
+		converted, err := ConvertToOpenAPI3(body)
+		if err != nil {
+			return specDocument{}, err
+		}
+		body = converted
+	}
+
+	if target.format == yamlContentType {
+		yamlBody, err := h.toYAML(body)
+		if err != nil {
+			return specDocument{}, fmt.Errorf("cannot convert spec to YAML: %w", err)
+		}
+		body = yamlBody
+	}
+
+	var view string
+	if h.filter != nil {
+		// views don't share ETags, even when their documents are the same
+		view = filter.key()
+	}
+	doc = newSpecDocument(body, view)
+
+	h.mx.Lock()
+	if len(h.views) < maxSpecViews {
+		h.views[key] = doc
+	}
+	h.mx.Unlock()
+
+	return doc, nil
+}
+
+// matchesETag uses the weak comparison of entity-tags, as required for If-None-Match.
+func matchesETag(candidates []string, etag string) bool {
+	for _, candidate := range candidates {
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
 }
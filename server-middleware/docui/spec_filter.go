@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package docui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const internalExtension = "x-internal"
+
+// operationMethods are the keys of operations in a path item, for Swagger 2.0 and OpenAPI 3.
+var operationMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// SpecFilter selects the operations exposed by a filtered view of a spec document,
+// so that different consumers (e.g. public and internal) see different surfaces of an API.
+//
+// Operations which are filtered out are removed from the paths of the document,
+// and path items left without any operation are removed as well.
+// Definitions and components are left untouched.
+//
+// The zero value doesn't filter anything.
+type SpecFilter struct {
+	// ExcludeInternal removes the path items, operations and tags flagged with "x-internal: true".
+	ExcludeInternal bool
+
+	// Tags, when not nil, restricts the view to the operations with at least one of these tags.
+	// The tags declared at the top level of the document are restricted likewise.
+	Tags []string
+
+	// Scopes, when not nil, restricts the view to the operations the caller may invoke with these scopes:
+	// unsecured operations, and operations with a security requirement which scopes are all granted.
+	//
+	// An empty, non-nil slice restricts the view to unsecured operations.
+	Scopes []string
+}
+
+func (f SpecFilter) isZero() bool {
+	return !f.ExcludeInternal && f.Tags == nil && f.Scopes == nil
+}
+
+// key identifies a filter, regardless of the order of its tags and scopes.
+func (f SpecFilter) key() string {
+	sortedList := func(values []string) string {
+		if values == nil {
+			return "*"
+		}
+
+		sorted := slices.Clone(values)
+		slices.Sort(sorted)
+
+		return "[" + strings.Join(sorted, ",") + "]"
+	}
+
+	return fmt.Sprintf("%t;%s;%s", f.ExcludeInternal, sortedList(f.Tags), sortedList(f.Scopes))
+}
+
+// apply filters a JSON spec document.
+//
+// The keys of the filtered document are sorted.
+func (f SpecFilter) apply(spec []byte) ([]byte, error) {
+	var doc map[string]any
+	dec := json.NewDecoder(bytes.NewReader(spec))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("cannot filter spec: %w", err)
+	}
+
+	globalSecurity, _ := doc["security"].([]any)
+	paths, _ := doc["paths"].(map[string]any)
+	for key, item := range paths {
+		pathItem, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		if f.ExcludeInternal && isInternal(pathItem) {
+			delete(paths, key)
+
+			continue
+		}
+
+		kept := 0
+		for _, method := range operationMethods {
+			operation, isOperation := pathItem[method].(map[string]any)
+			if !isOperation {
+				continue
+			}
+
+			if !f.keeps(operation, globalSecurity) {
+				delete(pathItem, method)
+
+				continue
+			}
+			kept++
+		}
+
+		if kept == 0 {
+			delete(paths, key)
+		}
+	}
+
+	if tags, ok := doc["tags"].([]any); ok {
+		doc["tags"] = slices.DeleteFunc(tags, func(tag any) bool {
+			t, isTag := tag.(map[string]any)
+			if !isTag {
+				return false
+			}
+
+			name, _ := t["name"].(string)
+
+			return (f.ExcludeInternal && isInternal(t)) || (f.Tags != nil && !slices.Contains(f.Tags, name))
+		})
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("cannot filter spec: %w", err)
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func (f SpecFilter) keeps(operation map[string]any, globalSecurity []any) bool {
+	if f.ExcludeInternal && isInternal(operation) {
+		return false
+	}
+
+	if f.Tags != nil {
+		tags, _ := operation["tags"].([]any)
+		if !slices.ContainsFunc(tags, func(tag any) bool {
+			name, _ := tag.(string)
+
+			return slices.Contains(f.Tags, name)
+		}) {
+			return false
+		}
+	}
+
+	if f.Scopes == nil {
+		return true
+	}
+
+	security := globalSecurity
+	if opSecurity, ok := operation["security"].([]any); ok {
+		security = opSecurity
+	}
+
+	if len(security) == 0 {
+		return true
+	}
+
+	// requirements are alternatives: any one of them which scopes are all granted will do
+	return slices.ContainsFunc(security, func(requirement any) bool {
+		schemes, _ := requirement.(map[string]any)
+		for _, scopes := range schemes {
+			required, _ := scopes.([]any)
+			for _, scope := range required {
+				name, _ := scope.(string)
+				if !slices.Contains(f.Scopes, name) {
+					return false
+				}
+			}
+		}
+
+		return true
+	})
+}
+
+func isInternal(object map[string]any) bool {
+	internal, _ := object[internalExtension].(bool)
+
+	return internal
+}
@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package docui
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+var filterSpec = []byte(`{
+  "swagger": "2.0",
+  "info": {"title": "Filtered", "version": "1.0.0"},
+  "security": [{"oauth": ["read"]}],
+  "tags": [{"name": "pets"}, {"name": "stores"}, {"name": "admin", "x-internal": true}],
+  "paths": {
+    "/pets": {
+      "get": {"tags": ["pets"], "security": [], "responses": {"200": {"description": "ok"}}},
+      "post": {"tags": ["pets"], "security": [{"oauth": ["read", "write"]}], "responses": {"201": {"description": "created"}}}
+    },
+    "/stores": {
+      "get": {"tags": ["stores"], "responses": {"200": {"description": "ok"}}},
+      "delete": {"tags": ["stores"], "x-internal": true, "responses": {"204": {"description": "deleted"}}}
+    },
+    "/admin": {
+      "x-internal": true,
+      "get": {"tags": ["admin"], "responses": {"200": {"description": "ok"}}}
+    }
+  }
+}`)
+
+func TestSpecFilter(t *testing.T) {
+	// operations lists the operations of a filtered spec as "METHOD /path"
+	operations := func(t *testing.T, filter SpecFilter) ([]string, []string) {
+		t.Helper()
+
+		filtered, err := filter.apply(filterSpec)
+		require.NoError(t, err)
+
+		var doc struct {
+			Tags []struct {
+				Name string `json:"name"`
+			} `json:"tags"`
+			Paths map[string]map[string]json.RawMessage `json:"paths"`
+		}
+		require.NoError(t, json.Unmarshal(filtered, &doc))
+
+		var ops, tags []string
+		for pth, item := range doc.Paths {
+			for method := range item {
+				if slices.Contains(operationMethods, method) {
+					ops = append(ops, method+" "+pth)
+				}
+			}
+		}
+		for _, tag := range doc.Tags {
+			tags = append(tags, tag.Name)
+		}
+		slices.Sort(ops)
+
+		return ops, tags
+	}
+
+	t.Run("should exclude internal items", func(t *testing.T) {
+		ops, tags := operations(t, SpecFilter{ExcludeInternal: true})
+		assert.Equal(t, []string{"get /pets", "get /stores", "post /pets"}, ops)
+		assert.Equal(t, []string{"pets", "stores"}, tags)
+	})
+
+	t.Run("should restrict to tags", func(t *testing.T) {
+		ops, tags := operations(t, SpecFilter{Tags: []string{"stores", "admin"}})
+		assert.Equal(t, []string{"delete /stores", "get /admin", "get /stores"}, ops)
+		assert.Equal(t, []string{"stores", "admin"}, tags)
+	})
+
+	t.Run("should restrict to scopes", func(t *testing.T) {
+		ops, _ := operations(t, SpecFilter{Scopes: []string{}})
+		assert.Equal(t, []string{"get /pets"}, ops)
+
+		ops, _ = operations(t, SpecFilter{Scopes: []string{"read"}, ExcludeInternal: true})
+		assert.Equal(t, []string{"get /pets", "get /stores"}, ops)
+
+		ops, _ = operations(t, SpecFilter{Scopes: []string{"read", "write"}, ExcludeInternal: true})
+		assert.Equal(t, []string{"get /pets", "get /stores", "post /pets"}, ops)
+	})
+
+	t.Run("should report invalid documents", func(t *testing.T) {
+		_, err := SpecFilter{ExcludeInternal: true}.apply([]byte("swagger: '2.0'"))
+		require.Error(t, err)
+	})
+
+	t.Run("should identify filters regardless of order", func(t *testing.T) {
+		assert.EqualT(t, SpecFilter{Tags: []string{"a", "b"}}.key(), SpecFilter{Tags: []string{"b", "a"}}.key())
+		assert.NotEqual(t, SpecFilter{Scopes: []string{}}.key(), SpecFilter{}.key())
+		assert.TrueT(t, SpecFilter{}.isZero())
+	})
+}
@@ -0,0 +1,653 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package docui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	openAPI3Version  = "3.0.3"
+	openAPI3Document = "openapi.json"
+
+	multipartFormData = "multipart/form-data"
+	urlEncodedForm    = "application/x-www-form-urlencoded"
+)
+
+// refPrefixes maps the prefixes of Swagger 2.0 references to their OpenAPI 3 equivalent.
+var refPrefixes = [][2]string{
+	{"#/definitions/", "#/components/schemas/"},
+	{"#/parameters/", "#/components/parameters/"},
+	{"#/responses/", "#/components/responses/"},
+}
+
+// schemaKeywords are the keys of a non-body Swagger 2.0 parameter, header or items object,
+// which are part of the schema of its OpenAPI 3 counterpart.
+var schemaKeywords = []string{
+	"type", "format", "items", "default", "enum", "multipleOf",
+	"maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum",
+	"maxLength", "minLength", "pattern", "maxItems", "minItems", "uniqueItems",
+	"x-nullable",
+}
+
+// ConvertToOpenAPI3 converts a Swagger 2.0 JSON document to an OpenAPI 3.0 JSON document.
+//
+// The conversion moves definitions, parameters, responses and security definitions to components,
+// maps consumes and produces to content maps, body and formData parameters to request bodies,
+// and schemes, host and base path to servers. References are rewritten accordingly.
+//
+// A document which is already an OpenAPI 3 document is returned unchanged.
+//
+// The keys of the converted document are sorted.
+func ConvertToOpenAPI3(spec []byte) ([]byte, error) {
+	var doc map[string]any
+	dec := json.NewDecoder(bytes.NewReader(spec))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("cannot convert spec to OpenAPI 3: %w", err)
+	}
+
+	if _, isOpenAPI3 := doc["openapi"]; isOpenAPI3 {
+		return spec, nil
+	}
+
+	if version, _ := doc["swagger"].(string); version != "2.0" {
+		return nil, fmt.Errorf("cannot convert spec to OpenAPI 3: unsupported swagger version %q", version)
+	}
+
+	c := newOAS3Converter(doc)
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(c.convert()); err != nil {
+		return nil, fmt.Errorf("cannot convert spec to OpenAPI 3: %w", err)
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+type oas3Converter struct {
+	doc      map[string]any
+	consumes []string
+	produces []string
+
+	// global parameters, which may be referenced by operations
+	parameters map[string]any
+}
+
+func newOAS3Converter(doc map[string]any) *oas3Converter {
+	c := &oas3Converter{
+		doc:      doc,
+		consumes: stringList(doc["consumes"]),
+		produces: stringList(doc["produces"]),
+	}
+	c.parameters, _ = doc["parameters"].(map[string]any)
+
+	if len(c.consumes) == 0 {
+		c.consumes = []string{applicationJSON}
+	}
+	if len(c.produces) == 0 {
+		c.produces = []string{applicationJSON}
+	}
+
+	return c
+}
+
+func (c *oas3Converter) convert() map[string]any {
+	out := map[string]any{"openapi": openAPI3Version}
+
+	for key, value := range c.doc {
+		switch key {
+		case "swagger", "host", "basePath", "schemes", "consumes", "produces",
+			"definitions", "parameters", "responses", "securityDefinitions", "paths":
+		default:
+			// info, tags, security, externalDocs and extensions are the same in both dialects
+			out[key] = value
+		}
+	}
+
+	out["servers"] = c.servers()
+	out["paths"] = c.paths()
+
+	components := map[string]any{}
+	if definitions, ok := c.doc["definitions"].(map[string]any); ok {
+		components["schemas"] = mapValues(definitions, convertSchema)
+	}
+
+	if len(c.parameters) > 0 {
+		parameters := map[string]any{}
+		requestBodies := map[string]any{}
+		for name, value := range c.parameters {
+			param, _ := value.(map[string]any)
+			switch param["in"] {
+			case "body":
+				requestBodies[name] = c.requestBody([]map[string]any{param}, c.consumes)
+			case "formData":
+				// form parameters are inlined in the request body of the operations referencing them
+			default:
+				parameters[name] = convertParameter(param)
+			}
+		}
+		setNotEmpty(components, "parameters", parameters)
+		setNotEmpty(components, "requestBodies", requestBodies)
+	}
+
+	if responses, ok := c.doc["responses"].(map[string]any); ok {
+		components["responses"] = mapValues(responses, func(response any) any {
+			return c.response(response, c.produces)
+		})
+	}
+
+	if securityDefinitions, ok := c.doc["securityDefinitions"].(map[string]any); ok {
+		components["securitySchemes"] = mapValues(securityDefinitions, convertSecurityScheme)
+	}
+
+	setNotEmpty(out, "components", components)
+
+	return out
+}
+
+func (c *oas3Converter) servers() []any {
+	host, _ := c.doc["host"].(string)
+	basePath, _ := c.doc["basePath"].(string)
+	if basePath == "" {
+		basePath = "/"
+	}
+
+	if host == "" {
+		return []any{map[string]any{"url": basePath}}
+	}
+
+	schemes := stringList(c.doc["schemes"])
+	if len(schemes) == 0 {
+		// the scheme used to fetch the document
+		return []any{map[string]any{"url": "//" + host + strings.TrimSuffix(basePath, "/")}}
+	}
+
+	servers := make([]any, 0, len(schemes))
+	for _, scheme := range schemes {
+		servers = append(servers, map[string]any{"url": scheme + "://" + host + strings.TrimSuffix(basePath, "/")})
+	}
+
+	return servers
+}
+
+func (c *oas3Converter) paths() map[string]any {
+	paths, _ := c.doc["paths"].(map[string]any)
+	out := make(map[string]any, len(paths))
+
+	for key, value := range paths {
+		pathItem, ok := value.(map[string]any)
+		if !ok {
+			out[key] = value
+
+			continue
+		}
+
+		// body and form parameters declared for the path item go to the request body of each operation
+		parameters, bodyParameters := c.splitParameters(pathItem["parameters"])
+
+		item := make(map[string]any, len(pathItem))
+		for field, fieldValue := range pathItem {
+			switch {
+			case field == "parameters":
+				setNotEmpty(item, field, parameters)
+			case slices.Contains(operationMethods, field):
+				operation, _ := fieldValue.(map[string]any)
+				item[field] = c.operation(operation, bodyParameters)
+			default:
+				item[field] = convertRefs(fieldValue)
+			}
+		}
+		out[key] = item
+	}
+
+	return out
+}
+
+func (c *oas3Converter) operation(operation map[string]any, inherited []map[string]any) map[string]any {
+	consumes := c.consumes
+	if opConsumes, ok := operation["consumes"]; ok {
+		consumes = stringList(opConsumes)
+	}
+
+	produces := c.produces
+	if opProduces, ok := operation["produces"]; ok {
+		produces = stringList(opProduces)
+	}
+
+	out := make(map[string]any, len(operation))
+	for key, value := range operation {
+		switch key {
+		case "consumes", "produces", "schemes", "parameters", "responses":
+		default:
+			out[key] = value
+		}
+	}
+
+	parameters, bodyParameters := c.splitParameters(operation["parameters"])
+	setNotEmpty(out, "parameters", parameters)
+
+	// an operation overrides the body or form parameters of the path item with the same name
+	for _, param := range inherited {
+		if !slices.ContainsFunc(bodyParameters, func(p map[string]any) bool {
+			return parameterKey(p) == parameterKey(param)
+		}) {
+			bodyParameters = append(bodyParameters, param)
+		}
+	}
+
+	if len(bodyParameters) > 0 {
+		out["requestBody"] = c.requestBody(bodyParameters, consumes)
+	}
+
+	if responses, ok := operation["responses"].(map[string]any); ok {
+		out["responses"] = mapValues(responses, func(response any) any {
+			return c.response(response, produces)
+		})
+	}
+
+	return out
+}
+
+// splitParameters tells the parameters which remain parameters in OpenAPI 3 from
+// the body and form parameters, which make up a request body.
+func (c *oas3Converter) splitParameters(value any) (parameters []any, bodyParameters []map[string]any) {
+	list, _ := value.([]any)
+
+	for _, item := range list {
+		param, _ := item.(map[string]any)
+
+		if ref, isRef := param["$ref"].(string); isRef {
+			name, isGlobal := strings.CutPrefix(ref, "#/parameters/")
+			global, _ := c.parameters[name].(map[string]any)
+
+			switch {
+			case isGlobal && global["in"] == "body":
+				bodyParameters = append(bodyParameters, param)
+			case isGlobal && global["in"] == "formData":
+				bodyParameters = append(bodyParameters, global)
+			default:
+				parameters = append(parameters, convertRefs(param))
+			}
+
+			continue
+		}
+
+		switch param["in"] {
+		case "body", "formData":
+			bodyParameters = append(bodyParameters, param)
+		default:
+			parameters = append(parameters, convertParameter(param))
+		}
+	}
+
+	return parameters, bodyParameters
+}
+
+func parameterKey(param map[string]any) string {
+	if ref, isRef := param["$ref"].(string); isRef {
+		return ref
+	}
+
+	return fmt.Sprintf("%v:%v", param["in"], param["name"])
+}
+
+// requestBody converts either a body parameter, or a set of form parameters.
+func (c *oas3Converter) requestBody(params []map[string]any, consumes []string) map[string]any {
+	var form []map[string]any
+	for _, param := range params {
+		if ref, isRef := param["$ref"].(string); isRef {
+			return map[string]any{"$ref": "#/components/requestBodies/" + strings.TrimPrefix(ref, "#/parameters/")}
+		}
+
+		if param["in"] == "body" {
+			return bodyRequestBody(param, consumes)
+		}
+
+		form = append(form, param)
+	}
+
+	return formRequestBody(form, consumes)
+}
+
+func bodyRequestBody(param map[string]any, consumes []string) map[string]any {
+	body := map[string]any{
+		"content": contentMap(consumes, convertSchema(param["schema"]), nil),
+	}
+	copyFields(body, param, "description", "required")
+	copyExtensions(body, param)
+
+	return body
+}
+
+func formRequestBody(params []map[string]any, consumes []string) map[string]any {
+	schema := map[string]any{"type": "object"}
+	properties := make(map[string]any, len(params))
+	var required []any
+	hasFile := false
+
+	for _, param := range params {
+		name, _ := param["name"].(string)
+		property := parameterSchema(param)
+		if description, ok := param["description"]; ok {
+			property["description"] = description
+		}
+		properties[name] = property
+
+		if isRequired, _ := param["required"].(bool); isRequired {
+			required = append(required, name)
+		}
+		if param["type"] == "file" {
+			hasFile = true
+		}
+	}
+	schema["properties"] = properties
+	setNotEmpty(schema, "required", required)
+
+	mediaTypes := slices.DeleteFunc(slices.Clone(consumes), func(mediaType string) bool {
+		return mediaType != multipartFormData && mediaType != urlEncodedForm
+	})
+	if len(mediaTypes) == 0 {
+		mediaTypes = []string{urlEncodedForm}
+		if hasFile {
+			mediaTypes = []string{multipartFormData}
+		}
+	}
+
+	body := map[string]any{"content": contentMap(mediaTypes, schema, nil)}
+	if slices.ContainsFunc(params, func(param map[string]any) bool {
+		isRequired, _ := param["required"].(bool)
+
+		return isRequired
+	}) {
+		body["required"] = true
+	}
+
+	return body
+}
+
+func (c *oas3Converter) response(value any, produces []string) any {
+	response, ok := value.(map[string]any)
+	if !ok {
+		return value
+	}
+
+	if _, isRef := response["$ref"]; isRef {
+		return convertRefs(response)
+	}
+
+	out := make(map[string]any, len(response))
+	for key, field := range response {
+		switch key {
+		case "schema", "examples":
+		case "headers":
+			headers, _ := field.(map[string]any)
+			out[key] = mapValues(headers, convertHeader)
+		default:
+			out[key] = field
+		}
+	}
+
+	if schema, hasSchema := response["schema"]; hasSchema {
+		examples, _ := response["examples"].(map[string]any)
+		out["content"] = contentMap(produces, convertSchema(schema), examples)
+	}
+
+	return out
+}
+
+func contentMap(mediaTypes []string, schema any, examples map[string]any) map[string]any {
+	content := make(map[string]any, len(mediaTypes))
+	for _, mediaType := range mediaTypes {
+		media := map[string]any{"schema": schema}
+		if example, ok := examples[mediaType]; ok {
+			media["example"] = example
+		}
+		content[mediaType] = media
+	}
+
+	return content
+}
+
+func convertParameter(param map[string]any) any {
+	if _, isRef := param["$ref"]; isRef {
+		return convertRefs(param)
+	}
+
+	out := map[string]any{"schema": parameterSchema(param)}
+	copyFields(out, param, "name", "in", "description", "required", "allowEmptyValue")
+	copyExtensions(out, param)
+
+	in, _ := param["in"].(string)
+	switch param["collectionFormat"] {
+	case "csv":
+		if in == "query" {
+			out["style"] = "form"
+			out["explode"] = false
+		}
+	case "multi":
+		out["style"] = "form"
+		out["explode"] = true
+	case "ssv":
+		out["style"] = "spaceDelimited"
+	case "pipes":
+		out["style"] = "pipeDelimited"
+	}
+
+	return out
+}
+
+func convertHeader(value any) any {
+	header, ok := value.(map[string]any)
+	if !ok {
+		return value
+	}
+
+	out := map[string]any{"schema": parameterSchema(header)}
+	copyFields(out, header, "description")
+	copyExtensions(out, header)
+
+	return out
+}
+
+// parameterSchema builds the schema of a non-body parameter, a header or an items object.
+func parameterSchema(param map[string]any) map[string]any {
+	schema := make(map[string]any)
+	for _, keyword := range schemaKeywords {
+		if value, ok := param[keyword]; ok {
+			schema[keyword] = value
+		}
+	}
+
+	if items, ok := schema["items"].(map[string]any); ok {
+		schema["items"] = parameterSchema(items)
+	}
+
+	converted, _ := convertSchema(schema).(map[string]any)
+
+	return converted
+}
+
+// convertSchema converts a schema: types, nullable flags and discriminators differ
+// between both dialects.
+func convertSchema(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, field := range v {
+			switch key {
+			case "$ref":
+				out[key] = convertRef(field)
+			case "x-nullable":
+				out["nullable"] = field
+			case "discriminator":
+				if name, ok := field.(string); ok {
+					out[key] = map[string]any{"propertyName": name}
+				} else {
+					out[key] = field
+				}
+			case "properties", "definitions", "patternProperties":
+				properties, _ := field.(map[string]any)
+				out[key] = mapValues(properties, convertSchema)
+			case "example", "enum", "default", "x-example":
+				out[key] = field
+			default:
+				out[key] = convertSchema(field)
+			}
+		}
+
+		if out["type"] == "file" {
+			out["type"] = "string"
+			out["format"] = "binary"
+		}
+
+		return out
+	case []any:
+		out := make([]any, 0, len(v))
+		for _, item := range v {
+			out = append(out, convertSchema(item))
+		}
+
+		return out
+	default:
+		return value
+	}
+}
+
+func convertSecurityScheme(value any) any {
+	scheme, ok := value.(map[string]any)
+	if !ok {
+		return value
+	}
+
+	out := make(map[string]any)
+	copyFields(out, scheme, "description")
+	copyExtensions(out, scheme)
+
+	switch scheme["type"] {
+	case "basic":
+		out["type"] = "http"
+		out["scheme"] = "basic"
+	case "apiKey":
+		copyFields(out, scheme, "type", "name", "in")
+	case "oauth2":
+		flows := map[string]string{
+			"implicit":    "implicit",
+			"password":    "password",
+			"application": "clientCredentials",
+			"accessCode":  "authorizationCode",
+		}
+		flow := make(map[string]any)
+		copyFields(flow, scheme, "authorizationUrl", "tokenUrl", "scopes")
+		if _, hasScopes := flow["scopes"]; !hasScopes {
+			flow["scopes"] = map[string]any{}
+		}
+
+		name, _ := scheme["flow"].(string)
+		out["type"] = "oauth2"
+		out["flows"] = map[string]any{flows[name]: flow}
+	default:
+		copyFields(out, scheme, "type")
+	}
+
+	return out
+}
+
+// convertRefs rewrites all references found in a value.
+func convertRefs(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, field := range v {
+			if key == "$ref" {
+				out[key] = convertRef(field)
+
+				continue
+			}
+			out[key] = convertRefs(field)
+		}
+
+		return out
+	case []any:
+		out := make([]any, 0, len(v))
+		for _, item := range v {
+			out = append(out, convertRefs(item))
+		}
+
+		return out
+	default:
+		return value
+	}
+}
+
+func convertRef(value any) any {
+	ref, ok := value.(string)
+	if !ok {
+		return value
+	}
+
+	for _, prefixes := range refPrefixes {
+		if rest, found := strings.CutPrefix(ref, prefixes[0]); found {
+			return prefixes[1] + rest
+		}
+	}
+
+	return ref
+}
+
+func mapValues(in map[string]any, convert func(any) any) map[string]any {
+	out := make(map[string]any, len(in))
+	for key, value := range in {
+		out[key] = convert(value)
+	}
+
+	return out
+}
+
+func copyFields(dst, src map[string]any, keys ...string) {
+	for _, key := range keys {
+		if value, ok := src[key]; ok {
+			dst[key] = value
+		}
+	}
+}
+
+func copyExtensions(dst, src map[string]any) {
+	for key, value := range src {
+		if strings.HasPrefix(key, "x-") && key != "x-nullable" {
+			dst[key] = value
+		}
+	}
+}
+
+func setNotEmpty(dst map[string]any, key string, value any) {
+	switch v := value.(type) {
+	case []any:
+		if len(v) > 0 {
+			dst[key] = v
+		}
+	case map[string]any:
+		if len(v) > 0 {
+			dst[key] = v
+		}
+	}
+}
+
+func stringList(value any) []string {
+	list, _ := value.([]any)
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,306 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package docui
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+var swaggerSpec = []byte(`{
+  "swagger": "2.0",
+  "info": {"title": "Pets", "version": "1.0.0"},
+  "host": "api.example.com",
+  "basePath": "/v1",
+  "schemes": ["https"],
+  "consumes": ["application/json"],
+  "produces": ["application/json", "application/xml"],
+  "x-owner": "team",
+  "paths": {
+    "/pets": {
+      "get": {
+        "operationId": "listPets",
+        "parameters": [
+          {"name": "tags", "in": "query", "type": "array", "items": {"type": "string"}, "collectionFormat": "multi"},
+          {"$ref": "#/parameters/limit"}
+        ],
+        "responses": {
+          "200": {
+            "description": "pets",
+            "headers": {"X-Total": {"type": "integer", "description": "total"}},
+            "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}},
+            "examples": {"application/json": [{"name": "rex"}]}
+          },
+          "default": {"$ref": "#/responses/error"}
+        }
+      },
+      "post": {
+        "operationId": "addPet",
+        "security": [{"oauth": ["write"]}],
+        "parameters": [
+          {"name": "pet", "in": "body", "required": true, "description": "the pet", "schema": {"$ref": "#/definitions/Pet"}}
+        ],
+        "responses": {"201": {"description": "created"}}
+      }
+    },
+    "/pets/{id}/photo": {
+      "parameters": [
+        {"name": "id", "in": "path", "required": true, "type": "integer", "format": "int64"}
+      ],
+      "put": {
+        "consumes": ["multipart/form-data"],
+        "produces": [],
+        "parameters": [
+          {"name": "file", "in": "formData", "required": true, "type": "file"},
+          {"name": "caption", "in": "formData", "type": "string", "x-nullable": true}
+        ],
+        "responses": {"204": {"description": "uploaded"}}
+      }
+    }
+  },
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "discriminator": "kind",
+      "required": ["name", "kind"],
+      "properties": {
+        "name": {"type": "string"},
+        "kind": {"type": "string"},
+        "owner": {"$ref": "#/definitions/Owner"}
+      }
+    },
+    "Owner": {"type": "object", "x-nullable": true}
+  },
+  "parameters": {
+    "limit": {"name": "limit", "in": "query", "type": "integer", "maximum": 100}
+  },
+  "responses": {
+    "error": {"description": "error", "schema": {"type": "string"}}
+  },
+  "securityDefinitions": {
+    "basic": {"type": "basic"},
+    "key": {"type": "apiKey", "name": "X-Key", "in": "header"},
+    "oauth": {
+      "type": "oauth2",
+      "flow": "accessCode",
+      "authorizationUrl": "https://example.com/authorize",
+      "tokenUrl": "https://example.com/token",
+      "scopes": {"write": "modify pets"}
+    }
+  }
+}`)
+
+func TestConvertToOpenAPI3(t *testing.T) {
+	t.Run("should convert a swagger 2.0 document", func(t *testing.T) {
+		converted, err := ConvertToOpenAPI3(swaggerSpec)
+		require.NoError(t, err)
+
+		assert.JSONEqT(t, `{
+  "openapi": "3.0.3",
+  "info": {"title": "Pets", "version": "1.0.0"},
+  "servers": [{"url": "https://api.example.com/v1"}],
+  "x-owner": "team",
+  "paths": {
+    "/pets": {
+      "get": {
+        "operationId": "listPets",
+        "parameters": [
+          {"name": "tags", "in": "query", "style": "form", "explode": true, "schema": {"type": "array", "items": {"type": "string"}}},
+          {"$ref": "#/components/parameters/limit"}
+        ],
+        "responses": {
+          "200": {
+            "description": "pets",
+            "headers": {"X-Total": {"description": "total", "schema": {"type": "integer"}}},
+            "content": {
+              "application/json": {
+                "schema": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}},
+                "example": [{"name": "rex"}]
+              },
+              "application/xml": {
+                "schema": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}}
+              }
+            }
+          },
+          "default": {"$ref": "#/components/responses/error"}
+        }
+      },
+      "post": {
+        "operationId": "addPet",
+        "security": [{"oauth": ["write"]}],
+        "requestBody": {
+          "description": "the pet",
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}
+        },
+        "responses": {"201": {"description": "created"}}
+      }
+    },
+    "/pets/{id}/photo": {
+      "parameters": [
+        {"name": "id", "in": "path", "required": true, "schema": {"type": "integer", "format": "int64"}}
+      ],
+      "put": {
+        "requestBody": {
+          "required": true,
+          "content": {
+            "multipart/form-data": {
+              "schema": {
+                "type": "object",
+                "required": ["file"],
+                "properties": {
+                  "file": {"type": "string", "format": "binary"},
+                  "caption": {"type": "string", "nullable": true}
+                }
+              }
+            }
+          }
+        },
+        "responses": {"204": {"description": "uploaded"}}
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Pet": {
+        "type": "object",
+        "discriminator": {"propertyName": "kind"},
+        "required": ["name", "kind"],
+        "properties": {
+          "name": {"type": "string"},
+          "kind": {"type": "string"},
+          "owner": {"$ref": "#/components/schemas/Owner"}
+        }
+      },
+      "Owner": {"type": "object", "nullable": true}
+    },
+    "parameters": {
+      "limit": {"name": "limit", "in": "query", "schema": {"type": "integer", "maximum": 100}}
+    },
+    "responses": {
+      "error": {
+        "description": "error",
+        "content": {
+          "application/json": {"schema": {"type": "string"}},
+          "application/xml": {"schema": {"type": "string"}}
+        }
+      }
+    },
+    "securitySchemes": {
+      "basic": {"type": "http", "scheme": "basic"},
+      "key": {"type": "apiKey", "name": "X-Key", "in": "header"},
+      "oauth": {
+        "type": "oauth2",
+        "flows": {
+          "authorizationCode": {
+            "authorizationUrl": "https://example.com/authorize",
+            "tokenUrl": "https://example.com/token",
+            "scopes": {"write": "modify pets"}
+          }
+        }
+      }
+    }
+  }
+}`, string(converted))
+	})
+
+	t.Run("should convert form parameters to url-encoded bodies", func(t *testing.T) {
+		converted, err := ConvertToOpenAPI3([]byte(`{"swagger":"2.0","info":{},"paths":{"/login":{"post":{
+			"parameters":[{"name":"user","in":"formData","type":"string"}],
+			"responses":{"204":{"description":"ok"}}
+		}}}}`))
+		require.NoError(t, err)
+
+		assert.JSONEqT(t, `{"openapi":"3.0.3","info":{},"servers":[{"url":"/"}],"paths":{"/login":{"post":{
+			"requestBody":{"content":{"application/x-www-form-urlencoded":{"schema":{"type":"object","properties":{"user":{"type":"string"}}}}}},
+			"responses":{"204":{"description":"ok"}}
+		}}}}`, string(converted))
+	})
+
+	t.Run("should leave OpenAPI 3 documents unchanged", func(t *testing.T) {
+		doc := []byte(`{"openapi":"3.1.0","info":{},"paths":{}}`)
+		converted, err := ConvertToOpenAPI3(doc)
+		require.NoError(t, err)
+		assert.EqualT(t, string(doc), string(converted))
+	})
+
+	t.Run("should reject other documents", func(t *testing.T) {
+		_, err := ConvertToOpenAPI3([]byte(`{"info":{}}`))
+		require.Error(t, err)
+
+		_, err = ConvertToOpenAPI3([]byte(`not json`))
+		require.Error(t, err)
+	})
+}
+
+func TestServeSpecOpenAPI3(t *testing.T) {
+	serve := func(t *testing.T, h http.Handler, pth, accept string) *httptest.ResponseRecorder {
+		t.Helper()
+
+		request, err := http.NewRequestWithContext(context.Background(), http.MethodGet, pth, nil)
+		require.NoError(t, err)
+		if accept != "" {
+			request.Header.Set("Accept", accept)
+		}
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, request)
+
+		return recorder
+	}
+
+	expected, err := ConvertToOpenAPI3(swaggerSpec)
+	require.NoError(t, err)
+
+	t.Run("should serve openapi.json next to the spec", func(t *testing.T) {
+		h := ServeSpec(swaggerSpec, nil, WithSpecPath("/api/swagger.json"), WithSpecOpenAPI3(true))
+
+		recorder := serve(t, h, "/api/openapi.json", "")
+		require.EqualT(t, http.StatusOK, recorder.Code)
+		assert.EqualT(t, applicationJSON, recorder.Header().Get(contentTypeHeader))
+		assert.JSONEqT(t, string(expected), recorder.Body.String())
+		assert.EqualT(t, newSpecDocument(expected, "").etag, recorder.Header().Get(etagHeader))
+
+		recorder = serve(t, h, "/api/swagger.json", "")
+		require.EqualT(t, http.StatusOK, recorder.Code)
+		assert.JSONEqT(t, string(swaggerSpec), recorder.Body.String())
+	})
+
+	t.Run("should serve openapi.yaml", func(t *testing.T) {
+		toYAML := func(doc []byte) ([]byte, error) {
+			return append([]byte("# yaml\n"), doc...), nil
+		}
+		h := ServeSpec(swaggerSpec, nil, WithSpecOpenAPI3(true), WithSpecYAML(toYAML))
+
+		for _, recorder := range []*httptest.ResponseRecorder{
+			serve(t, h, "/openapi.yaml", ""),
+			serve(t, h, "/openapi.json", yamlContentType),
+		} {
+			require.EqualT(t, http.StatusOK, recorder.Code)
+			assert.EqualT(t, yamlContentType, recorder.Header().Get(contentTypeHeader))
+			assert.StringContainsT(t, recorder.Body.String(), `"openapi":"3.0.3"`)
+		}
+	})
+
+	t.Run("should apply filters before conversion", func(t *testing.T) {
+		h := ServeSpec(swaggerSpec, nil, WithSpecOpenAPI3(true), WithSpecFilter(func(*http.Request) SpecFilter {
+			return SpecFilter{Scopes: []string{}}
+		}))
+
+		recorder := serve(t, h, "/openapi.json", "")
+		require.EqualT(t, http.StatusOK, recorder.Code)
+		assert.StringContainsT(t, recorder.Body.String(), `"listPets"`)
+		assert.StringNotContainsT(t, recorder.Body.String(), `"addPet"`)
+	})
+
+	t.Run("should not serve openapi.json by default", func(t *testing.T) {
+		h := ServeSpec(swaggerSpec, nil)
+
+		assert.EqualT(t, http.StatusNotFound, serve(t, h, "/openapi.json", "").Code)
+	})
+}
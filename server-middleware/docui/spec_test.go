@@ -78,3 +78,115 @@ func TestServeSpecMiddleware(t *testing.T) {
 		})
 	})
 }
+
+func TestServeSpecConditional(t *testing.T) {
+	handler := ServeSpec(testSpec, nil)
+
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/swagger.json", nil)
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	require.EqualT(t, http.StatusOK, recorder.Code)
+
+	etag := recorder.Header().Get(etagHeader)
+	require.NotEmpty(t, etag)
+	assert.EqualT(t, newSpecDocument(testSpec, "").etag, etag)
+
+	request.Header.Set(ifNoneMatchHeader, `"other", `+etag)
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	assert.EqualT(t, http.StatusNotModified, recorder.Code)
+	assert.Empty(t, recorder.Body.Bytes())
+}
+
+func TestServeSpecYAML(t *testing.T) {
+	toYAML := func(doc []byte) ([]byte, error) {
+		return append([]byte("# yaml\n"), doc...), nil
+	}
+	handler := ServeSpec(testSpec, nil, WithSpecYAML(toYAML))
+
+	serve := func(t *testing.T, pth, accept string) *httptest.ResponseRecorder {
+		t.Helper()
+
+		request, err := http.NewRequestWithContext(context.Background(), http.MethodGet, pth, nil)
+		require.NoError(t, err)
+		if accept != "" {
+			request.Header.Set("Accept", accept)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		return recorder
+	}
+
+	for _, tc := range []struct {
+		name, pth, accept, expected string
+	}{
+		{name: "JSON by default", pth: "/swagger.json", expected: applicationJSON},
+		{name: "JSON when accepted", pth: "/swagger.json", accept: "application/json", expected: applicationJSON},
+		{name: "YAML when accepted", pth: "/swagger.json", accept: "application/yaml, application/json;q=0.5", expected: yamlContentType},
+		{name: "YAML by extension", pth: "/swagger.yaml", expected: yamlContentType},
+		{name: "YAML by short extension", pth: "/swagger.yml", accept: "application/json", expected: yamlContentType},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serve(t, tc.pth, tc.accept)
+			require.EqualT(t, http.StatusOK, recorder.Code)
+			assert.EqualT(t, tc.expected, recorder.Header().Get(contentTypeHeader))
+			assert.EqualT(t, "Accept", recorder.Header().Get(varyHeader))
+
+			if tc.expected == yamlContentType {
+				assert.StringContainsT(t, recorder.Body.String(), "# yaml\n")
+			}
+		})
+	}
+
+	t.Run("ETags differ by format", func(t *testing.T) {
+		assert.NotEqual(t, serve(t, "/swagger.json", "").Header().Get(etagHeader), serve(t, "/swagger.yaml", "").Header().Get(etagHeader))
+	})
+
+	t.Run("should not serve YAML unless enabled", func(t *testing.T) {
+		handler := ServeSpec(testSpec, nil)
+		request, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/swagger.yaml", nil)
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		assert.EqualT(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+func TestServeSpecFilter(t *testing.T) {
+	handler := ServeSpec(filterSpec, nil, WithSpecFilter(func(r *http.Request) SpecFilter {
+		if r.Header.Get("X-Internal") != "" {
+			return SpecFilter{}
+		}
+
+		return SpecFilter{ExcludeInternal: true}
+	}))
+
+	serve := func(t *testing.T, internal bool) *httptest.ResponseRecorder {
+		t.Helper()
+
+		request, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/swagger.json", nil)
+		require.NoError(t, err)
+		if internal {
+			request.Header.Set("X-Internal", "true")
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		require.EqualT(t, http.StatusOK, recorder.Code)
+
+		return recorder
+	}
+
+	internal := serve(t, true)
+	public := serve(t, false)
+
+	assert.Equal(t, filterSpec, internal.Body.Bytes())
+	assert.StringNotContainsT(t, public.Body.String(), "/admin")
+	assert.NotEqual(t, internal.Header().Get(etagHeader), public.Header().Get(etagHeader))
+
+	// views are private to their callers, and don't share the ETag of the unfiltered document
+	assert.EqualT(t, "private", internal.Header().Get(cacheControlHeader))
+	assert.EqualT(t, "private", public.Header().Get(cacheControlHeader))
+	assert.NotEqual(t, newSpecDocument(filterSpec, "").etag, internal.Header().Get(etagHeader))
+}
@@ -15,10 +15,10 @@ import (
 //
 // [SwaggerUI]: https://swagger.io/tools/swagger-ui
 func UseSwaggerUI(opts ...Option) func(next http.Handler) http.Handler {
-	pth, assets := swaggeruiSetup(opts)
+	pth, assets, header := swaggeruiSetup(opts)
 
 	return func(next http.Handler) http.Handler {
-		return serveUI(pth, assets, next)
+		return serveUI(pth, assets, header, next)
 	}
 }
 
@@ -30,12 +30,12 @@ func UseSwaggerUI(opts ...Option) func(next http.Handler) http.Handler {
 //
 // [SwaggerUI]: https://swagger.io/tools/swagger-ui
 func SwaggerUI(next http.Handler, opts ...Option) http.Handler {
-	pth, assets := swaggeruiSetup(opts)
+	pth, assets, header := swaggeruiSetup(opts)
 
-	return serveUI(pth, assets, next)
+	return serveUI(pth, assets, header, next)
 }
 
-func swaggeruiSetup(opts []Option) (pth string, assets []byte) {
+func swaggeruiSetup(opts []Option) (pth string, assets []byte, header http.Header) {
 	o := optionsWithDefaults(opts,
 		// defaults for SwaggerUI
 		WithUITemplate(swaggeruiTemplate),
@@ -53,7 +53,7 @@ func swaggeruiSetup(opts []Option) (pth string, assets []byte) {
 		panic(fmt.Errorf("cannot execute template: %w", err))
 	}
 
-	return pth, buf.Bytes()
+	return pth, buf.Bytes(), o.pageHeader(buf.Bytes())
 }
 
 const (
@@ -14,22 +14,22 @@ import (
 // UseSwaggerUIOAuth2Callback creates a middleware that serves a callback URL to complete
 // a OAuth2 token handshake.
 func UseSwaggerUIOAuth2Callback(opts ...Option) func(next http.Handler) http.Handler {
-	pth, assets := swaggeruiOAuth2Setup(opts)
+	pth, assets, header := swaggeruiOAuth2Setup(opts)
 
 	return func(next http.Handler) http.Handler {
-		return serveUI(pth, assets, next)
+		return serveUI(pth, assets, header, next)
 	}
 }
 
 // SwaggerUIOAuth2Callback creates a [http.Handler] that serves a callback URL to complete
 // a OAuth2 token handshake.
 func SwaggerUIOAuth2Callback(next http.Handler, opts ...Option) http.Handler {
-	pth, assets := swaggeruiOAuth2Setup(opts)
+	pth, assets, header := swaggeruiOAuth2Setup(opts)
 
-	return serveUI(pth, assets, next)
+	return serveUI(pth, assets, header, next)
 }
 
-func swaggeruiOAuth2Setup(opts []Option) (pth string, assets []byte) {
+func swaggeruiOAuth2Setup(opts []Option) (pth string, assets []byte, header http.Header) {
 	o := optionsWithDefaults(opts,
 		// defaults for SwaggerUI OAuth2 callback endpoint
 		WithUITemplate(swaggerOAuth2Template),
@@ -47,7 +47,7 @@ func swaggeruiOAuth2Setup(opts []Option) (pth string, assets []byte) {
 		panic(fmt.Errorf("cannot execute template: %w", err))
 	}
 
-	return pth, buf.Bytes()
+	return pth, buf.Bytes(), o.pageHeader(buf.Bytes())
 }
 
 const swaggerOAuth2Template = `
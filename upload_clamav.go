@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+)
+
+const clamAVChunkSize = 32 << 10
+
+// ErrUploadInfected is returned by the [ClamAVScanner] when malware is found in a file.
+var ErrUploadInfected = stderrors.New("malware found")
+
+// ClamAVScanner creates an [UploadScanner] which submits files to a clamd daemon,
+// using its INSTREAM command.
+//
+// The network and address are those of the clamd socket, e.g. ("tcp", "localhost:3310")
+// or ("unix", "/var/run/clamav/clamd.ctl"). A connection is opened for each file.
+//
+// Files in which clamd finds malware are rejected with an error wrapping [ErrUploadInfected].
+// Other errors, e.g. when clamd is unreachable or replies with an error, wrap [ErrUploadScanFailed].
+// Notice that clamd rejects streams larger than its StreamMaxLength setting.
+func ClamAVScanner(network, address string) UploadScanner {
+	return UploadScannerFunc(func(ctx context.Context, r io.Reader) error {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, network, address)
+		if err != nil {
+			return fmt.Errorf("%w: clamav: %w", ErrUploadScanFailed, err)
+		}
+		defer conn.Close()
+
+		if deadline, ok := ctx.Deadline(); ok {
+			_ = conn.SetDeadline(deadline)
+		}
+
+		// the reply is read on error too, since clamd may reject the stream before its end
+		writeErr := clamAVInstream(conn, r)
+
+		reply, err := bufio.NewReader(conn).ReadString(0)
+		if err != nil && (!stderrors.Is(err, io.EOF) || reply == "") {
+			return fmt.Errorf("%w: clamav: %w", ErrUploadScanFailed, stderrors.Join(writeErr, err))
+		}
+
+		return clamAVVerdict(strings.TrimRight(reply, "\x00\n"))
+	})
+}
+
+func clamAVInstream(w io.Writer, r io.Reader) error {
+	if _, err := io.WriteString(w, "zINSTREAM\x00"); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 4+clamAVChunkSize)
+	for {
+		n, err := r.Read(buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf[:4], uint32(n)) //nolint:gosec // n <= clamAVChunkSize
+			if _, werr := w.Write(buf[:4+n]); werr != nil {
+				return werr
+			}
+		}
+
+		if stderrors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// a zero-length chunk terminates the stream
+	_, err := w.Write([]byte{0, 0, 0, 0})
+
+	return err
+}
+
+// clamAVVerdict interprets a reply, e.g. "stream: OK" or "stream: Eicar-Signature FOUND".
+func clamAVVerdict(reply string) error {
+	result := strings.TrimPrefix(reply, "stream: ")
+
+	switch {
+	case result == "OK":
+		return nil
+	case strings.HasSuffix(result, " FOUND"):
+		return fmt.Errorf("%w: %s", ErrUploadInfected, strings.TrimSuffix(result, " FOUND"))
+	default:
+		return fmt.Errorf("%w: clamav: %s", ErrUploadScanFailed, reply)
+	}
+}
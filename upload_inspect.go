@@ -0,0 +1,483 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"context"
+	"crypto"
+	"encoding/base64"
+	stderrors "errors"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+const (
+	sniffLength = 512 // the number of bytes considered by http.DetectContentType
+
+	// HeaderContentDigest is the header which carries the digest of a content (RFC 9530).
+	HeaderContentDigest = "Content-Digest"
+)
+
+var errUploadAbandoned = stderrors.New("upload abandoned before the end of the file")
+
+// ErrUploadScanFailed is wrapped by the errors of an [UploadScanner] which could not scan a file,
+// e.g. when its antivirus is unreachable.
+var ErrUploadScanFailed = stderrors.New("upload scan failed")
+
+// digestNames are the algorithm names registered for the Content-Digest header.
+var digestNames = map[crypto.Hash]string{
+	crypto.SHA256: "sha-256",
+	crypto.SHA512: "sha-512",
+}
+
+// UploadScanner scans the content of an uploaded file, e.g. with an antivirus.
+//
+// Scan reads the content from r, and returns an error to reject the file.
+// A scanner which cannot give a verdict returns an error wrapping [ErrUploadScanFailed] instead:
+// the file is then neither accepted nor rejected, and the request fails with an [*UploadScanFailure].
+// The content is fed to the scanner while the file is being read by its consumer,
+// and the consumer only reaches the end of the file once the scanner has returned.
+//
+// A scanner which returns before the end of the content accepts the rest of the file.
+type UploadScanner interface {
+	Scan(ctx context.Context, r io.Reader) error
+}
+
+// UploadScannerFunc is an adapter for a function to the [UploadScanner] interface.
+type UploadScannerFunc func(ctx context.Context, r io.Reader) error
+
+// Scan calls the function.
+func (f UploadScannerFunc) Scan(ctx context.Context, r io.Reader) error {
+	return f(ctx, r)
+}
+
+// UploadTypeError reports an uploaded file which content type is not allowed.
+//
+// It maps to the HTTP status 415 (Unsupported Media Type).
+type UploadTypeError struct {
+	Name        string   // the form field of the file
+	Filename    string   // the client-supplied file name
+	ContentType string   // the content type detected from the content of the file
+	Allowed     []string // the allowed content types
+}
+
+func (e *UploadTypeError) Error() string {
+	return fmt.Sprintf("formData %s: file %q has content type %q, which is not one of [%s]",
+		e.Name, e.Filename, e.ContentType, strings.Join(e.Allowed, ", "))
+}
+
+// Code returns 415 (Unsupported Media Type).
+func (e *UploadTypeError) Code() int32 {
+	return http.StatusUnsupportedMediaType
+}
+
+// UploadScanError reports an uploaded file rejected by an [UploadScanner].
+//
+// It maps to the HTTP status 422 (Unprocessable Entity).
+type UploadScanError struct {
+	Name     string // the form field of the file
+	Filename string // the client-supplied file name
+	Err      error  // the error returned by the scanner
+}
+
+func (e *UploadScanError) Error() string {
+	return fmt.Sprintf("formData %s: file %q was rejected: %v", e.Name, e.Filename, e.Err)
+}
+
+// Code returns 422 (Unprocessable Entity).
+func (e *UploadScanError) Code() int32 {
+	return http.StatusUnprocessableEntity
+}
+
+func (e *UploadScanError) Unwrap() error {
+	return e.Err
+}
+
+// UploadScanFailure reports an uploaded file which could not be scanned, e.g. because the
+// antivirus is unreachable, or because the request was canceled before the verdict.
+//
+// It maps to the HTTP status 503 (Service Unavailable).
+type UploadScanFailure struct {
+	Name     string // the form field of the file
+	Filename string // the client-supplied file name
+	Err      error  // the error returned by the scanner
+}
+
+func (e *UploadScanFailure) Error() string {
+	return fmt.Sprintf("formData %s: file %q could not be scanned: %v", e.Name, e.Filename, e.Err)
+}
+
+// Code returns 503 (Service Unavailable).
+func (e *UploadScanFailure) Code() int32 {
+	return http.StatusServiceUnavailable
+}
+
+func (e *UploadScanFailure) Unwrap() error {
+	return e.Err
+}
+
+// UploadInspection is the outcome of the inspection of an uploaded file.
+type UploadInspection struct {
+	// ContentType is detected from the first bytes of the file, with [http.DetectContentType].
+	ContentType string
+
+	// Size is the number of bytes in the file.
+	Size int64
+
+	// Digest of the file, when enabled with [InspectDigest].
+	Digest []byte
+
+	algorithm crypto.Hash
+}
+
+// ContentDigest formats the digest as the value of a Content-Digest header (RFC 9530),
+// e.g. "sha-256=:base64:". It is empty when no digest was computed.
+func (i UploadInspection) ContentDigest() string {
+	name, ok := digestNames[i.algorithm]
+	if !ok || i.Digest == nil {
+		return ""
+	}
+
+	return name + "=:" + base64.StdEncoding.EncodeToString(i.Digest) + ":"
+}
+
+// setHeader replaces the client-supplied content type of a file with the inspected one, and sets its digest.
+func (i UploadInspection) setHeader(header textproto.MIMEHeader) {
+	if header == nil {
+		return
+	}
+
+	header.Set(HeaderContentType, i.ContentType)
+	if digest := i.ContentDigest(); digest != "" {
+		header.Set(HeaderContentDigest, digest)
+	}
+}
+
+// UploadInspectOption configures an [UploadInspector].
+type UploadInspectOption func(*uploadInspectConfig)
+
+type uploadInspectConfig struct {
+	allowed   []string
+	scanner   UploadScanner
+	algorithm crypto.Hash
+}
+
+// InspectAllowedTypes restricts the content types detected for uploaded files.
+//
+// Media types may use wildcards (e.g. "image/*"). Parameters are ignored.
+// Repeated options extend the list of allowed types. By default, any content type is allowed.
+//
+// Notice that the content types detected by [http.DetectContentType] are coarse:
+// e.g. JSON or CSV files are detected as "text/plain".
+func InspectAllowedTypes(mediaTypes ...string) UploadInspectOption {
+	return func(c *uploadInspectConfig) {
+		c.allowed = append(c.allowed, mediaTypes...)
+	}
+}
+
+// InspectScanner scans the content of uploaded files.
+func InspectScanner(scanner UploadScanner) UploadInspectOption {
+	return func(c *uploadInspectConfig) {
+		c.scanner = scanner
+	}
+}
+
+// InspectDigest computes a digest of uploaded files, with crypto.SHA256 or crypto.SHA512.
+//
+// The hash function must be linked into the binary (e.g. by importing crypto/sha256).
+func InspectDigest(algorithm crypto.Hash) UploadInspectOption {
+	return func(c *uploadInspectConfig) {
+		c.algorithm = algorithm
+	}
+}
+
+// UploadInspector inspects uploaded files as they are read: it detects their actual content type
+// from their first bytes, checks it against a list of allowed types, feeds their content to a scanner
+// and computes their digest.
+//
+// File names and MIME headers supplied by clients are untrusted. Once inspected, the Content-Type
+// header of a file is replaced by the detected type, and its Content-Digest header is set.
+//
+// An UploadInspector is safe for concurrent use.
+type UploadInspector struct {
+	uploadInspectConfig
+}
+
+// NewUploadInspector creates an [UploadInspector].
+//
+// Use [MultipartFormStreamInspect] to inspect the files of a [MultipartFormStream],
+// and [BindFormInspect] to inspect the files bound by [BindForm].
+func NewUploadInspector(opts ...UploadInspectOption) *UploadInspector {
+	var cfg uploadInspectConfig
+	for _, apply := range opts {
+		apply(&cfg)
+	}
+
+	if cfg.algorithm != 0 && !cfg.algorithm.Available() {
+		panic(fmt.Errorf("digest algorithm %v is not available", cfg.algorithm))
+	}
+
+	return &UploadInspector{uploadInspectConfig: cfg}
+}
+
+// InspectFile inspects a file bound from a parsed form, then rewinds it.
+//
+// On success, the Content-Type and Content-Digest of the file header are set from the inspection.
+func (i *UploadInspector) InspectFile(ctx context.Context, name string, file multipart.File, header *multipart.FileHeader) (UploadInspection, error) {
+	r := i.newReader(ctx, name, header.Filename, file)
+	defer r.abandon()
+
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return UploadInspection{}, err
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return UploadInspection{}, err
+	}
+
+	if header.Header == nil {
+		header.Header = make(textproto.MIMEHeader)
+	}
+	r.result.setHeader(header.Header)
+
+	return r.result, nil
+}
+
+func (i *UploadInspector) allows(contentType string) bool {
+	if len(i.allowed) == 0 {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, allowed := range i.allowed {
+		allowedType, _, err := mime.ParseMediaType(allowed)
+		if err != nil {
+			continue
+		}
+
+		if allowedType == "*/*" || allowedType == mediaType {
+			return true
+		}
+
+		if prefix, isWildcard := strings.CutSuffix(allowedType, "/*"); isWildcard && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (i *UploadInspector) newReader(ctx context.Context, name, filename string, src io.Reader) *inspectedReader {
+	r := &inspectedReader{
+		ctx:       ctx,
+		inspector: i,
+		name:      name,
+		filename:  filename,
+		src:       src,
+	}
+
+	if i.algorithm != 0 {
+		r.hash = i.algorithm.New()
+		r.result.algorithm = i.algorithm
+	}
+
+	return r
+}
+
+// inspectedReader inspects a file while it is being read.
+type inspectedReader struct {
+	ctx       context.Context //nolint:containedctx // Read has no context parameter, so the reader must retain it
+	inspector *UploadInspector
+	name      string
+	filename  string
+	src       io.Reader
+
+	sniffed bool
+	pending []byte // bytes read to sniff the content type, not returned yet
+	srcEOF  bool
+	hash    hash.Hash
+
+	scanW    *io.PipeWriter
+	scanDone chan error
+
+	result UploadInspection
+	done   bool  // the inspection is complete and successful
+	err    error // the sticky error of the inspection
+}
+
+func (r *inspectedReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	if !r.sniffed {
+		if err := r.sniff(); err != nil {
+			r.err = err
+
+			return 0, err
+		}
+	}
+
+	if len(r.pending) > 0 {
+		n := copy(p, r.pending)
+		r.pending = r.pending[n:]
+
+		return n, nil
+	}
+
+	if r.srcEOF {
+		return 0, r.finish()
+	}
+
+	n, err := r.src.Read(p)
+	if n > 0 {
+		if feedErr := r.feed(p[:n]); feedErr != nil {
+			r.err = feedErr
+
+			return 0, feedErr
+		}
+	}
+
+	if stderrors.Is(err, io.EOF) {
+		return n, r.finish()
+	}
+	if err != nil {
+		r.err = err
+	}
+
+	return n, err
+}
+
+// sniff reads the first bytes of the file to detect its content type, and starts the scanner.
+func (r *inspectedReader) sniff() error {
+	r.sniffed = true
+
+	buf := make([]byte, sniffLength)
+	n, err := io.ReadFull(r.src, buf)
+	switch {
+	case stderrors.Is(err, io.EOF), stderrors.Is(err, io.ErrUnexpectedEOF):
+		r.srcEOF = true
+	case err != nil:
+		return err
+	}
+	r.pending = buf[:n]
+
+	r.result.ContentType = http.DetectContentType(r.pending)
+	if !r.inspector.allows(r.result.ContentType) {
+		return &UploadTypeError{
+			Name:        r.name,
+			Filename:    r.filename,
+			ContentType: r.result.ContentType,
+			Allowed:     r.inspector.allowed,
+		}
+	}
+
+	if scanner := r.inspector.scanner; scanner != nil {
+		pr, pw := io.Pipe()
+		r.scanW = pw
+		r.scanDone = make(chan error, 1)
+
+		go func() {
+			err := scanner.Scan(r.ctx, pr)
+			// a scanner which returns early accepts, or rejects, the rest of the content
+			_ = pr.CloseWithError(err)
+			r.scanDone <- err
+		}()
+	}
+
+	return r.feed(r.pending)
+}
+
+// feed passes the content to the digest and the scanner.
+func (r *inspectedReader) feed(p []byte) error {
+	r.result.Size += int64(len(p))
+
+	if r.hash != nil {
+		_, _ = r.hash.Write(p)
+	}
+
+	if r.scanW == nil {
+		return nil
+	}
+
+	if _, err := r.scanW.Write(p); err != nil {
+		if stderrors.Is(err, io.ErrClosedPipe) {
+			// the scanner has returned early without any error
+			return nil
+		}
+
+		return r.scanError(err)
+	}
+
+	return nil
+}
+
+// finish waits for the verdict of the scanner when the whole file has been read.
+func (r *inspectedReader) finish() error {
+	if r.done {
+		return io.EOF
+	}
+
+	if r.scanW != nil {
+		_ = r.scanW.Close()
+		err := <-r.scanDone
+		r.scanW = nil
+		if err != nil {
+			r.err = r.scanError(err)
+
+			return r.err
+		}
+	}
+
+	if r.hash != nil {
+		r.result.Digest = r.hash.Sum(nil)
+	}
+	r.done = true
+
+	return io.EOF
+}
+
+func (r *inspectedReader) scanError(err error) error {
+	if r.scanW != nil {
+		_ = r.scanW.CloseWithError(err)
+		<-r.scanDone
+		r.scanW = nil
+	}
+
+	if stderrors.Is(err, ErrUploadScanFailed) || stderrors.Is(err, context.Canceled) || stderrors.Is(err, context.DeadlineExceeded) {
+		return &UploadScanFailure{Name: r.name, Filename: r.filename, Err: err}
+	}
+
+	return &UploadScanError{Name: r.name, Filename: r.filename, Err: err}
+}
+
+// abandon stops the scanner of a file which has not been read to the end.
+func (r *inspectedReader) abandon() {
+	if r.scanW == nil {
+		return
+	}
+
+	_ = r.scanW.CloseWithError(errUploadAbandoned)
+	<-r.scanDone
+	r.scanW = nil
+}
+
+// inspection returns the outcome of a complete and successful inspection.
+func (r *inspectedReader) inspection() (UploadInspection, bool) {
+	if !r.done {
+		return UploadInspection{}, false
+	}
+
+	return r.result, true
+}
@@ -0,0 +1,301 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+const (
+	testPNGContent = "\x89PNG\r\n\x1a\n" + "image data"
+	testEICAR      = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+)
+
+func inspectedFile(t *testing.T, inspector *UploadInspector, content string) (UploadInspection, *multipart.FileHeader, error) {
+	t.Helper()
+
+	request := newMultipartRequest(t, []multipartFile{{testFieldFile, "upload.bin", content}}, nil)
+	require.NoError(t, request.ParseMultipartForm(defaultMultipartFormStreamMaxParts))
+	file, header, err := request.FormFile(testFieldFile)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = file.Close() })
+
+	inspection, err := inspector.InspectFile(t.Context(), testFieldFile, file, header)
+	if err != nil {
+		return inspection, header, err
+	}
+
+	// the file is rewound
+	content2, readErr := io.ReadAll(file)
+	require.NoError(t, readErr)
+	assert.EqualT(t, content, string(content2))
+
+	return inspection, header, nil
+}
+
+func TestUploadInspector(t *testing.T) {
+	t.Run("should detect the content type and compute a digest", func(t *testing.T) {
+		inspector := NewUploadInspector(InspectAllowedTypes("image/*"), InspectDigest(crypto.SHA256))
+
+		inspection, header, err := inspectedFile(t, inspector, testPNGContent)
+		require.NoError(t, err)
+
+		sum := sha256.Sum256([]byte(testPNGContent))
+		assert.EqualT(t, "image/png", inspection.ContentType)
+		assert.EqualT(t, int64(len(testPNGContent)), inspection.Size)
+		assert.Equal(t, sum[:], inspection.Digest)
+		assert.EqualT(t, "sha-256=:"+base64.StdEncoding.EncodeToString(sum[:])+":", inspection.ContentDigest())
+
+		// the client-supplied content type is replaced
+		assert.EqualT(t, "image/png", header.Header.Get(HeaderContentType))
+		assert.EqualT(t, inspection.ContentDigest(), header.Header.Get(HeaderContentDigest))
+	})
+
+	t.Run("should reject types which are not allowed", func(t *testing.T) {
+		inspector := NewUploadInspector(InspectAllowedTypes("image/png", "application/pdf"))
+
+		_, _, err := inspectedFile(t, inspector, "plain text")
+		var typeErr *UploadTypeError
+		require.ErrorAs(t, err, &typeErr)
+		assert.EqualT(t, "text/plain; charset=utf-8", typeErr.ContentType)
+		assert.EqualT(t, testFieldFile, typeErr.Name)
+		assert.EqualT(t, int32(http.StatusUnsupportedMediaType), typeErr.Code())
+	})
+
+	t.Run("should reject files refused by the scanner", func(t *testing.T) {
+		inspector := NewUploadInspector(InspectScanner(UploadScannerFunc(func(_ context.Context, r io.Reader) error {
+			content, err := io.ReadAll(r)
+			if err != nil {
+				return err
+			}
+			if bytes.Contains(content, []byte("EICAR")) {
+				return ErrUploadInfected
+			}
+
+			return nil
+		})))
+
+		_, _, err := inspectedFile(t, inspector, "clean content")
+		require.NoError(t, err)
+
+		_, _, err = inspectedFile(t, inspector, testEICAR)
+		var scanErr *UploadScanError
+		require.ErrorAs(t, err, &scanErr)
+		require.ErrorIs(t, err, ErrUploadInfected)
+		assert.EqualT(t, int32(http.StatusUnprocessableEntity), scanErr.Code())
+	})
+
+	t.Run("should fail when the scanner fails", func(t *testing.T) {
+		inspector := NewUploadInspector(InspectScanner(UploadScannerFunc(func(context.Context, io.Reader) error {
+			return fmt.Errorf("%w: antivirus unreachable", ErrUploadScanFailed)
+		})))
+
+		_, _, err := inspectedFile(t, inspector, "clean content")
+		var scanFailure *UploadScanFailure
+		require.ErrorAs(t, err, &scanFailure)
+		require.ErrorIs(t, err, ErrUploadScanFailed)
+		assert.EqualT(t, int32(http.StatusServiceUnavailable), scanFailure.Code())
+
+		var scanErr *UploadScanError
+		assert.FalseT(t, stderrors.As(err, &scanErr))
+	})
+
+	t.Run("should accept files when the scanner returns early", func(t *testing.T) {
+		inspector := NewUploadInspector(InspectScanner(UploadScannerFunc(func(context.Context, io.Reader) error {
+			return nil
+		})))
+
+		_, _, err := inspectedFile(t, inspector, strings.Repeat("x", 4*sniffLength))
+		require.NoError(t, err)
+	})
+}
+
+func TestMultipartFormStreamInspect(t *testing.T) {
+	newStream := func(t *testing.T, inspector *UploadInspector, content string) *MultipartFormStream {
+		t.Helper()
+
+		request := newMultipartRequest(t, []multipartFile{{testFieldFile, "upload.bin", content}}, nil)
+		stream, err := NewMultipartFormStream(request, MultipartFormStreamInspect(inspector))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = stream.Close() })
+
+		return stream
+	}
+
+	t.Run("should inspect files while they are read", func(t *testing.T) {
+		content := testPNGContent + strings.Repeat("x", 4*sniffLength)
+		stream := newStream(t, NewUploadInspector(InspectAllowedTypes("image/png"), InspectDigest(crypto.SHA256)), content)
+
+		file, err := stream.NextFile()
+		require.NoError(t, err)
+
+		_, done := file.Inspection()
+		assert.FalseT(t, done)
+
+		data, err := io.ReadAll(file)
+		require.NoError(t, err)
+		assert.EqualT(t, content, string(data))
+
+		inspection, done := file.Inspection()
+		require.TrueT(t, done)
+		assert.EqualT(t, "image/png", inspection.ContentType)
+		assert.EqualT(t, inspection.ContentDigest(), file.Header.Get(HeaderContentDigest))
+	})
+
+	t.Run("should fail the first read of a file of a type not allowed", func(t *testing.T) {
+		stream := newStream(t, NewUploadInspector(InspectAllowedTypes("image/png")), "plain text")
+
+		file, err := stream.NextFile()
+		require.NoError(t, err)
+
+		_, err = file.Read(make([]byte, 10))
+		var typeErr *UploadTypeError
+		require.ErrorAs(t, err, &typeErr)
+	})
+
+	t.Run("should stop the scanner of an abandoned file", func(t *testing.T) {
+		scanned := make(chan error, 1)
+		inspector := NewUploadInspector(InspectScanner(UploadScannerFunc(func(_ context.Context, r io.Reader) error {
+			_, err := io.Copy(io.Discard, r)
+			scanned <- err
+
+			return err
+		})))
+		stream := newStream(t, inspector, strings.Repeat("x", 4*sniffLength))
+
+		file, err := stream.NextFile()
+		require.NoError(t, err)
+		_, err = file.Read(make([]byte, 10))
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+
+		require.ErrorIs(t, <-scanned, errUploadAbandoned)
+	})
+}
+
+func TestBindFormInspect(t *testing.T) {
+	request := newMultipartRequest(t, []multipartFile{{testFieldFile, "photo.png", "not an image"}}, nil)
+
+	called := false
+	fatal, err := BindForm(request,
+		BindFormInspect(NewUploadInspector(InspectAllowedTypes("image/*"))),
+		BindFormFile(testFieldFile, true, func(multipart.File, *multipart.FileHeader) error {
+			called = true
+
+			return nil
+		}),
+	)
+
+	assert.FalseT(t, fatal)
+	assert.FalseT(t, called)
+	assertCompositeContains(t, err, 1, func(e error) bool {
+		var apiErr errors.Error
+
+		return stderrors.As(e, &apiErr) && apiErr.Code() == http.StatusUnsupportedMediaType
+	})
+}
+
+// clamdStandIn serves the INSTREAM command of clamd, and finds the EICAR test signature.
+func clamdStandIn(t *testing.T) string {
+	t.Helper()
+
+	listener, err := (&net.ListenConfig{}).Listen(t.Context(), "tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				reader := bufio.NewReader(conn)
+				command, err := reader.ReadString(0)
+				if err != nil || command != "zINSTREAM\x00" {
+					_, _ = io.WriteString(conn, "UNKNOWN COMMAND\x00")
+
+					return
+				}
+
+				var content bytes.Buffer
+				for {
+					var size uint32
+					if err := binary.Read(reader, binary.BigEndian, &size); err != nil {
+						return
+					}
+					if size == 0 {
+						break
+					}
+					if _, err := io.CopyN(&content, reader, int64(size)); err != nil {
+						return
+					}
+				}
+
+				reply := "stream: OK\x00"
+				if strings.Contains(content.String(), "EICAR-STANDARD-ANTIVIRUS-TEST-FILE") {
+					reply = "stream: Eicar-Test-Signature FOUND\x00"
+				}
+				_, _ = io.WriteString(conn, reply)
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestClamAVScanner(t *testing.T) {
+	scanner := ClamAVScanner("tcp", clamdStandIn(t))
+
+	require.NoError(t, scanner.Scan(t.Context(), strings.NewReader(strings.Repeat("clean ", 20000))))
+
+	err := scanner.Scan(t.Context(), strings.NewReader(testEICAR))
+	require.ErrorIs(t, err, ErrUploadInfected)
+	assert.StringContainsT(t, err.Error(), "Eicar-Test-Signature")
+
+	t.Run("as an inspection scanner", func(t *testing.T) {
+		request := httptest.NewRequestWithContext(t.Context(), http.MethodPost, testUploadPath, nil)
+		body, contentType := multipartBody(t, []multipartFile{{testFieldFile, "eicar.com", testEICAR}}, nil)
+		request.Body = io.NopCloser(body)
+		request.Header.Set(HeaderContentType, contentType)
+
+		stream, err := NewMultipartFormStream(request, MultipartFormStreamInspect(NewUploadInspector(InspectScanner(scanner))))
+		require.NoError(t, err)
+		defer stream.Close()
+
+		file, err := stream.NextFile()
+		require.NoError(t, err)
+
+		_, err = io.ReadAll(file)
+		var scanErr *UploadScanError
+		require.ErrorAs(t, err, &scanErr)
+		require.ErrorIs(t, err, ErrUploadInfected)
+	})
+
+	t.Run("should fail when clamd is unreachable", func(t *testing.T) {
+		require.ErrorIs(t, ClamAVScanner("unix", "/nonexistent/clamd.sock").Scan(t.Context(), strings.NewReader("x")), ErrUploadScanFailed)
+	})
+}
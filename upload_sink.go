@@ -0,0 +1,324 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"encoding/hex"
+	stderrors "errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/go-openapi/errors"
+)
+
+const storageNameBytes = 16
+
+// safeExtension matches the file extensions kept in storage names.
+var safeExtension = regexp.MustCompile(`^\.[A-Za-z0-9]{1,16}$`)
+
+var (
+	errUploadAborted = stderrors.New("upload aborted")
+	errFileTooLarge  = stderrors.New("file too large")
+)
+
+// FileSink stores uploaded files, e.g. in a local directory or an object storage.
+type FileSink interface {
+	// Create starts storing a file. The client-supplied file name and headers are untrusted:
+	// see [UploadStorageName].
+	Create(ctx context.Context, file MultipartFileInfo) (SinkWriter, error)
+
+	// Remove removes a stored file, given its location.
+	Remove(ctx context.Context, location string) error
+}
+
+// SinkWriter writes a file to a [FileSink].
+//
+// Exactly one of Commit or Abort is called once the file has been written, or has failed.
+type SinkWriter interface {
+	io.Writer
+
+	// Commit completes the storage of the file, and returns its location in the sink.
+	Commit() (location string, err error)
+
+	// Abort discards the file.
+	Abort() error
+}
+
+// StoredFile describes a file stored in a [FileSink].
+type StoredFile struct {
+	MultipartFileInfo
+
+	// Location of the file in the sink, e.g. a path for [DirSink].
+	Location string
+
+	// Size of the file, in bytes.
+	Size int64
+
+	// Digest of the file, when enabled with [SaveDigest].
+	Digest []byte
+}
+
+// SaveOption configures how files are saved to a [FileSink].
+type SaveOption func(*saveConfig)
+
+type saveConfig struct {
+	maxFileSize int64
+	algorithm   crypto.Hash
+}
+
+// SaveMaxFileSize rejects files larger than n bytes, with the HTTP status 413 (Request Entity Too Large).
+// A value of 0 means no limit.
+func SaveMaxFileSize(n int64) SaveOption {
+	return func(c *saveConfig) { c.maxFileSize = n }
+}
+
+// SaveDigest computes a digest of saved files, with a hash function linked into the binary.
+func SaveDigest(algorithm crypto.Hash) SaveOption {
+	return func(c *saveConfig) { c.algorithm = algorithm }
+}
+
+// SaveTo copies the unread content of the file to a [FileSink].
+//
+// The file is committed to the sink once it has been read to its end. Otherwise, it is aborted,
+// e.g. when exceeding [SaveMaxFileSize], or on an inspection failure (see [MultipartFormStreamInspect]).
+// The file is closed in any case.
+func (f *StreamedFile) SaveTo(ctx context.Context, sink FileSink, opts ...SaveOption) (StoredFile, error) {
+	var cfg saveConfig
+	for _, apply := range opts {
+		apply(&cfg)
+	}
+
+	stored := StoredFile{
+		MultipartFileInfo: MultipartFileInfo{
+			FieldName: f.FieldName,
+			Filename:  f.Filename,
+			Header:    cloneMultipartMIMEHeader(f.Header),
+		},
+	}
+
+	w, err := sink.Create(ctx, stored.MultipartFileInfo)
+	if err != nil {
+		return stored, stderrors.Join(err, f.Close())
+	}
+
+	size, digest, err := copyToSink(ctx, w, f, cfg)
+	if err != nil {
+		if stderrors.Is(err, errFileTooLarge) {
+			err = errors.New(http.StatusRequestEntityTooLarge,
+				"formData %s: file %q exceeds %d bytes", f.FieldName, f.Filename, cfg.maxFileSize)
+		}
+
+		return stored, stderrors.Join(err, w.Abort(), f.Close())
+	}
+
+	location, err := w.Commit()
+	if err != nil {
+		return stored, stderrors.Join(err, f.Close())
+	}
+
+	stored.Location = location
+	stored.Size = size
+	stored.Digest = digest
+
+	return stored, f.Close()
+}
+
+// SaveFiles saves all the remaining file parts of the stream to a [FileSink], then closes the stream.
+//
+// Form fields encountered on the way are collected as with [MultipartFormStream.NextFile].
+//
+// If any file fails, the files already saved are removed from the sink, and the stream is closed.
+func (s *MultipartFormStream) SaveFiles(sink FileSink, opts ...SaveOption) ([]StoredFile, error) {
+	ctx := context.Background()
+	if s != nil && s.request != nil {
+		ctx = s.request.Context()
+	}
+
+	var stored []StoredFile
+	cleanup := func(err error) error {
+		errs := []error{err}
+		for _, file := range stored {
+			errs = append(errs, sink.Remove(context.WithoutCancel(ctx), file.Location))
+		}
+
+		return stderrors.Join(append(errs, s.Close())...)
+	}
+
+	for {
+		file, err := s.NextFile()
+		if stderrors.Is(err, io.EOF) {
+			return stored, s.Close()
+		}
+		if err != nil {
+			return nil, cleanup(err)
+		}
+
+		saved, err := file.SaveTo(ctx, sink, opts...)
+		if err != nil {
+			return nil, cleanup(err)
+		}
+		stored = append(stored, saved)
+	}
+}
+
+func copyToSink(ctx context.Context, w io.Writer, r io.Reader, cfg saveConfig) (int64, []byte, error) {
+	if cfg.maxFileSize > 0 {
+		r = io.LimitReader(r, cfg.maxFileSize+1)
+	}
+
+	var h hash.Hash
+	if cfg.algorithm != 0 {
+		h = cfg.algorithm.New()
+		w = io.MultiWriter(w, h)
+	}
+
+	size, err := io.Copy(w, &contextReader{ctx: ctx, Reader: r})
+	if err != nil {
+		return size, nil, err
+	}
+
+	if cfg.maxFileSize > 0 && size > cfg.maxFileSize {
+		return size, nil, errFileTooLarge
+	}
+
+	if h == nil {
+		return size, nil, nil
+	}
+
+	return size, h.Sum(nil), nil
+}
+
+type contextReader struct {
+	io.Reader
+
+	ctx context.Context //nolint:containedctx // Read has no context parameter, so the wrapper must retain it
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return r.Reader.Read(p)
+}
+
+// UploadStorageName returns a random name to store an uploaded file, which only keeps the
+// extension of the client-supplied file name when it is made of letters and digits (e.g. ".pdf").
+//
+// Client-supplied file names are never safe to use as paths or keys.
+func UploadStorageName(file MultipartFileInfo) string {
+	var buf [storageNameBytes]byte
+	_, _ = rand.Read(buf[:])
+
+	name := hex.EncodeToString(buf[:])
+	if ext := filepath.Ext(file.Filename); safeExtension.MatchString(ext) {
+		name += ext
+	}
+
+	return name
+}
+
+// DirSink creates a [FileSink] which stores files in a local directory.
+//
+// Each file is written to a temporary file in the directory, which is renamed once complete.
+// Files are named with [UploadStorageName], and their location is their path.
+func DirSink(dir string) FileSink {
+	return dirSink(dir)
+}
+
+type dirSink string
+
+func (d dirSink) Create(_ context.Context, file MultipartFileInfo) (SinkWriter, error) {
+	tmp, err := os.CreateTemp(string(d), ".upload-*")
+	if err != nil {
+		return nil, err
+	}
+
+	return &dirSinkWriter{
+		File: tmp,
+		path: filepath.Join(string(d), UploadStorageName(file)),
+	}, nil
+}
+
+func (d dirSink) Remove(_ context.Context, location string) error {
+	if filepath.Dir(location) != filepath.Clean(string(d)) {
+		return fmt.Errorf("cannot remove %q: not in directory %q", location, string(d))
+	}
+
+	return os.Remove(location)
+}
+
+type dirSinkWriter struct {
+	*os.File
+
+	path string
+}
+
+func (w *dirSinkWriter) Commit() (string, error) {
+	if err := w.Sync(); err != nil {
+		return "", stderrors.Join(err, w.Abort())
+	}
+
+	if err := w.Close(); err != nil {
+		return "", stderrors.Join(err, os.Remove(w.Name()))
+	}
+
+	if err := os.Rename(w.Name(), w.path); err != nil {
+		return "", stderrors.Join(err, os.Remove(w.Name()))
+	}
+
+	return w.path, nil
+}
+
+func (w *dirSinkWriter) Abort() error {
+	return stderrors.Join(w.Close(), os.Remove(w.Name()))
+}
+
+// WriterSink creates a [FileSink] which writes files to the writers returned by a factory.
+//
+// Committing a file closes its writer. Aborting a file closes its writer with an error when
+// it supports CloseWithError (like [io.PipeWriter]), and closes it otherwise.
+// Stored files have no location, and cannot be removed by the sink.
+func WriterSink(create func(ctx context.Context, file MultipartFileInfo) (io.WriteCloser, error)) FileSink {
+	return writerSink(create)
+}
+
+type writerSink func(ctx context.Context, file MultipartFileInfo) (io.WriteCloser, error)
+
+func (f writerSink) Create(ctx context.Context, file MultipartFileInfo) (SinkWriter, error) {
+	w, err := f(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	return writerSinkWriter{WriteCloser: w}, nil
+}
+
+func (writerSink) Remove(context.Context, string) error {
+	return nil
+}
+
+type writerSinkWriter struct {
+	io.WriteCloser
+}
+
+func (w writerSinkWriter) Commit() (string, error) {
+	return "", w.Close()
+}
+
+func (w writerSinkWriter) Abort() error {
+	if closer, ok := w.WriteCloser.(interface{ CloseWithError(error) error }); ok {
+		return closer.CloseWithError(errUploadAborted)
+	}
+
+	return w.Close()
+}
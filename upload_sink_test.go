@@ -0,0 +1,215 @@
+// SPDX-FileCopyrightText: Copyright 2015-2026 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/sha256"
+	stderrors "errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func newSinkStream(t *testing.T, parts ...orderedMultipartPart) *MultipartFormStream {
+	t.Helper()
+
+	body, contentType := orderedMultipartBody(t, parts...)
+	request := httptest.NewRequestWithContext(t.Context(), http.MethodPost, testUploadPath, body)
+	request.Header.Set(HeaderContentType, contentType)
+
+	stream, err := NewMultipartFormStream(request)
+	require.NoError(t, err)
+
+	return stream
+}
+
+func dirEntries(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	return names
+}
+
+func TestDirSink(t *testing.T) {
+	t.Run("should save files and collect trailing fields", func(t *testing.T) {
+		dir := t.TempDir()
+		stream := newSinkStream(t,
+			orderedFile{field: testFieldFile1, filename: testFileFieldA, content: "AAA"},
+			orderedFile{field: testFieldFile2, filename: "../../etc/passwd", content: "BBBB"},
+			orderedField{name: "after", value: "three"},
+		)
+
+		stored, err := stream.SaveFiles(DirSink(dir), SaveDigest(crypto.SHA256))
+		require.NoError(t, err)
+		require.Len(t, stored, 2)
+		assert.EqualT(t, "three", stream.Fields().Get("after"))
+
+		for i, content := range []string{"AAA", "BBBB"} {
+			file := stored[i]
+			assert.EqualT(t, dir, filepath.Dir(file.Location))
+			assert.EqualT(t, int64(len(content)), file.Size)
+			sum := sha256.Sum256([]byte(content))
+			assert.Equal(t, sum[:], file.Digest)
+
+			saved, err := os.ReadFile(file.Location)
+			require.NoError(t, err)
+			assert.EqualT(t, content, string(saved))
+		}
+		assert.EqualT(t, testFieldFile1, stored[0].FieldName)
+		assert.EqualT(t, filepath.Ext(testFileFieldA), filepath.Ext(stored[0].Location))
+		assert.EqualT(t, "passwd", stored[1].Filename)
+
+		// no temporary file is left behind
+		assert.Len(t, dirEntries(t, dir), 2)
+	})
+
+	t.Run("should remove saved files when a file exceeds the size limit", func(t *testing.T) {
+		dir := t.TempDir()
+		stream := newSinkStream(t,
+			orderedFile{field: testFieldFile1, filename: testFileFieldA, content: "AAA"},
+			orderedFile{field: testFieldFile2, filename: testFileFieldB, content: "BBBBBBBB"},
+		)
+
+		stored, err := stream.SaveFiles(DirSink(dir), SaveMaxFileSize(4))
+		require.Error(t, err)
+		assert.Empty(t, stored)
+
+		var coded errors.Error
+		require.ErrorAs(t, err, &coded)
+		assert.EqualT(t, int32(http.StatusRequestEntityTooLarge), coded.Code())
+		assert.StringContainsT(t, err.Error(), testFieldFile2)
+		assert.Empty(t, dirEntries(t, dir))
+	})
+
+	t.Run("should abort a file when the request is canceled", func(t *testing.T) {
+		dir := t.TempDir()
+		stream := newSinkStream(t,
+			orderedFile{field: testFieldFile, filename: streamedFilename, content: "payload"},
+		)
+		file, err := stream.NextFile()
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		_, err = file.SaveTo(ctx, DirSink(dir))
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, dirEntries(t, dir))
+		require.NoError(t, stream.Close())
+	})
+
+	t.Run("should only remove files from its directory", func(t *testing.T) {
+		dir := t.TempDir()
+		outside := filepath.Join(t.TempDir(), "outside")
+		require.NoError(t, os.WriteFile(outside, []byte("keep"), 0o600))
+
+		require.Error(t, DirSink(dir).Remove(t.Context(), outside))
+		require.Error(t, DirSink(dir).Remove(t.Context(), filepath.Join(dir, "..", filepath.Base(outside))))
+		_, err := os.Stat(outside)
+		require.NoError(t, err)
+	})
+}
+
+func TestWriterSink(t *testing.T) {
+	t.Run("should write and close files", func(t *testing.T) {
+		var buffers []*closingBuffer
+		sink := WriterSink(func(_ context.Context, file MultipartFileInfo) (io.WriteCloser, error) {
+			assert.EqualT(t, testFieldFile, file.FieldName)
+			buffer := &closingBuffer{}
+			buffers = append(buffers, buffer)
+
+			return buffer, nil
+		})
+
+		stream := newSinkStream(t,
+			orderedFile{field: testFieldFile, filename: streamedFilename, content: "payload"},
+		)
+		stored, err := stream.SaveFiles(sink)
+		require.NoError(t, err)
+		require.Len(t, stored, 1)
+		assert.Empty(t, stored[0].Location)
+		require.Len(t, buffers, 1)
+		assert.EqualT(t, "payload", buffers[0].String())
+		assert.TrueT(t, buffers[0].closed)
+	})
+
+	t.Run("should close aborted pipes with an error", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		sink := WriterSink(func(context.Context, MultipartFileInfo) (io.WriteCloser, error) {
+			return pw, nil
+		})
+
+		received := make(chan error, 1)
+		go func() {
+			_, err := io.Copy(io.Discard, pr)
+			received <- err
+		}()
+
+		stream := newSinkStream(t,
+			orderedFile{field: testFieldFile, filename: streamedFilename, content: "too large"},
+		)
+		_, err := stream.SaveFiles(sink, SaveMaxFileSize(2))
+		require.Error(t, err)
+		require.ErrorIs(t, <-received, errUploadAborted)
+	})
+
+	t.Run("should report factory errors", func(t *testing.T) {
+		errFactory := stderrors.New("no writer")
+		sink := WriterSink(func(context.Context, MultipartFileInfo) (io.WriteCloser, error) {
+			return nil, errFactory
+		})
+
+		stream := newSinkStream(t,
+			orderedFile{field: testFieldFile, filename: streamedFilename, content: "payload"},
+		)
+		_, err := stream.SaveFiles(sink)
+		require.ErrorIs(t, err, errFactory)
+	})
+}
+
+func TestUploadStorageName(t *testing.T) {
+	for filename, ext := range map[string]string{
+		"report.pdf":       ".pdf",
+		"archive.tar.gz":   ".gz",
+		"../../etc/passwd": "",
+		"no extension":     "",
+		"bad.ext/../x":     "",
+		"script.p h p":     "",
+		"":                 "",
+	} {
+		name := UploadStorageName(MultipartFileInfo{Filename: filename})
+		assert.EqualT(t, ext, filepath.Ext(name), filename)
+		assert.EqualT(t, filepath.Base(name), name, filename)
+	}
+
+	assert.NotEqual(t, UploadStorageName(MultipartFileInfo{}), UploadStorageName(MultipartFileInfo{}))
+}
+
+type closingBuffer struct {
+	bytes.Buffer
+
+	closed bool
+}
+
+func (b *closingBuffer) Close() error {
+	b.closed = true
+
+	return nil
+}